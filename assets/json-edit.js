@@ -13,10 +13,24 @@ function addProperty(button) {
     nameLabel.textContent = 'Property Name:';
     nameLabel.className = 'name-label';
 
-    const nameInput = document.createElement('input');
-    nameInput.type = 'text';
-    nameInput.placeholder = 'Enter property name';
-    nameInput.className = 'input-field';
+    // Only the properties defined by the schema are offered if the form is driven by a schema
+    const definedProperties = button.hasAttribute('data-properties') ? JSON.parse(button.getAttribute('data-properties')) : null;
+    let nameInput;
+    if (definedProperties) {
+        nameInput = document.createElement('select');
+        nameInput.className = 'select-field';
+        definedProperties.forEach(name => {
+            const option = document.createElement('option');
+            option.value = name;
+            option.textContent = name;
+            nameInput.appendChild(option);
+        });
+    } else {
+        nameInput = document.createElement('input');
+        nameInput.type = 'text';
+        nameInput.placeholder = 'Enter property name';
+        nameInput.className = 'input-field';
+    }
 
     // Create input for property value
     const valueLabel = document.createElement('label');
@@ -74,123 +88,13 @@ function addProperty(button) {
             return;
         }
 
-        // Create the full path for the new property
-        let fullPath = path ? path + '.' + propertyName : propertyName;
-
-        let value;
-
-        // Handle different types
-        switch(propertyType) {
-            case 'string':
-                value = propertyValue;
-                break;
-            case 'number':
-                value = parseFloat(propertyValue) || 0;
-                break;
-            case 'boolean':
-                value = propertyValue.toLowerCase() === 'true';
-                break;
-            case 'null':
-                value = null;
-                break;
-            case 'object':
-                value = {};
-                break;
-            case 'array':
-                value = [];
-                break;
+        if (!validValue(propertyType, propertyValue)) {
+            alert('Value must be a number');
+            return;
         }
 
-        if (propertyType === 'object' || propertyType === 'array') {
-            // For objects and arrays, we'll add a nested structure
-            const newField = document.createElement('div');
-            newField.className = 'json-field';
-            newField.style.marginLeft = (indent * 20) + 'px';
-
-            const newLabel = document.createElement('label');
-            newLabel.setAttribute('for', fullPath);
-            newLabel.textContent = propertyName + ':';
-
-            // Create delete button
-            const deleteButton = document.createElement('button');
-            deleteButton.type = 'button';
-            deleteButton.textContent = '×';
-            deleteButton.className = 'delete-property-btn';
-            deleteButton.setAttribute('data-path', path);
-            deleteButton.setAttribute('data-key', propertyName);
-            deleteButton.onclick = function() {
-                deleteProperty(this);
-            };
-
-            newField.appendChild(deleteButton);
-            newField.appendChild(newLabel);
-            newField.appendChild(document.createElement('div')); // Placeholder
-
-            // Insert the new field before the add property button
-            button.parentNode.parentNode.insertBefore(newField, button.parentNode);
-
-            // Get the JSON content and update it
-            const jsonContent = document.getElementById('jsonContent');
-            const jsonData = JSON.parse(jsonContent.value);
-
-            // Parse the path to access the parent object
-            const pathParts = parsePath(path);
-
-            // Set the new property in the JSON data
-            let parent = jsonData;
-            for (const part of pathParts) {
-                if (parent[part] === undefined) {
-                    parent[part] = {};
-                }
-                parent = parent[part];
-            }
-            parent[propertyName] = value;
-
-            // Update the JSON content
-            jsonContent.value = JSON.stringify(jsonData, null, 4);
-
-            // Use HTMX to update the main div without page reload
-            htmx.ajax('POST', '/edit', {
-                target: '#main',
-                swap: 'innerHTML',
-                values: {
-                    jsonContent: jsonContent.value
-                }
-            });
-        } else {
-            // For primitive types, add a simple input field
-            const newField = document.createElement('div');
-            newField.className = 'json-field';
-            newField.style.marginLeft = (indent * 20) + 'px';
-
-            const newLabel = document.createElement('label');
-            newLabel.setAttribute('for', fullPath);
-            newLabel.textContent = propertyName + ':';
-
-            const newInput = document.createElement('input');
-            newInput.type = 'text';
-            newInput.name = fullPath;
-            newInput.id = fullPath;
-            newInput.value = propertyValue;
-
-            // Create delete button
-            const deleteButton = document.createElement('button');
-            deleteButton.type = 'button';
-            deleteButton.textContent = '×';
-            deleteButton.className = 'delete-property-btn';
-            deleteButton.setAttribute('data-path', path);
-            deleteButton.setAttribute('data-key', propertyName);
-            deleteButton.onclick = function() {
-                deleteProperty(this);
-            };
-
-            newField.appendChild(deleteButton);
-            newField.appendChild(newLabel);
-            newField.appendChild(newInput);
-
-            // Insert the new field before the add property button
-            button.parentNode.parentNode.insertBefore(newField, button.parentNode);
-        }
+        // Add the property to the document and render the form again
+        renderEditForm([{ op: 'add', path: path, key: propertyName, type: propertyType, value: propertyValue }]);
 
         // Remove the property form
         propertyForm.remove();
@@ -223,31 +127,6 @@ function addArrayItem(button) {
     const path = button.getAttribute('data-path');
     const indent = parseInt(button.getAttribute('data-indent'));
 
-    // Get the current array length
-    const jsonContent = document.getElementById('jsonContent');
-    const jsonData = JSON.parse(jsonContent.value);
-
-    // Parse the path to access the array
-    const pathParts = parsePath(path);
-
-    // Get the array from the JSON data
-    let array = jsonData;
-    for (const part of pathParts) {
-        if (array[part] === undefined) {
-            return; // Path not found
-        }
-        array = array[part];
-    }
-
-    // If it's not an array, convert it to one
-    if (!Array.isArray(array)) {
-        array = [];
-        setValueByPath(jsonData, pathParts, array);
-    }
-
-    // Calculate the new index
-    const newIndex = Array.isArray(array) ? array.length : 0;
-
     // Create a form for adding a new item
     const itemForm = document.createElement('div');
     itemForm.className = 'json-field property-form';
@@ -287,105 +166,13 @@ function addArrayItem(button) {
         const itemValue = valueInput.value.trim();
         const itemType = typeSelect.value;
 
-        // Create the full path for the new item
-        let fullPath = path ? path + '[' + newIndex + ']' : '[' + newIndex + ']';
-
-        // Create a new input field for the item
-        const newField = document.createElement('div');
-        newField.className = 'json-field';
-        newField.style.marginLeft = ((indent) * 20) + 'px';
-
-        const newLabel = document.createElement('label');
-        newLabel.setAttribute('for', fullPath);
-        newLabel.textContent = '[' + newIndex + ']:';
-
-        let value;
-
-        // Handle different types
-        switch(itemType) {
-            case 'string':
-                value = itemValue;
-                break;
-            case 'number':
-                value = parseFloat(itemValue) || 0;
-                break;
-            case 'boolean':
-                value = itemValue.toLowerCase() === 'true';
-                break;
-            case 'null':
-                value = null;
-                break;
-            case 'object':
-                value = {};
-                break;
-            case 'array':
-                value = [];
-                break;
+        if (!validValue(itemType, itemValue)) {
+            alert('Value must be a number');
+            return;
         }
 
-        if (itemType === 'object' || itemType === 'array') {
-            // For objects and arrays, we'll add a nested structure
-
-            // Create delete button
-            const deleteButton = document.createElement('button');
-            deleteButton.type = 'button';
-            deleteButton.textContent = '×';
-            deleteButton.className = 'delete-array-item-btn';
-            deleteButton.setAttribute('data-path', path);
-            deleteButton.setAttribute('data-index', newIndex);
-            deleteButton.onclick = function() {
-                deleteArrayItem(this);
-            };
-
-            newField.appendChild(deleteButton);
-            newField.appendChild(newLabel);
-            newField.appendChild(document.createElement('div')); // Placeholder
-
-            // Insert the new field before the add item button
-            button.parentNode.parentNode.insertBefore(newField, button.parentNode);
-
-            // Update the JSON data
-            setValueByPath(jsonData, [...pathParts, newIndex], value);
-            jsonContent.value = JSON.stringify(jsonData, null, 4);
-
-            // Use HTMX to update the main div without page reload
-            htmx.ajax('POST', '/edit', {
-                target: '#main',
-                swap: 'innerHTML',
-                values: {
-                    jsonContent: jsonContent.value
-                }
-            });
-        } else {
-            // For primitive types, add a simple input field
-            const newInput = document.createElement('input');
-            newInput.type = 'text';
-            newInput.name = fullPath;
-            newInput.id = fullPath;
-            newInput.value = itemValue;
-
-            // Create delete button
-            const deleteButton = document.createElement('button');
-            deleteButton.type = 'button';
-            deleteButton.textContent = '×';
-            deleteButton.className = 'delete-array-item-btn';
-            deleteButton.setAttribute('data-path', path);
-            deleteButton.setAttribute('data-index', newIndex);
-            deleteButton.onclick = function() {
-                deleteArrayItem(this);
-            };
-
-            newField.appendChild(deleteButton);
-            newField.appendChild(newLabel);
-            newField.appendChild(newInput);
-
-            // Insert the new field before the add item button
-            button.parentNode.parentNode.insertBefore(newField, button.parentNode);
-
-            // Update the JSON data
-            setValueByPath(jsonData, [...pathParts, newIndex], value);
-            jsonContent.value = JSON.stringify(jsonData, null, 4);
-        }
+        // Append the item to the array, a value that is not an array is replaced by one
+        renderEditForm([{ op: 'append', path: path, type: itemType, value: itemValue }]);
 
         // Remove the item form
         itemForm.remove();
@@ -411,103 +198,251 @@ function addArrayItem(button) {
     button.parentNode.parentNode.insertBefore(itemForm, button.parentNode);
 }
 
-// Function to collect all field values and update the hidden textarea before form submission
+// Function to collect the edited fields into the hidden textarea before form submission
 function updateJSONContent() {
+    document.getElementById('editsContent').value = currentEdits();
+    return true;
+}
+
+// Function to return the fields edited by the user as JSON array of edits. The server applies
+// them to the document, so key order and number literals survive unchanged.
+function currentEdits(extra) {
     const form = document.getElementById('editForm');
-    const inputs = form.querySelectorAll('input[type="text"]');
-    const jsonContent = document.getElementById('jsonContent');
+    const inputs = form.querySelectorAll('#jsonFields input[name], #jsonFields select[name]');
+
+    // Values that do not match their type are kept as in the document
+    const edits = Array.from(inputs)
+        .filter(fieldChanged)
+        .filter(input => validValue(fieldType(input), fieldText(input)))
+        .map(input => ({ op: 'set', path: input.name, type: fieldType(input), value: fieldText(input) }));
+    return JSON.stringify(edits.concat(extra || []));
+}
 
-    // Start with the original JSON content
-    let jsonData = JSON.parse(jsonContent.value);
+// Function to check whether the user changed the value or the type of a form field
+function fieldChanged(field) {
+    const typeSelect = fieldTypeSelect(field);
+    if (typeSelect && selectChanged(typeSelect)) {
+        return true;
+    }
+    if (field.type === 'checkbox') {
+        return field.checked !== field.defaultChecked;
+    }
+    if (field.tagName === 'SELECT') {
+        return selectChanged(field);
+    }
+    return field.value !== field.defaultValue;
+}
 
-    // Update each field value in the JSON data
-    inputs.forEach(input => {
-        const path = input.name;
-        const value = input.value;
+// Function to check whether the user selected another option
+function selectChanged(select) {
+    return Array.from(select.options).some(option => option.selected !== option.defaultSelected);
+}
 
-        // Skip empty paths
-        if (!path) return;
+// Function to return the type selector of a text field, null if the input determines the type
+function fieldTypeSelect(field) {
+    const container = field.closest('.json-field');
+    return container ? container.querySelector('select.type-select') : null;
+}
 
-        // Parse the path to access nested properties
-        const pathParts = parsePath(path);
+// Function to return the JSON type of the value of a form field
+function fieldType(field) {
+    const typeSelect = fieldTypeSelect(field);
+    if (typeSelect) {
+        return typeSelect.value;
+    }
+    if (field.type === 'checkbox') {
+        return 'boolean';
+    }
+    if (field.type === 'number') {
+        return 'number';
+    }
+    if (field.tagName === 'SELECT') {
+        return field.selectedOptions[0].dataset.type || 'string';
+    }
+    return 'string';
+}
 
-        // Update the value in the JSON data
-        setValueByPath(jsonData, pathParts, parseValue(value));
-    });
+// Function to return the text of a form field as sent to the server
+function fieldText(field) {
+    if (field.type === 'checkbox') {
+        return String(field.checked);
+    }
+    return field.value;
+}
 
-    // Update the hidden textarea with the new JSON content
-    jsonContent.value = JSON.stringify(jsonData, null, 4);
-    return true;
+// Function to check whether the text of a value matches the given JSON type, numbers must
+// be JSON number literals so they are kept exactly as entered
+function validValue(type, text) {
+    return type !== 'number' || /^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$/.test(text.trim());
 }
 
-// Function to parse a path string into an array of path parts
-function parsePath(path) {
-    const parts = [];
-    let currentPart = '';
-    let inBracket = false;
-
-    for (let i = 0; i < path.length; i++) {
-        const char = path[i];
-
-        if (char === '.' && !inBracket) {
-            if (currentPart) {
-                parts.push(currentPart);
-                currentPart = '';
-            }
-        } else if (char === '[') {
-            if (currentPart) {
-                parts.push(currentPart);
-                currentPart = '';
-            }
-            inBracket = true;
-        } else if (char === ']') {
-            if (currentPart) {
-                parts.push(parseInt(currentPart));
-                currentPart = '';
-            }
-            inBracket = false;
-        } else {
-            currentPart += char;
-        }
+// Function to confirm saving a document that does not satisfy the attached schema
+function confirmSave() {
+    updateJSONContent();
+    if (!document.querySelector('#validationStatus [data-valid="false"]')) {
+        return true;
     }
-
-    if (currentPart) {
-        parts.push(currentPart);
+    if (document.getElementById('editForm').dataset.blockInvalidSave === 'true') {
+        alert('The document does not satisfy the schema and cannot be saved.');
+        return false;
     }
+    return confirm('The document does not satisfy the schema. Save anyway?');
+}
+
+// Function to attach the selected schema file to the edit session and validate the document
+function attachSchema(input) {
+    if (input.files.length === 0) return;
+    const reader = new FileReader();
+    reader.onload = function() {
+        document.getElementById('schemaContent').value = reader.result;
+        renderEditForm();
+    };
+    reader.readAsText(input.files[0]);
+}
+
+// Function to remove the schema from the edit session
+function detachSchema() {
+    document.getElementById('schemaContent').value = '';
+    document.getElementById('schemaFile').value = '';
+    renderEditForm();
+}
 
-    return parts;
+// Function to render the edit page again with the edited fields and the given extra edits applied,
+// e.g. so the form follows an attached schema or shows a new object or array
+function renderEditForm(extra) {
+    htmx.ajax('POST', '/edit', {
+        target: '#main',
+        swap: 'innerHTML',
+        values: editValues(currentEdits(extra))
+    });
 }
 
-// Function to set a value in a nested object by path
-function setValueByPath(obj, pathParts, value) {
-    if (pathParts.length === 0) return obj;
+// Function to show a marker next to every form field failing validation
+function markViolations() {
+    document.querySelectorAll('#jsonFields .violation-marker').forEach(marker => marker.remove());
+    document.querySelectorAll('#jsonFields .field-error').forEach(field => field.classList.remove('field-error'));
+    document.querySelectorAll('#validationStatus li[data-field]').forEach(item => {
+        const field = findField(item.dataset.field);
+        if (!field) return;
+        const container = field.closest('.json-field') || field;
+        container.classList.add('field-error');
+        const marker = document.createElement('span');
+        marker.className = 'violation-marker';
+        marker.title = item.dataset.message;
+        marker.textContent = '⚠ ' + item.dataset.message;
+        container.appendChild(marker);
+    });
+}
 
-    let current = obj;
-    for (let i = 0; i < pathParts.length - 1; i++) {
-        const part = pathParts[i];
-        if (current[part] === undefined) {
-            current[part] = typeof pathParts[i + 1] === 'number' ? [] : {};
+// Keep the type of a text field and its value consistent while editing
+document.addEventListener('input', function(event) {
+    const field = event.target;
+    if (!field.closest('#jsonFields')) return;
+    if (field.classList.contains('type-select')) {
+        const input = field.closest('.json-field').querySelector('input[name]');
+        if (field.value === 'object' || field.value === 'array') {
+            // Containers are edited with nested fields, render the form again to show them
+            renderEditForm();
+            return;
+        }
+        if (field.value === 'null') {
+            input.value = '';
+        }
+        checkFieldType(input);
+    } else if (field.matches('input[type="text"][name]')) {
+        const typeSelect = fieldTypeSelect(field);
+        if (typeSelect && typeSelect.value === 'null' && field.value !== '') {
+            // Typing into a null value turns it into a string
+            typeSelect.value = 'string';
         }
-        current = current[part];
+        checkFieldType(field);
+    }
+});
+
+// Function to mark a text field whose value does not match its type
+function checkFieldType(input) {
+    input.setCustomValidity(validValue(fieldType(input), fieldText(input)) ? '' : 'Value must be a number');
+}
+
+// Re-validate the document shortly after a field was changed
+let validationTimer;
+document.addEventListener('input', function(event) {
+    if (!event.target.closest('#jsonFields') || !document.getElementById('validationStatus')) return;
+    clearTimeout(validationTimer);
+    validationTimer = setTimeout(() => htmx.trigger('#validationStatus', 'validate'), 500);
+});
+
+// Function to keep the scroll position of both panes of the side-by-side comparison in sync
+function syncScroll(source, targetId) {
+    const target = document.getElementById(targetId);
+    if (!target) return;
+    if (target.scrollTop !== source.scrollTop) {
+        target.scrollTop = source.scrollTop;
+    }
+    if (target.scrollLeft !== source.scrollLeft) {
+        target.scrollLeft = source.scrollLeft;
+    }
+}
+
+// Function to open or close the collapsed block at the same position of the other pane of the side-by-side comparison
+function syncCollapse(details) {
+    const pane = details.closest('.diff-pane');
+    const other = document.getElementById(pane.id === 'diffLeft' ? 'diffRight' : 'diffLeft');
+    const block = other ? other.querySelector(`details[data-block="${details.dataset.block}"]`) : null;
+    if (block && block.open !== details.open) {
+        block.open = details.open;
     }
+}
 
-    current[pathParts[pathParts.length - 1]] = value;
-    return obj;
+// Function to find the input or the json-field element showing the value of the given path
+function findField(path) {
+    let field = document.getElementById(path);
+    if (!field) {
+        const label = document.querySelector(`label[for="${CSS.escape(path)}"]`);
+        field = label ? label.parentElement : null;
+    }
+    if (!field) {
+        field = document.querySelector(`.json-field[data-field="${CSS.escape(path)}"]`);
+    }
+    return field;
 }
 
-// Function to parse a string value to the appropriate type
-function parseValue(value) {
-    if (value === '') return null;
-    if (value === 'true') return true;
-    if (value === 'false') return false;
-    if (value === 'null') return null;
+// Function to highlight the form field of the given path and scroll it into view
+function highlightField(path) {
+    const field = findField(path);
+    if (!field) return;
+    const container = field.closest('.json-field') || field;
+    container.classList.add('field-error');
+    container.scrollIntoView({ block: 'center' });
+    if (field.tagName === 'INPUT') {
+        field.focus();
+    }
+}
 
-    // Try to parse as number
-    const num = Number(value);
-    if (!isNaN(num)) return num;
+// Highlight the field requested by the edit page after it was loaded and mark fields failing validation
+document.addEventListener('htmx:afterSwap', function(event) {
+    if (event.detail.target.id === 'validationStatus') {
+        markViolations();
+        return;
+    }
+    const fields = document.querySelector('#jsonFields[data-highlight]');
+    if (fields) {
+        highlightField(fields.dataset.highlight);
+    }
+});
 
-    // Default to string
-    return value;
+// Function to build the values posted to /edit, keeping the workspace file path if present
+function editValues(edits) {
+    const values = { jsonContent: document.getElementById('jsonContent').value, edits: edits };
+    const filePath = document.querySelector('#editForm input[name="filePath"]');
+    if (filePath) {
+        values.filePath = filePath.value;
+    }
+    const schema = document.getElementById('schemaContent');
+    if (schema && schema.value) {
+        values.schema = schema.value;
+    }
+    return values;
 }
 
 // Function to delete a property from an object
@@ -520,36 +455,8 @@ function deleteProperty(button) {
         return; // User cancelled the operation
     }
 
-    // Get the JSON content
-    const jsonContent = document.getElementById('jsonContent');
-    const jsonData = JSON.parse(jsonContent.value);
-
-    // Parse the path to access the parent object
-    const pathParts = parsePath(path);
-
-    // Get the parent object
-    let parent = jsonData;
-    for (const part of pathParts) {
-        if (parent[part] === undefined) {
-            return; // Path not found
-        }
-        parent = parent[part];
-    }
-
-    // Delete the property
-    delete parent[key];
-
-    // Update the JSON content
-    jsonContent.value = JSON.stringify(jsonData, null, 4);
-
     // Use HTMX to update the main div without page reload
-    htmx.ajax('POST', '/edit', {
-        target: '#main',
-        swap: 'innerHTML',
-        values: {
-            jsonContent: jsonContent.value
-        }
-    });
+    renderEditForm([{ op: 'delete', path: path, key: key }]);
 }
 
 // Function to delete an item from an array
@@ -562,41 +469,8 @@ function deleteArrayItem(button) {
         return; // User cancelled the operation
     }
 
-    // Get the JSON content
-    const jsonContent = document.getElementById('jsonContent');
-    const jsonData = JSON.parse(jsonContent.value);
-
-    // Parse the path to access the parent array
-    const pathParts = parsePath(path);
-
-    // Get the parent array
-    let parent = jsonData;
-    for (const part of pathParts) {
-        if (parent[part] === undefined) {
-            return; // Path not found
-        }
-        parent = parent[part];
-    }
-
-    // Check if parent is an array
-    if (!Array.isArray(parent)) {
-        return; // Not an array
-    }
-
-    // Remove the item at the specified index
-    parent.splice(index, 1);
-
-    // Update the JSON content
-    jsonContent.value = JSON.stringify(jsonData, null, 4);
-
     // Use HTMX to update the main div without page reload
-    htmx.ajax('POST', '/edit', {
-        target: '#main',
-        swap: 'innerHTML',
-        values: {
-            jsonContent: jsonContent.value
-        }
-    });
+    renderEditForm([{ op: 'delete', path: path, index: index }]);
 }
 
 // Function to handle sidebar menu item selection and theme toggle
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/sascha-andres/jsonedit/json"
+	"github.com/sascha-andres/jsonedit/json/codegen"
+	"github.com/sascha-andres/jsonedit/json/fromschema"
+)
+
+// runCodegen implements the codegen subcommand writing Go or TypeScript types for a JSON schema
+// or for the sample documents given as arguments
+func runCodegen(args []string) error {
+	flags := newFlagSet("codegen", "[flags] (--schema schema.json | sample.json...)")
+	language := flags.String("lang", "go", "Language to generate, go or typescript")
+	schemaPath := flags.String("schema", "", "JSON schema file, schemas in its directory can be referenced")
+	rootName := flags.String("root", "", "Name of the root type, the title of the schema or Root by default")
+	packageName := flags.String("package", "models", "Package of generated Go code")
+	output := flags.String("output", "-", "Path to the output file (- for Stdout)")
+	if ok, err := parseFlags(flags, args); !ok {
+		return err
+	}
+
+	lang, err := codegen.ParseLanguage(*language)
+	if err != nil {
+		return err
+	}
+	logger := newCommandLogger()
+	options := []codegen.Option{codegen.WithPackage(*packageName), codegen.WithLogger(logger)}
+	if *rootName != "" {
+		options = append(options, codegen.WithRootName(*rootName))
+	}
+
+	var code string
+	switch {
+	case *schemaPath != "":
+		bundle, err := readSchemaBundle(*schemaPath)
+		if err != nil {
+			return err
+		}
+		parser, err := fromschema.NewSchemaParserFromBundle(logger, bundle)
+		if err != nil {
+			return err
+		}
+		code, err = codegen.FromSchema(parser.Schema(), lang, options...)
+		if err != nil {
+			return err
+		}
+	case flags.NArg() > 0:
+		samples, err := readSamples(flags.Args())
+		if err != nil {
+			return err
+		}
+		code, err = codegen.FromSamples(samples, lang, options...)
+		if err != nil {
+			return err
+		}
+	default:
+		flags.Usage()
+		return errors.New("either --schema or sample documents are required")
+	}
+
+	return writeOutput(*output, []byte(code))
+}
+
+// readSamples parses the sample documents, .ndjson and .jsonl files hold one document per line
+// and - reads a document from Stdin
+func readSamples(paths []string) ([]*json.Node, error) {
+	documents, err := readDocuments(paths)
+	if err != nil {
+		return nil, err
+	}
+	samples := make([]*json.Node, 0, len(documents))
+	for _, document := range documents {
+		sample, err := parseDocument(document.Name, document.Content)
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, sample)
+	}
+	return samples, nil
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	stdJson "encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/sascha-andres/jsonedit/json"
+	"github.com/sascha-andres/jsonedit/json/compare"
+)
+
+// stringList is a flag that may be given multiple times
+type stringList []string
+
+// String joins the values
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+// Set appends a value
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// runDiff implements the diff subcommand, comparing two documents. It exits with code 1 if they differ.
+// Patch formats describe every difference, so they do not accept the flags filtering the comparison.
+func runDiff(args []string) error {
+	flags := newFlagSet("diff", "[flags] a.json b.json")
+	format := flags.String("format", "text", "output format: text, json, json-patch or merge-patch")
+	arrayKey := flags.String("array-key", "", "match object elements of arrays by this member")
+	unordered := flags.Bool("unordered", false, "match array elements regardless of their position")
+	absolute := flags.Float64("tolerance", 0, "maximum absolute difference of numbers considered equal")
+	relative := flags.Float64("relative-tolerance", 0, "maximum relative difference of numbers considered equal")
+	var ignore stringList
+	flags.Var(&ignore, "ignore", "JSON Pointer of values to ignore, * matches any single token, may be repeated")
+	if ok, err := parseFlags(flags, args); !ok {
+		return err
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return errors.New("two documents are required")
+	}
+	if flags.Arg(0) == "-" && flags.Arg(1) == "-" {
+		return errors.New("only one document can be read from Stdin")
+	}
+	if *format == "json-patch" || *format == "merge-patch" {
+		// Patches describe every difference, the exit code would not match them with filters
+		var filters []string
+		flags.Visit(func(f *flag.Flag) {
+			if f.Name != "format" {
+				filters = append(filters, "--"+f.Name)
+			}
+		})
+		if len(filters) > 0 {
+			return fmt.Errorf("%s cannot be used with --format %s", strings.Join(filters, ", "), *format)
+		}
+	}
+
+	a, err := readDocument(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	b, err := readDocument(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	result := compare.Compare(a, b,
+		compare.WithArrayKey(*arrayKey),
+		compare.WithUnorderedArrays(*unordered),
+		compare.WithIgnore(ignore...),
+		compare.WithTolerance(*absolute, *relative),
+	)
+
+	var output []byte
+	switch *format {
+	case "text":
+		output, err = formatChanges(result.Changes)
+	case "json":
+		output, err = stdJson.MarshalIndent(result, "", indent)
+	case "json-patch":
+		output, err = stdJson.MarshalIndent(compare.JSONPatch(a, b), "", indent)
+	case "merge-patch":
+		var patch *json.Node
+		patch, err = compare.MergePatch(a, b)
+		if err == nil {
+			output, err = patch.Format("", indent)
+		}
+	default:
+		return fmt.Errorf("unknown output format %q", *format)
+	}
+	if err != nil {
+		return err
+	}
+	if *format != "text" {
+		output = append(output, '\n')
+	}
+	if err := writeOutput("-", output); err != nil {
+		return err
+	}
+
+	if !result.Equal() {
+		return errCheckFailed
+	}
+	return nil
+}
+
+// formatChanges writes one line per change, prefixed with + for added, - for removed and ~ for modified values
+func formatChanges(changes []compare.Change) ([]byte, error) {
+	var sb strings.Builder
+	for _, change := range changes {
+		path := change.Path
+		if change.From != "" {
+			path = change.From + " -> " + change.Path
+		}
+		if path == "" {
+			path = "/"
+		}
+		switch change.Type {
+		case compare.Added:
+			value, err := change.New.Format("", "")
+			if err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(&sb, "+ %s: %s\n", path, value)
+		case compare.Removed:
+			value, err := change.Old.Format("", "")
+			if err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(&sb, "- %s: %s\n", path, value)
+		default:
+			oldValue, err := change.Old.Format("", "")
+			if err != nil {
+				return nil, err
+			}
+			newValue, err := change.New.Format("", "")
+			if err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(&sb, "~ %s: %s -> %s\n", path, oldValue, newValue)
+		}
+	}
+	return []byte(sb.String()), nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/sascha-andres/jsonedit/json/flatten"
+)
+
+// runFlatten implements the flatten subcommand, writing the values of a document as key value lines
+// or rebuilding a document from the output of flatten written with the same flags
+func runFlatten(args []string) error {
+	flags := newFlagSet("flatten", "[flags] [document.json]")
+	format := flags.String("format", string(flatten.LinesFormat), "format: lines, csv, properties or env, env cannot be unflattened")
+	keyStyle := flags.String("keys", string(flatten.DottedKeys), "key style: dotted, pointer or bracket")
+	escapeKeys := flags.Bool("escape-keys", false, "escape separators within object keys")
+	quoteStrings := flags.Bool("quote-strings", false, "write strings and null as JSON literals")
+	unflatten := flags.Bool("unflatten", false, "rebuild a document from flattened input")
+	if ok, err := parseFlags(flags, args); !ok {
+		return err
+	}
+	if flags.NArg() > 1 {
+		flags.Usage()
+		return errors.New("at most one document is allowed")
+	}
+	path := "-"
+	if flags.NArg() == 1 {
+		path = flags.Arg(0)
+	}
+
+	content, err := readInput(path)
+	if err != nil {
+		return err
+	}
+
+	options := []flatten.Option{
+		flatten.WithKeyStyle(flatten.KeyStyle(*keyStyle)),
+		flatten.WithEscapedKeys(*escapeKeys),
+		flatten.WithQuotedStrings(*quoteStrings),
+	}
+	if *unflatten {
+		document, err := flatten.UnflattenFormat(content, flatten.Format(*format), options...)
+		if err != nil {
+			return err
+		}
+		output, err := document.Format("", indent)
+		if err != nil {
+			return err
+		}
+		return writeOutput("-", append(output, '\n'))
+	}
+
+	output, err := flatten.Flatten(content, flatten.Format(*format), options...)
+	if err != nil {
+		return err
+	}
+	return writeOutput("-", output)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// runFmt implements the fmt subcommand, formatting documents while keeping the order of their members.
+// With --check it exits with code 1 if any document is not formatted.
+func runFmt(args []string) error {
+	flags := newFlagSet("fmt", "[flags] [document.json...]")
+	outputIndent := flags.String("indent", indent, "indentation of the output")
+	compact := flags.Bool("compact", false, "write compact JSON without whitespace")
+	write := flags.Bool("w", false, "write the result to the files instead of Stdout")
+	check := flags.Bool("check", false, "list documents that are not formatted instead of writing them")
+	if ok, err := parseFlags(flags, args); !ok {
+		return err
+	}
+	if *compact {
+		*outputIndent = ""
+	}
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"-"}
+	}
+	if *write && *check {
+		return errors.New("-w and --check cannot be combined")
+	}
+
+	unformatted := false
+	for _, path := range paths {
+		content, err := readInput(path)
+		if err != nil {
+			return err
+		}
+		document, err := parseDocument(path, content)
+		if err != nil {
+			return err
+		}
+		output, err := document.Format("", *outputIndent)
+		if err != nil {
+			return err
+		}
+		output = append(output, '\n')
+
+		switch {
+		case *check:
+			if !bytes.Equal(content, output) {
+				unformatted = true
+				fmt.Fprintln(stdout, path)
+			}
+		case *write && path != "-":
+			if bytes.Equal(content, output) {
+				continue
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(path, output, info.Mode().Perm()); err != nil {
+				return err
+			}
+		default:
+			if err := writeOutput("-", output); err != nil {
+				return err
+			}
+		}
+	}
+
+	if unformatted {
+		return errCheckFailed
+	}
+	return nil
+}
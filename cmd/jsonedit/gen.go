@@ -0,0 +1,93 @@
+package main
+
+import (
+	stdJson "encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sascha-andres/jsonedit/json/fromschema"
+)
+
+// runGen implements the gen subcommand, generating a minimal document or random documents satisfying a schema
+func runGen(args []string) error {
+	flags := newFlagSet("gen", "--schema schema.json [flags]")
+	schemaPath := flags.String("schema", "", "main schema, schemas in its directory can be referenced")
+	random := flags.Bool("random", false, "generate random documents instead of a minimal document")
+	count := flags.Int("count", 1, "number of random documents")
+	seed := flags.Uint64("seed", 0, "seed for reproducible random documents, 0 uses a random seed")
+	optional := flags.Bool("optional", false, "randomly include optional properties")
+	ndjson := flags.Bool("ndjson", false, "write random documents as newline delimited JSON instead of an array")
+	outputIndent := flags.String("indent", indent, "indentation of the output")
+	var branches stringList
+	flags.Var(&branches, "branch", "oneOf/anyOf branch as location=index, may be repeated")
+	if ok, err := parseFlags(flags, args); !ok {
+		return err
+	}
+	if *schemaPath == "" {
+		flags.Usage()
+		return errors.New("--schema is required")
+	}
+
+	bundle, err := readSchemaBundle(*schemaPath)
+	if err != nil {
+		return err
+	}
+	parser, err := fromschema.NewSchemaParserFromBundle(newCommandLogger(), bundle)
+	if err != nil {
+		return err
+	}
+	for _, value := range branches {
+		separator := strings.LastIndex(value, "=")
+		if separator < 0 {
+			return fmt.Errorf("invalid branch %q", value)
+		}
+		branch, err := strconv.Atoi(value[separator+1:])
+		if err != nil {
+			return fmt.Errorf("invalid branch %q", value)
+		}
+		parser.Choose(value[:separator], branch)
+	}
+
+	if !*random {
+		document, err := parser.CreateEmptyJSONDocument()
+		if err != nil {
+			return err
+		}
+		output, err := stdJson.MarshalIndent(document, "", *outputIndent)
+		if err != nil {
+			return err
+		}
+		return writeOutput("-", append(output, '\n'))
+	}
+
+	if *count < 1 {
+		return errors.New("--count must be at least 1")
+	}
+	options := []fromschema.GenerateOption{fromschema.WithOptionalProperties(*optional)}
+	if *seed != 0 {
+		options = append(options, fromschema.WithSeed(*seed))
+	}
+	documents, err := parser.GenerateDocuments(*count, options...)
+	if err != nil {
+		return err
+	}
+
+	var output []byte
+	if *ndjson {
+		for _, document := range documents {
+			line, err := stdJson.Marshal(document)
+			if err != nil {
+				return err
+			}
+			output = append(append(output, line...), '\n')
+		}
+		return writeOutput("-", output)
+	}
+	output, err = stdJson.MarshalIndent(documents, "", *outputIndent)
+	if err != nil {
+		return err
+	}
+	return writeOutput("-", append(output, '\n'))
+}
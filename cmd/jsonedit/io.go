@@ -0,0 +1,152 @@
+package main
+
+import (
+	"errors"
+	stdflag "flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sascha-andres/jsonedit/json"
+	"github.com/sascha-andres/jsonedit/json/validate"
+)
+
+var (
+	// errCheckFailed is returned by subcommands whose check failed, e.g. documents differ or do not
+	// satisfy the schema. It exits with code 1 without a message, other errors exit with code 2.
+	errCheckFailed = errors.New("check failed")
+
+	// stdin is read by subcommands for the path -
+	stdin io.Reader = os.Stdin
+
+	// stdout receives the output of subcommands
+	stdout io.Writer = os.Stdout
+)
+
+// exitCode returns the exit code for the error returned by a subcommand: 0 without error, 1 if its
+// check failed and 2 for other errors
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, errCheckFailed):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// newFlagSet returns the flags of a subcommand, usage is printed after the Usage: prefix
+func newFlagSet(name, usage string) *stdflag.FlagSet {
+	flags := stdflag.NewFlagSet(name, stdflag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: jsonedit %s %s\n", name, usage)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// parseFlags parses the arguments of a subcommand, help returns false without error
+func parseFlags(flags *stdflag.FlagSet, args []string) (bool, error) {
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, stdflag.ErrHelp) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// newCommandLogger returns a logger writing warnings and errors of subcommands to Stderr
+func newCommandLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+}
+
+// readInput reads the file, - reads Stdin
+func readInput(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(stdin)
+	}
+	return os.ReadFile(path)
+}
+
+// readDocument reads and parses the JSON document in the file, - reads Stdin
+func readDocument(path string) (*json.Node, error) {
+	content, err := readInput(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseDocument(path, content)
+}
+
+// parseDocument parses the JSON document read from the file, errors name the file
+func parseDocument(path string, content []byte) (*json.Node, error) {
+	document, err := json.Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return document, nil
+}
+
+// readDocuments reads the documents of the files, .ndjson and .jsonl files hold one document per line,
+// zip archives are unpacked and - reads a document from Stdin
+func readDocuments(paths []string) ([]validate.Document, error) {
+	documents := make([]validate.Document, 0, len(paths))
+	for _, path := range paths {
+		content, err := readInput(path)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case strings.EqualFold(filepath.Ext(path), ".zip"):
+			archived, err := validate.ReadZip(content)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", path, err)
+			}
+			documents = append(documents, archived...)
+		case validate.IsNDJSON(path):
+			lines, err := validate.ReadNDJSON(path, content)
+			if err != nil {
+				return nil, err
+			}
+			documents = append(documents, lines...)
+		default:
+			documents = append(documents, validate.Document{Name: path, Content: content})
+		}
+	}
+	return documents, nil
+}
+
+// readSchemaBundle returns a bundle with the schema file as main schema and all schemas of its directory,
+// which the main schema can reference. - reads a single schema from Stdin.
+func readSchemaBundle(path string) (*json.SchemaBundle, error) {
+	bundle := json.NewSchemaBundle()
+	if path == "-" {
+		content, err := readInput(path)
+		if err != nil {
+			return nil, err
+		}
+		bundle.Add("schema.json", content)
+		return bundle, nil
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	if err := bundle.AddFS(os.DirFS(filepath.Dir(path)), "."); err != nil {
+		return nil, err
+	}
+	bundle.Main = filepath.Base(path)
+	return bundle, nil
+}
+
+// writeOutput writes the content to the file, - writes to Stdout
+func writeOutput(path string, content []byte) error {
+	if path == "-" {
+		_, err := stdout.Write(content)
+		return err
+	}
+	return os.WriteFile(path, content, 0o644)
+}
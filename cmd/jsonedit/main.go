@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"log/slog"
 	"os"
 	"strings"
@@ -14,14 +15,28 @@ import (
 const appName = "JSON_EDIT"
 
 var (
-	port      = 8080
-	host      = "localhost"
-	indent    = "  "
-	readOnly  = false
-	logLevel  = "info"
-	noBrowser = false
+	port             = 8080
+	host             = "localhost"
+	indent           = "  "
+	readOnly         = false
+	logLevel         = "info"
+	noBrowser        = false
+	root             = ""
+	backup           = false
+	blockInvalidSave = false
 )
 
+// commands maps the names of subcommands to their implementation, without a subcommand the web interface is started
+var commands = map[string]func(args []string) error{
+	"codegen":  runCodegen,
+	"diff":     runDiff,
+	"flatten":  runFlatten,
+	"fmt":      runFmt,
+	"gen":      runGen,
+	"serve":    runServe,
+	"validate": runValidate,
+}
+
 // init initializes command-line flags for the application,
 // setting defaults and descriptions for various configuration options.
 func init() {
@@ -33,10 +48,30 @@ func init() {
 	flag.StringVar(&logLevel, "log-level", logLevel, "Log level (debug, info, warn, error)")
 	flag.BoolVar(&readOnly, "read-only", readOnly, "Read-only mode")
 	flag.BoolVar(&noBrowser, "no-browser", noBrowser, "Do not open browser")
+	flag.StringVar(&root, "root", root, "Workspace directory to open and save files in")
+	flag.BoolVar(&backup, "backup", backup, "Keep a .bak copy when saving a workspace file")
+	flag.BoolVar(&blockInvalidSave, "block-invalid-save", blockInvalidSave, "Refuse to save documents not satisfying the schema attached in the editor")
 }
 
 // main is the entry point of the application, parsing flags and handling any initialization errors during startup.
+// A subcommand given as first argument is run instead of the web interface, it exits with code 1 if
+// its check failed and with code 2 on errors.
 func main() {
+	if len(os.Args) > 1 {
+		if command, ok := commands[os.Args[1]]; ok {
+			err := command(os.Args[2:])
+			code := exitCode(err)
+			if code == 2 {
+				fmt.Fprintln(os.Stderr, "jsonedit:", err)
+			}
+			os.Exit(code)
+		}
+		if !strings.HasPrefix(os.Args[1], "-") {
+			fmt.Fprintf(os.Stderr, "jsonedit: unknown command %q\n", os.Args[1])
+			os.Exit(2)
+		}
+	}
+
 	flag.Parse()
 
 	if err := run(); err != nil {
@@ -44,6 +79,13 @@ func main() {
 	}
 }
 
+// runServe implements the serve subcommand, starting the web interface like running jsonedit without subcommand
+func runServe(args []string) error {
+	os.Args = append(os.Args[:1], args...)
+	flag.Parse()
+	return run()
+}
+
 // run initializes the application with configurations and starts the server,
 // returning an error if initialization fails.
 func run() error {
@@ -60,6 +102,9 @@ func run() error {
 		jsonedit.WithLogger(logger),
 		jsonedit.WithDebug(logLevel == "debug"),
 		jsonedit.WithNoBrowser(noBrowser),
+		jsonedit.WithRoot(root),
+		jsonedit.WithBackup(backup),
+		jsonedit.WithBlockInvalidSave(blockInvalidSave),
 	)
 	if err != nil {
 		return err
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sascha-andres/jsonedit/json"
+	"github.com/sascha-andres/jsonedit/json/compare"
+	"github.com/sascha-andres/jsonedit/json/validate"
+)
+
+// runCommand runs the subcommand with input as Stdin and returns what it wrote to Stdout
+func runCommand(t *testing.T, command func(args []string) error, input string, args ...string) (string, error) {
+	t.Helper()
+	var output bytes.Buffer
+	stdin, stdout = strings.NewReader(input), &output
+	t.Cleanup(func() {
+		stdin, stdout = os.Stdin, os.Stdout
+	})
+	err := command(args)
+	return output.String(), err
+}
+
+// writeFiles writes the files to a temporary directory and returns its path
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "success", err: nil, want: 0},
+		{name: "check failed", err: errCheckFailed, want: 1},
+		{name: "wrapped check failed", err: fmt.Errorf("a.json: %w", errCheckFailed), want: 1},
+		{name: "error", err: errors.New("no such file"), want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(tt.err); got != tt.want {
+				t.Errorf("exitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunDiff(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a.json": `{"a": 1, "updatedAt": "monday"}`,
+		"b.json": `{"a": 2, "updatedAt": "tuesday"}`,
+		"c.json": `{"a": 1.0005, "updatedAt": "monday"}`,
+	})
+	a, b, c := filepath.Join(dir, "a.json"), filepath.Join(dir, "b.json"), filepath.Join(dir, "c.json")
+
+	tests := []struct {
+		name  string
+		input string
+		args  []string
+		want  string
+		code  int
+	}{
+		{name: "equal", args: []string{a, a}, want: "", code: 0},
+		{name: "different", args: []string{a, b}, want: "~ /a: 1 -> 2\n~ /updatedAt: \"monday\" -> \"tuesday\"\n", code: 1},
+		{name: "ignored", args: []string{"--ignore", "/updatedAt", "--ignore", "/a", a, b}, want: "", code: 0},
+		{name: "tolerance", args: []string{"--tolerance", "0.001", a, c}, want: "", code: 0},
+		{name: "stdin", input: `{"a": 1, "updatedAt": "monday"}`, args: []string{"-", a}, want: "", code: 0},
+		{name: "json patch", args: []string{"--format", "json-patch", a, c}, want: "[\n  {\n    \"op\": \"replace\",\n    \"path\": \"/a\",\n    \"value\": 1.0005\n  }\n]\n", code: 1},
+		{name: "json patch with filter", args: []string{"--format", "json-patch", "--ignore", "/a", a, b}, code: 2},
+		{name: "merge patch with tolerance", args: []string{"--format", "merge-patch", "--tolerance", "1", a, b}, code: 2},
+		{name: "both from stdin", args: []string{"-", "-"}, code: 2},
+		{name: "one document", args: []string{a}, code: 2},
+		{name: "missing file", args: []string{a, filepath.Join(dir, "missing.json")}, code: 2},
+		{name: "unknown format", args: []string{"--format", "yaml", a, b}, code: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := runCommand(t, runDiff, tt.input, tt.args...)
+			if code := exitCode(err); code != tt.code {
+				t.Fatalf("runDiff() error = %v, exit code %d, want %d", err, code, tt.code)
+			}
+			if tt.code != 2 && got != tt.want {
+				t.Errorf("runDiff() output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatChanges(t *testing.T) {
+	changes := []compare.Change{
+		{Type: compare.Added, Path: "/tags/1", New: json.NewString("new")},
+		{Type: compare.Removed, Path: "/name", Old: json.NewString("old")},
+		{Type: compare.Modified, Path: "/items/0/price", From: "/items/2/price", Old: json.NewNumber("1.50"), New: json.NewNumber("2")},
+		{Type: compare.TypeChanged, Path: "", Old: json.NewObject(), New: json.NewArray()},
+	}
+	want := "+ /tags/1: \"new\"\n" +
+		"- /name: \"old\"\n" +
+		"~ /items/2/price -> /items/0/price: 1.50 -> 2\n" +
+		"~ /: {} -> []\n"
+
+	got, err := formatChanges(changes)
+	if err != nil {
+		t.Fatalf("formatChanges() error = %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("formatChanges() = %q, want %q", got, want)
+	}
+}
+
+func TestRunFmt(t *testing.T) {
+	formatted := "{\n  \"b\": 1,\n  \"a\": [\n    true\n  ]\n}\n"
+	unformatted := `{"b":1,"a":[true]}`
+
+	tests := []struct {
+		name  string
+		files map[string]string
+		input string
+		args  []string
+		want  string
+		code  int
+
+		// written is the expected content of written.json after the command
+		written string
+	}{
+		{name: "stdin", input: unformatted, want: formatted, code: 0},
+		{name: "compact", input: formatted, args: []string{"--compact"}, want: unformatted + "\n", code: 0},
+		{name: "check formatted", files: map[string]string{"a.json": formatted}, args: []string{"--check", "a.json"}, want: "", code: 0},
+		{name: "check unformatted", files: map[string]string{"a.json": formatted, "b.json": unformatted}, args: []string{"--check", "a.json", "b.json"}, want: "b.json\n", code: 1},
+		{name: "write", files: map[string]string{"written.json": unformatted}, args: []string{"-w", "written.json"}, want: "", code: 0, written: formatted},
+		{name: "write and check", files: map[string]string{"a.json": formatted}, args: []string{"-w", "--check", "a.json"}, code: 2},
+		{name: "invalid document", input: `{"a":`, code: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Files are given relative to the directory holding them
+			t.Chdir(writeFiles(t, tt.files))
+
+			got, err := runCommand(t, runFmt, tt.input, tt.args...)
+			if code := exitCode(err); code != tt.code {
+				t.Fatalf("runFmt() error = %v, exit code %d, want %d", err, code, tt.code)
+			}
+			if tt.code != 2 && got != tt.want {
+				t.Errorf("runFmt() output = %q, want %q", got, tt.want)
+			}
+			if tt.written != "" {
+				content, _ := os.ReadFile("written.json")
+				if string(content) != tt.written {
+					t.Errorf("runFmt() wrote %q, want %q", content, tt.written)
+				}
+			}
+		})
+	}
+}
+
+func TestRunValidate(t *testing.T) {
+	schema := `{"type": "object", "properties": {"name": {"type": "string"}}, "required": ["name"]}`
+	dir := writeFiles(t, map[string]string{
+		"schema.json":  schema,
+		"valid.json":   `{"name": "a"}`,
+		"invalid.json": `{}`,
+	})
+	schemaPath, valid, invalid := filepath.Join(dir, "schema.json"), filepath.Join(dir, "valid.json"), filepath.Join(dir, "invalid.json")
+
+	tests := []struct {
+		name  string
+		input string
+		args  []string
+		want  string
+		code  int
+	}{
+		{name: "valid", args: []string{"--schema", schemaPath, valid}, want: valid + ": valid\n1 document(s), 1 passed, 0 failed\n", code: 0},
+		{name: "invalid", args: []string{"--schema", schemaPath, valid, invalid}, want: valid + ": valid\n" + invalid + ": invalid\n  /: missing property 'name' (required)\n2 document(s), 1 passed, 1 failed\n", code: 1},
+		{name: "document from stdin", input: `{"name": "a"}`, args: []string{"--schema", schemaPath}, want: "-: valid\n1 document(s), 1 passed, 0 failed\n", code: 0},
+		{name: "schema from stdin", input: schema, args: []string{"--schema", "-", invalid}, want: invalid + ": invalid\n  /: missing property 'name' (required)\n1 document(s), 0 passed, 1 failed\n", code: 1},
+		{name: "schema and document from stdin", input: schema, args: []string{"--schema", "-", valid, "-"}, code: 2},
+		{name: "missing schema", args: []string{valid}, code: 2},
+		{name: "unknown format", args: []string{"--schema", schemaPath, "--format", "xml", valid}, code: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := runCommand(t, runValidate, tt.input, tt.args...)
+			if code := exitCode(err); code != tt.code {
+				t.Fatalf("runValidate() error = %v, exit code %d, want %d", err, code, tt.code)
+			}
+			if tt.code != 2 && got != tt.want {
+				t.Errorf("runValidate() output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatBatchResult(t *testing.T) {
+	result := &validate.BatchResult{
+		Total:  3,
+		Passed: 1,
+		Failed: 2,
+		Documents: []validate.DocumentResult{
+			{Name: "a.json", Valid: true},
+			{Name: "b.json", Violations: []validate.Violation{
+				{InstanceLocation: "", Message: "missing property 'name'", Keyword: "required"},
+				{InstanceLocation: "/age", Message: "got string, want integer", Keyword: "type"},
+			}},
+			{Name: "c.json", Error: "invalid JSON document"},
+		},
+	}
+	want := "a.json: valid\n" +
+		"b.json: invalid\n" +
+		"  /: missing property 'name' (required)\n" +
+		"  /age: got string, want integer (type)\n" +
+		"c.json: error: invalid JSON document\n" +
+		"3 document(s), 1 passed, 2 failed\n"
+
+	if got := string(formatBatchResult(result)); got != want {
+		t.Errorf("formatBatchResult() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	stdJson "encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/sascha-andres/jsonedit/json/validate"
+)
+
+// runValidate implements the validate subcommand, validating documents against a schema.
+// It exits with code 1 if any document is invalid.
+func runValidate(args []string) error {
+	flags := newFlagSet("validate", "--schema schema.json [flags] [document.json|documents.ndjson|documents.zip...]")
+	schemaPath := flags.String("schema", "", "main schema, schemas in its directory can be referenced")
+	draft := flags.String("draft", "", "draft used for schemas without $schema, e.g. 2020-12")
+	assertFormat := flags.Bool("assert-format", false, "fail on values not matching their format")
+	assertContent := flags.Bool("assert-content", false, "fail on values not matching their contentEncoding and contentMediaType")
+	format := flags.String("format", "text", "output format: text, json or junit")
+	if ok, err := parseFlags(flags, args); !ok {
+		return err
+	}
+	if *schemaPath == "" {
+		flags.Usage()
+		return errors.New("--schema is required")
+	}
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"-"}
+	}
+	if *schemaPath == "-" && slices.Contains(paths, "-") {
+		return errors.New("only one of schema and documents can be read from Stdin")
+	}
+
+	bundle, err := readSchemaBundle(*schemaPath)
+	if err != nil {
+		return err
+	}
+	validator, err := validate.NewJSONValidator(
+		validate.WithLogger(newCommandLogger()),
+		validate.WithJSONSchemaBundle(bundle),
+		validate.WithDefaultDraft(*draft),
+		validate.WithFormatAssertion(*assertFormat),
+		validate.WithContentAssertion(*assertContent),
+	)
+	if err != nil {
+		return err
+	}
+	documents, err := readDocuments(paths)
+	if err != nil {
+		return err
+	}
+	result := validator.ValidateBatch(documents)
+
+	var output []byte
+	switch *format {
+	case "text":
+		output = formatBatchResult(result)
+	case "json":
+		output, err = stdJson.MarshalIndent(result, "", indent)
+		output = append(output, '\n')
+	case "junit":
+		output, err = result.JUnit(bundle.MainName())
+		output = append(output, '\n')
+	default:
+		return fmt.Errorf("unknown output format %q", *format)
+	}
+	if err != nil {
+		return err
+	}
+	if err := writeOutput("-", output); err != nil {
+		return err
+	}
+
+	if result.Failed > 0 {
+		return errCheckFailed
+	}
+	return nil
+}
+
+// formatBatchResult writes one line per document followed by its violations and a summary
+func formatBatchResult(result *validate.BatchResult) []byte {
+	var sb strings.Builder
+	for _, document := range result.Documents {
+		switch {
+		case document.Error != "":
+			fmt.Fprintf(&sb, "%s: error: %s\n", document.Name, document.Error)
+		case !document.Valid:
+			fmt.Fprintf(&sb, "%s: invalid\n", document.Name)
+			for _, violation := range document.Violations {
+				location := violation.InstanceLocation
+				if location == "" {
+					location = "/"
+				}
+				fmt.Fprintf(&sb, "  %s: %s (%s)\n", location, violation.Message, violation.Keyword)
+			}
+		default:
+			fmt.Fprintf(&sb, "%s: valid\n", document.Name)
+		}
+	}
+	fmt.Fprintf(&sb, "%d document(s), %d passed, %d failed\n", result.Total, result.Passed, result.Failed)
+	return []byte(sb.String())
+}
@@ -0,0 +1,379 @@
+package jsonedit
+
+import (
+	stdJson "encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/sascha-andres/jsonedit/json"
+	"github.com/sascha-andres/jsonedit/json/c2j"
+	"github.com/sascha-andres/jsonedit/json/compare"
+	"github.com/sascha-andres/jsonedit/json/flatten"
+	"github.com/sascha-andres/jsonedit/json/fromschema"
+	"github.com/sascha-andres/jsonedit/json/validate"
+)
+
+// apiMaxBodySize limits the size of request bodies accepted by the API endpoints
+const apiMaxBodySize = 10 << 20 // 10 MB max
+
+type (
+	// apiError is the JSON body returned by the API for every failed request
+	apiError struct {
+
+		// Error contains a human-readable description of the problem
+		Error string `json:"error"`
+	}
+
+	// apiCompareRequest is the body expected by the compare endpoint
+	apiCompareRequest struct {
+
+		// First is the reference document
+		First stdJson.RawMessage `json:"first"`
+
+		// Second is the document compared against the reference
+		Second stdJson.RawMessage `json:"second"`
+
+		// ArrayKey is the member used to match object elements of arrays, empty to match by position
+		ArrayKey string `json:"arrayKey"`
+
+		// UnorderedArrays treats arrays as unordered sets
+		UnorderedArrays bool `json:"unorderedArrays"`
+
+		// Ignore lists JSON Pointer patterns of values not compared, * matches any single token
+		Ignore []string `json:"ignore"`
+
+		// AbsoluteTolerance is the maximum absolute difference of numbers considered equal
+		AbsoluteTolerance float64 `json:"absoluteTolerance"`
+
+		// RelativeTolerance is the maximum relative difference of numbers considered equal
+		RelativeTolerance float64 `json:"relativeTolerance"`
+	}
+
+	// apiCompareResponse is the body returned by the compare endpoint
+	apiCompareResponse struct {
+
+		// Equal is true when both documents are identical
+		Equal bool `json:"equal"`
+
+		// Diff contains the textual difference between both documents
+		Diff string `json:"diff"`
+
+		// Changes lists the structural differences between both documents
+		Changes []compare.Change `json:"changes"`
+
+		// Suppressed is the number of differences hidden by ignore patterns or the numeric tolerance
+		Suppressed int `json:"suppressed"`
+	}
+
+	// apiFlattenResponse is the body returned by the flatten endpoint
+	apiFlattenResponse struct {
+
+		// Lines contains the sorted property:value pairs
+		Lines []string `json:"lines"`
+	}
+
+	// apiValidateRequest is the body expected by the validate endpoint
+	apiValidateRequest struct {
+
+		// Schema is the JSON schema the document is validated against
+		Schema stdJson.RawMessage `json:"schema"`
+
+		// Document is the JSON document to validate
+		Document stdJson.RawMessage `json:"document"`
+
+		// Draft is used for schemas not declaring one with $schema, e.g. draft-07, empty for the default
+		Draft string `json:"draft"`
+
+		// AssertFormat validates format keywords instead of treating them as annotations
+		AssertFormat bool `json:"assertFormat"`
+
+		// AssertContent validates contentEncoding, contentMediaType and contentSchema
+		AssertContent bool `json:"assertContent"`
+	}
+
+	// apiValidateResponse is the body returned by the validate endpoint
+	apiValidateResponse struct {
+
+		// Valid is true when the document satisfies the schema
+		Valid bool `json:"valid"`
+
+		// Errors lists the validation errors, empty if the document is valid
+		Errors []string `json:"errors"`
+
+		// Violations lists the failed schema keywords with their locations, empty if the document is valid
+		Violations []validate.Violation `json:"violations"`
+	}
+
+	// apiCSV2JSONRequest is the body expected by the csv2json endpoint
+	apiCSV2JSONRequest struct {
+
+		// CSV contains the CSV data to convert
+		CSV string `json:"csv"`
+
+		// Mapping contains the mapping configuration
+		Mapping stdJson.RawMessage `json:"mapping"`
+
+		// Array wraps the output in an array
+		Array bool `json:"array"`
+
+		// Named uses the CSV header for mapping column names
+		Named bool `json:"named"`
+
+		// OutputType is one of json, yaml or toml, defaults to json
+		OutputType string `json:"outputType"`
+
+		// Separator is the CSV separator, defaults to a comma
+		Separator string `json:"separator"`
+
+		// NestedPropertyName is the property name used for TOML array output, defaults to data
+		NestedPropertyName string `json:"nestedPropertyName"`
+	}
+)
+
+// handleAPIOpenAPI serves the OpenAPI description of the API
+func (app *App) handleAPIOpenAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		app.writeAPIError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	spec, err := embeddedFiles.ReadFile("assets/openapi.json")
+	if err != nil {
+		app.logger.Error("failed to read openapi description", "err", err)
+		app.writeAPIError(w, http.StatusInternalServerError, "failed to read openapi description")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(spec)))
+	_, err = w.Write(spec)
+	if err != nil {
+		app.logger.Error("failed to write openapi description", "err", err)
+	}
+}
+
+// handleAPICompare compares two JSON documents and returns the difference
+func (app *App) handleAPICompare(w http.ResponseWriter, r *http.Request) {
+	var request apiCompareRequest
+	if !app.readAPIRequest(w, r, &request) {
+		return
+	}
+	if len(request.First) == 0 || len(request.Second) == 0 {
+		app.writeAPIError(w, http.StatusBadRequest, "first and second documents are required")
+		return
+	}
+	if request.AbsoluteTolerance < 0 || request.RelativeTolerance < 0 {
+		app.writeAPIError(w, http.StatusBadRequest, "tolerances must not be negative")
+		return
+	}
+
+	first, err := json.Parse(request.First)
+	if err != nil {
+		app.writeAPIError(w, http.StatusBadRequest, "failed to parse first document: "+err.Error())
+		return
+	}
+	second, err := json.Parse(request.Second)
+	if err != nil {
+		app.writeAPIError(w, http.StatusBadRequest, "failed to parse second document: "+err.Error())
+		return
+	}
+
+	diff, err := compare.GetJSONComparison(first.Interface(), second.Interface(), app.indent)
+	if err != nil {
+		app.logger.Error("failed to compare JSON documents", "err", err)
+		app.writeAPIError(w, http.StatusInternalServerError, "failed to compare JSON documents: "+err.Error())
+		return
+	}
+	result := compare.Compare(first, second,
+		compare.WithArrayKey(request.ArrayKey),
+		compare.WithUnorderedArrays(request.UnorderedArrays),
+		compare.WithIgnore(request.Ignore...),
+		compare.WithTolerance(request.AbsoluteTolerance, request.RelativeTolerance),
+	)
+
+	app.writeAPIResponse(w, http.StatusOK, apiCompareResponse{
+		Equal:      result.Equal(),
+		Diff:       diff,
+		Changes:    result.Changes,
+		Suppressed: result.Suppressed,
+	})
+}
+
+// handleAPIFlatten flattens the JSON document sent as request body
+func (app *App) handleAPIFlatten(w http.ResponseWriter, r *http.Request) {
+	content, ok := app.readAPIBody(w, r)
+	if !ok {
+		return
+	}
+
+	lines, err := flatten.FlattenJSON(content)
+	if err != nil {
+		app.writeAPIError(w, http.StatusBadRequest, "failed to flatten JSON document: "+err.Error())
+		return
+	}
+
+	app.writeAPIResponse(w, http.StatusOK, apiFlattenResponse{Lines: lines})
+}
+
+// handleAPIValidate validates a JSON document against a JSON schema
+func (app *App) handleAPIValidate(w http.ResponseWriter, r *http.Request) {
+	var request apiValidateRequest
+	if !app.readAPIRequest(w, r, &request) {
+		return
+	}
+	if len(request.Schema) == 0 || len(request.Document) == 0 {
+		app.writeAPIError(w, http.StatusBadRequest, "schema and document are required")
+		return
+	}
+
+	validator, err := validate.NewJSONValidator(
+		validate.WithJSONSchema(request.Schema),
+		validate.WithJSONDocument(request.Document),
+		validate.WithDefaultDraft(request.Draft),
+		validate.WithFormatAssertion(request.AssertFormat),
+		validate.WithContentAssertion(request.AssertContent),
+		validate.WithLogger(app.logger.With("module", "validate")),
+	)
+	if err != nil {
+		app.writeAPIError(w, http.StatusUnprocessableEntity, "failed to create JSON validator: "+err.Error())
+		return
+	}
+
+	response := apiValidateResponse{Valid: true, Errors: []string{}, Violations: []validate.Violation{}}
+	if validationErr := validator.Validate(); validationErr != nil {
+		response.Valid = false
+		var violations *validate.ValidationError
+		if !errors.As(validationErr, &violations) {
+			response.Errors = append(response.Errors, validationErr.Error())
+		} else {
+			response.Violations = violations.Violations
+			for _, violation := range violations.Violations {
+				response.Errors = append(response.Errors, fmt.Sprintf("at '%s' [%s]: %s", violation.InstanceLocation, violation.Keyword, violation.Message))
+			}
+		}
+	}
+
+	app.writeAPIResponse(w, http.StatusOK, response)
+}
+
+// handleAPIFromSchema generates a JSON document from the JSON schema sent as request body
+func (app *App) handleAPIFromSchema(w http.ResponseWriter, r *http.Request) {
+	content, ok := app.readAPIBody(w, r)
+	if !ok {
+		return
+	}
+
+	schemaParser, err := fromschema.NewSchemaParser(app.logger.With("module", "from_schema"), content)
+	if err != nil {
+		app.writeAPIError(w, http.StatusUnprocessableEntity, "failed to parse JSON schema: "+err.Error())
+		return
+	}
+
+	jsonData, err := schemaParser.CreateEmptyJSONDocument()
+	if err != nil {
+		app.logger.Error("failed to create empty JSON document", "err", err)
+		app.writeAPIError(w, http.StatusInternalServerError, "failed to create JSON document: "+err.Error())
+		return
+	}
+
+	app.writeAPIResponse(w, http.StatusOK, jsonData)
+}
+
+// handleAPICSV2JSON converts CSV data to JSON, YAML or TOML using a mapping configuration
+func (app *App) handleAPICSV2JSON(w http.ResponseWriter, r *http.Request) {
+	var request apiCSV2JSONRequest
+	if !app.readAPIRequest(w, r, &request) {
+		return
+	}
+	if len(request.Mapping) == 0 {
+		app.writeAPIError(w, http.StatusBadRequest, "mapping is required")
+		return
+	}
+
+	options := c2j.C2JOptions{
+		Array:              request.Array,
+		Named:              request.Named,
+		OutputType:         request.OutputType,
+		NestedPropertyName: request.NestedPropertyName,
+		Separator:          request.Separator,
+		Logger:             app.logger.With("module", "csv2json"),
+	}
+	if options.OutputType == "" {
+		options.OutputType = "json"
+	}
+	if options.Separator == "" {
+		options.Separator = ","
+	}
+	if options.NestedPropertyName == "" {
+		options.NestedPropertyName = "data"
+	}
+
+	result, contentType, err := c2j.MapCSV2JSON(options, []byte(request.CSV), request.Mapping)
+	if err != nil {
+		app.writeAPIError(w, http.StatusUnprocessableEntity, "failed to convert CSV to JSON: "+err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(result)))
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(result)
+	if err != nil {
+		app.logger.Error("failed to write result to response", "err", err)
+	}
+}
+
+// readAPIBody ensures a POST request and reads its body; on failure an error response is written and false returned
+func (app *App) readAPIBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	if r.Method != http.MethodPost {
+		app.writeAPIError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return nil, false
+	}
+	content, err := io.ReadAll(http.MaxBytesReader(w, r.Body, apiMaxBodySize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			app.writeAPIError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return nil, false
+		}
+		app.logger.Error("failed to read request body", "err", err)
+		app.writeAPIError(w, http.StatusBadRequest, "failed to read request body")
+		return nil, false
+	}
+	return content, true
+}
+
+// readAPIRequest reads the request body and decodes it into request; on failure an error response is written and false returned
+func (app *App) readAPIRequest(w http.ResponseWriter, r *http.Request, request interface{}) bool {
+	content, ok := app.readAPIBody(w, r)
+	if !ok {
+		return false
+	}
+	if err := stdJson.Unmarshal(content, request); err != nil {
+		app.writeAPIError(w, http.StatusBadRequest, "failed to parse request body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// writeAPIError writes an error response with the given status code
+func (app *App) writeAPIError(w http.ResponseWriter, status int, message string) {
+	app.writeAPIResponse(w, status, apiError{Error: message})
+}
+
+// writeAPIResponse writes data as indented JSON with the given status code
+func (app *App) writeAPIResponse(w http.ResponseWriter, status int, data interface{}) {
+	content, err := stdJson.MarshalIndent(data, "", app.indent)
+	if err != nil {
+		app.logger.Error("failed to format json data", "err", err)
+		http.Error(w, "Failed to format JSON", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(content)))
+	w.WriteHeader(status)
+	_, err = w.Write(content)
+	if err != nil {
+		app.logger.Error("failed to write json data", "err", err)
+	}
+}
@@ -0,0 +1,123 @@
+package jsonedit
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+
+	"github.com/sascha-andres/jsonedit/json/codegen"
+	"github.com/sascha-andres/jsonedit/json/fromschema"
+)
+
+// handleCodegen generates Go or TypeScript types from a JSON schema or sample documents
+func (app *App) handleCodegen(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		app.renderCodegenResult(w, r)
+	}
+	if r.Method == http.MethodGet {
+		app.renderCodegenForm(w, r)
+	}
+}
+
+// renderCodegenForm renders the code generation form on a separate page
+func (app *App) renderCodegenForm(w http.ResponseWriter, _ *http.Request) {
+	tmpl := template.Must(template.New("codegen").Parse(codegenFormTemplate))
+	err := tmpl.Execute(w, SchemaFormData{Workspace: app.workspace != nil})
+	if err != nil {
+		app.logger.Error("failed to render upload page template", "err", err)
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
+
+// renderCodegenResult generates the types and renders them on a separate page
+func (app *App) renderCodegenResult(w http.ResponseWriter, r *http.Request) {
+	// Parse the multipart form
+	err := r.ParseMultipartForm(10 << 20) // 10 MB max
+	if err != nil {
+		app.logger.Error("failed to parse form", "err", err)
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	language, err := codegen.ParseLanguage(r.FormValue("language"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	options := []codegen.Option{codegen.WithLogger(app.logger.With("module", "codegen"))}
+	if name := r.FormValue("rootName"); name != "" {
+		options = append(options, codegen.WithRootName(name))
+	}
+	if name := r.FormValue("packageName"); name != "" {
+		options = append(options, codegen.WithPackage(name))
+	}
+
+	var code string
+	if r.FormValue("source") == "sample" {
+		samples, err := app.readSamples(r, "sampleFiles", "sampleText")
+		if err != nil {
+			http.Error(w, "Invalid samples: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		code, err = codegen.FromSamples(samples, language, options...)
+		if err != nil {
+			http.Error(w, "Failed to generate types: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	} else {
+		bundle, err := app.readSchemaBundle(r, "schemaFile")
+		if err != nil {
+			app.logger.Error("failed to read schema files", "err", err)
+			http.Error(w, "Failed to read schema files: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		schemaParser, err := fromschema.NewSchemaParserFromBundle(app.logger.With("module", "from_schema"), bundle)
+		if err != nil {
+			app.logger.Error("failed to create schema parser", "err", err)
+			http.Error(w, "Failed to parse JSON schema: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		code, err = codegen.FromSchema(schemaParser.Schema(), language, options...)
+		if err != nil {
+			http.Error(w, "Failed to generate types: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	tmpl := template.Must(template.New("codegen").Parse(codegenResultTemplate))
+	err = tmpl.Execute(w, CodegenResultData{Code: code, Language: string(language)})
+	if err != nil {
+		app.logger.Error("failed to render code generation result template", "err", err)
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
+
+// handleCodegenDownload sends the generated types as file download
+func (app *App) handleCodegenDownload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	err := r.ParseForm()
+	if err != nil {
+		app.logger.Error("failed to parse form", "err", err)
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	filename := "types.go"
+	if codegen.Language(r.FormValue("language")) == codegen.TypeScript {
+		filename = "types.ts"
+	}
+	content := []byte(r.FormValue("code"))
+
+	// Set headers for file download
+	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(content)))
+
+	_, err = w.Write(content)
+	if err != nil {
+		app.logger.Error("failed to write generated types", "err", err)
+	}
+}
@@ -1,13 +1,16 @@
 package jsonedit
 
 import (
-	"encoding/json"
+	stdJson "encoding/json"
+	"errors"
 	"fmt"
-	"html"
 	"html/template"
-	"io"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 
+	"github.com/sascha-andres/jsonedit/json"
 	"github.com/sascha-andres/jsonedit/json/compare"
 )
 
@@ -30,53 +33,24 @@ func (app *App) renderCompareResult(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get the first file from the form
-	file1, _, err := r.FormFile("jsonFile1")
+	// Get the first document from the uploaded file or the pasted text
+	content1, err := app.readFormFileOrText(r, "jsonFile1", "jsonText1")
 	if err != nil {
-		app.logger.Error("failed to get first file from form", "err", err)
-		http.Error(w, "Failed to get first file from form", http.StatusBadRequest)
-		return
-	}
-	defer func() {
-		err := file1.Close()
-		if err != nil {
-			app.logger.Error("failed to close first file", "err", err)
-		}
-	}()
-
-	// Get the second file from the form
-	file2, _, err := r.FormFile("jsonFile2")
-	if err != nil {
-		app.logger.Error("failed to get second file from form", "err", err)
-		http.Error(w, "Failed to get second file from form", http.StatusBadRequest)
-		return
-	}
-	defer func() {
-		err := file2.Close()
-		if err != nil {
-			app.logger.Error("failed to close second file", "err", err)
-		}
-	}()
-
-	// Read the first file content
-	content1, err := io.ReadAll(file1)
-	if err != nil {
-		app.logger.Error("failed to read first file", "err", err)
-		http.Error(w, "Failed to read first file", http.StatusInternalServerError)
+		app.logger.Error("failed to read first document", "err", err)
+		http.Error(w, "Failed to read first document: "+err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// Read the second file content
-	content2, err := io.ReadAll(file2)
+	// Get the second document from the uploaded file or the pasted text
+	content2, err := app.readFormFileOrText(r, "jsonFile2", "jsonText2")
 	if err != nil {
-		app.logger.Error("failed to read second file", "err", err)
-		http.Error(w, "Failed to read second file", http.StatusInternalServerError)
+		app.logger.Error("failed to read second document", "err", err)
+		http.Error(w, "Failed to read second document: "+err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	// Parse the first JSON content
-	var jsonData1 interface{}
-	err = json.Unmarshal(content1, &jsonData1)
+	jsonData1, err := json.Parse(content1)
 	if err != nil {
 		app.logger.Error("failed to parse first JSON file", "err", err)
 		http.Error(w, "Failed to parse first JSON file: "+err.Error(), http.StatusBadRequest)
@@ -84,38 +58,50 @@ func (app *App) renderCompareResult(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Parse the second JSON content
-	var jsonData2 interface{}
-	err = json.Unmarshal(content2, &jsonData2)
+	jsonData2, err := json.Parse(content2)
 	if err != nil {
 		app.logger.Error("failed to parse second JSON file", "err", err)
 		http.Error(w, "Failed to parse second JSON file: "+err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// Compare the two JSON objects
-	diff, err := compare.GetJSONComparison(jsonData1, jsonData2, app.indent)
+	options, err := compareOptions(r)
 	if err != nil {
-		app.logger.Error("failed to compare JSON files", "err", err)
-		http.Error(w, "Failed to compare JSON files: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// Render the comparison result on a separate page
-	tmpl := template.Must(template.New("compare").Parse(compareResultTemplate))
+	// Compare the two JSON documents
+	result := compare.Compare(jsonData1, jsonData2, options...)
 
-	var result string
-	if diff == "" {
-		result = "No changes"
-	} else {
-		result = fmt.Sprintf("<pre>%s</pre>", html.EscapeString(diff))
+	left, right, err := buildDiffPanes(jsonData1, jsonData2, app.indent, result.Changes)
+	if err != nil {
+		app.logger.Error("failed to format JSON documents", "err", err)
+		http.Error(w, "Failed to format JSON", http.StatusInternalServerError)
+		return
 	}
 
-	data := struct {
-		ComparisonResult template.HTML
-	}{
-		ComparisonResult: template.HTML(result),
+	data := CompareResultData{
+		Equal:             result.Equal(),
+		Added:             result.Count(compare.Added),
+		Removed:           result.Count(compare.Removed),
+		Modified:          result.Count(compare.Modified),
+		TypeChanged:       result.Count(compare.TypeChanged),
+		Tree:              buildChangeTree(result.Changes),
+		Left:              left,
+		Right:             right,
+		First:             string(content1),
+		Second:            string(content2),
+		ArrayKey:          r.FormValue("arrayKey"),
+		UnorderedArrays:   r.FormValue("unorderedArrays") == "true",
+		IgnorePaths:       r.FormValue("ignorePaths"),
+		AbsoluteTolerance: r.FormValue("absoluteTolerance"),
+		RelativeTolerance: r.FormValue("relativeTolerance"),
+		Suppressed:        result.Suppressed,
 	}
 
+	// Render the comparison result on a separate page
+	tmpl := template.Must(template.New("compare").Parse(compareResultTemplate))
 	err = tmpl.Execute(w, data)
 	if err != nil {
 		app.logger.Error("failed to render comparison result template", "err", err)
@@ -123,6 +109,212 @@ func (app *App) renderCompareResult(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handleCompareExport compares the posted documents again and provides the changes,
+// a JSON Patch or a Merge Patch as a download
+func (app *App) handleCompareExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	err := r.ParseForm()
+	if err != nil {
+		app.logger.Error("failed to parse form", "err", err)
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	first, err := json.Parse([]byte(r.FormValue("first")))
+	if err != nil {
+		http.Error(w, "Failed to parse first JSON document: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	second, err := json.Parse([]byte(r.FormValue("second")))
+	if err != nil {
+		http.Error(w, "Failed to parse second JSON document: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var exported interface{}
+	filename, contentType := "comparison.json", "application/json"
+	switch r.FormValue("format") {
+	case "json-patch":
+		exported = compare.JSONPatch(first, second)
+		filename, contentType = "patch.json", "application/json-patch+json"
+	case "merge-patch":
+		exported, err = compare.MergePatch(first, second)
+		if err != nil {
+			http.Error(w, "Failed to create merge patch: "+err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		filename, contentType = "merge-patch.json", "application/merge-patch+json"
+	default:
+		options, err := compareOptions(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		exported = compare.Compare(first, second, options...)
+	}
+
+	content, err := stdJson.MarshalIndent(exported, "", app.indent)
+	if err != nil {
+		app.logger.Error("failed to format comparison result", "err", err)
+		http.Error(w, "Failed to format comparison result", http.StatusInternalServerError)
+		return
+	}
+
+	// Set headers for file download
+	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(content)))
+
+	_, err = w.Write(content)
+	if err != nil {
+		app.logger.Error("failed to write comparison result", "err", err)
+	}
+}
+
+// readFormFileOrText returns the content of the uploaded file fileName or, if no file was
+// uploaded, the text of the form field textName
+func (app *App) readFormFileOrText(r *http.Request, fileName, textName string) ([]byte, error) {
+	content, err := app.readFormFile(r, fileName)
+	if err == nil {
+		return content, nil
+	}
+	if !errors.Is(err, http.ErrMissingFile) {
+		return nil, err
+	}
+	text := r.FormValue(textName)
+	if strings.TrimSpace(text) == "" {
+		return nil, errors.New("neither a file was uploaded nor text was pasted")
+	}
+	return []byte(text), nil
+}
+
+// compareOptions returns the comparison options selected in the submitted form
+func compareOptions(r *http.Request) ([]compare.Option, error) {
+	absolute, err := parseTolerance(r.FormValue("absoluteTolerance"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid absolute tolerance: %w", err)
+	}
+	relative, err := parseTolerance(r.FormValue("relativeTolerance"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid relative tolerance: %w", err)
+	}
+	return []compare.Option{
+		compare.WithArrayKey(r.FormValue("arrayKey")),
+		compare.WithUnorderedArrays(r.FormValue("unorderedArrays") == "true"),
+		compare.WithIgnore(strings.Split(r.FormValue("ignorePaths"), "\n")...),
+		compare.WithTolerance(absolute, relative),
+	}, nil
+}
+
+// parseTolerance parses a tolerance form value, an empty value disables the tolerance
+func parseTolerance(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	tolerance, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	if tolerance < 0 {
+		return 0, fmt.Errorf("tolerance must not be negative")
+	}
+	return tolerance, nil
+}
+
+// changeTreeNode is an element of the change tree shown on the comparison result page
+type changeTreeNode struct {
+
+	// Name is the reference token of the element
+	Name string
+
+	// Type is the change type of the element, empty for elements that only contain changes
+	Type compare.ChangeType
+
+	// Old is the compact value in the first document
+	Old string
+
+	// New is the compact value in the second document
+	New string
+
+	// Children are the nested elements
+	Children []*changeTreeNode
+}
+
+// buildChangeTree arranges changes in a tree following their JSON Pointer paths. Every change gets
+// its own element, so a value removed from the first document and a value added to the second
+// document at the same index are listed separately. Tokens of values matched at another position
+// are shown as from → to.
+func buildChangeTree(changes []compare.Change) []*changeTreeNode {
+	root := &changeTreeNode{}
+	for _, change := range changes {
+		names, err := changeNames(change)
+		if err != nil {
+			continue
+		}
+		if len(names) == 0 {
+			names = []string{"(document)"}
+		}
+		node := root
+		for _, name := range names[:len(names)-1] {
+			node = node.child(name)
+		}
+		node.Children = append(node.Children, &changeTreeNode{
+			Name: names[len(names)-1],
+			Type: change.Type,
+			Old:  compactValue(change.Old),
+			New:  compactValue(change.New),
+		})
+	}
+	return root.Children
+}
+
+// changeNames returns the names of the tree elements leading to a change, tokens that differ
+// between the location in the first and in the second document are joined by →
+func changeNames(change compare.Change) ([]string, error) {
+	tokens, err := json.ParsePointer(change.Path)
+	if err != nil || change.From == "" {
+		return tokens, err
+	}
+	from, err := json.ParsePointer(change.From)
+	if err != nil || len(from) != len(tokens) {
+		return tokens, nil
+	}
+	for i := range tokens {
+		if from[i] != tokens[i] {
+			tokens[i] = from[i] + " → " + tokens[i]
+		}
+	}
+	return tokens, nil
+}
+
+// child returns the child element with the given name that holds nested changes, creating it if necessary
+func (n *changeTreeNode) child(name string) *changeTreeNode {
+	for _, c := range n.Children {
+		if c.Name == name && c.Type == "" {
+			return c
+		}
+	}
+	c := &changeTreeNode{Name: name}
+	n.Children = append(n.Children, c)
+	return c
+}
+
+// compactValue returns the compact JSON of node, an empty string for nil
+func compactValue(node *json.Node) string {
+	if node == nil {
+		return ""
+	}
+	content, err := node.Format("", "")
+	if err != nil {
+		return ""
+	}
+	return string(content)
+}
+
 func (app *App) renderCompareForm(w http.ResponseWriter, r *http.Request) {
 	tmpl := template.Must(template.New("compare").Parse(compareFormTemplate))
 	err := tmpl.Execute(w, nil)
@@ -131,3 +323,237 @@ func (app *App) renderCompareForm(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Failed to render page", http.StatusInternalServerError)
 	}
 }
+
+// diffContextLines is the number of unchanged lines shown around changes in the side-by-side view
+const diffContextLines = 3
+
+// diffLine is a line of a document in the side-by-side view
+type diffLine struct {
+
+	// Class is the CSS class highlighting the line, empty for unchanged lines
+	Class string
+
+	// Text is the content of the line
+	Text string
+}
+
+// diffBlock is a run of lines in the side-by-side view
+type diffBlock struct {
+
+	// Collapsed indicates whether the block holds unchanged lines that are hidden initially
+	Collapsed bool
+
+	// Lines are the lines of the block
+	Lines []diffLine
+}
+
+// firstDocumentMarks maps the paths of changed values in the first document to their change type
+func firstDocumentMarks(changes []compare.Change) map[string]compare.ChangeType {
+	marks := make(map[string]compare.ChangeType)
+	for _, change := range changes {
+		switch {
+		case change.Type == compare.Added:
+			// Added values do not exist in the first document
+		case change.From != "":
+			marks[change.From] = change.Type
+		default:
+			marks[change.Path] = change.Type
+		}
+	}
+	return marks
+}
+
+// secondDocumentMarks maps the paths of changed values in the second document to their change type
+func secondDocumentMarks(changes []compare.Change) map[string]compare.ChangeType {
+	marks := make(map[string]compare.ChangeType)
+	for _, change := range changes {
+		if change.Type != compare.Removed {
+			marks[change.Path] = change.Type
+		}
+	}
+	return marks
+}
+
+// maxAlignEdits is the maximum number of unchanged lines that may differ between both documents
+// when aligning them, more differences are aligned by position to bound the effort
+const maxAlignEdits = 1000
+
+// buildDiffPanes formats both documents and aligns their lines row by row, lines only present in
+// one document get a placeholder in the other pane. Changed values are highlighted by their change
+// type. Longer runs of unchanged rows are put into collapsed blocks at the same position of both panes.
+func buildDiffPanes(a, b *json.Node, indent string, changes []compare.Change) (left, right []diffBlock, err error) {
+	linesA, err := a.FormatLines(indent)
+	if err != nil {
+		return nil, nil, err
+	}
+	linesB, err := b.FormatLines(indent)
+	if err != nil {
+		return nil, nil, err
+	}
+	rowsA, rowsB, changed := alignLines(
+		diffLines(linesA, firstDocumentMarks(changes)),
+		diffLines(linesB, secondDocumentMarks(changes)))
+
+	left, right = make([]diffBlock, 0), make([]diffBlock, 0)
+	appendBlock := func(from, to int, collapsed bool) {
+		if from < to {
+			left = append(left, diffBlock{Collapsed: collapsed, Lines: rowsA[from:to]})
+			right = append(right, diffBlock{Collapsed: collapsed, Lines: rowsB[from:to]})
+		}
+	}
+	start := 0
+	flush := func(end int, last bool) {
+		head := diffContextLines
+		if len(left) == 0 {
+			head = 0
+		}
+		tail := diffContextLines
+		if last {
+			tail = 0
+		}
+		if end-start > head+tail+diffContextLines {
+			appendBlock(start, start+head, false)
+			appendBlock(start+head, end-tail, true)
+			appendBlock(end-tail, end, false)
+		} else {
+			appendBlock(start, end, false)
+		}
+	}
+	for i := range changed {
+		if !changed[i] {
+			continue
+		}
+		flush(i, false)
+		appendBlock(i, i+1, false)
+		start = i + 1
+	}
+	flush(len(changed), true)
+	return left, right, nil
+}
+
+// diffLines returns the lines of a document, highlighted by the change type of their path
+func diffLines(lines []json.Line, marks map[string]compare.ChangeType) []diffLine {
+	result := make([]diffLine, len(lines))
+	for i, line := range lines {
+		result[i] = diffLine{Text: line.Text}
+		if changeType, ok := changeOfPath(marks, line.Path); ok {
+			result[i].Class = "diff-" + string(changeType)
+		}
+	}
+	return result
+}
+
+// alignLines pairs the lines of both documents into rows. Unchanged lines with the same text are
+// matched, the lines between matches are paired as changed rows filled up with placeholders.
+func alignLines(a, b []diffLine) (rowsA, rowsB []diffLine, changed []bool) {
+	indicesA, keysA := unchangedLines(a)
+	indicesB, keysB := unchangedLines(b)
+	matches, ok := commonLines(keysA, keysB)
+	if !ok {
+		matches = make([][2]int, min(len(keysA), len(keysB)))
+		for i := range matches {
+			matches[i] = [2]int{i, i}
+		}
+	}
+
+	placeholder := diffLine{Class: "diff-placeholder"}
+	i, j := 0, 0
+	pair := func(endA, endB int) {
+		for i < endA || j < endB {
+			lineA, lineB := placeholder, placeholder
+			if i < endA {
+				lineA, i = a[i], i+1
+			}
+			if j < endB {
+				lineB, j = b[j], j+1
+			}
+			rowsA, rowsB, changed = append(rowsA, lineA), append(rowsB, lineB), append(changed, true)
+		}
+	}
+	for _, match := range matches {
+		endA, endB := indicesA[match[0]], indicesB[match[1]]
+		pair(endA, endB)
+		rowsA, rowsB, changed = append(rowsA, a[endA]), append(rowsB, b[endB]), append(changed, false)
+		i, j = endA+1, endB+1
+	}
+	pair(len(a), len(b))
+	return rowsA, rowsB, changed
+}
+
+// unchangedLines returns the positions of the lines that are not highlighted together with their
+// text without a trailing comma, so the last element of a container matches its former position
+func unchangedLines(lines []diffLine) (indices []int, keys []string) {
+	for i, line := range lines {
+		if line.Class == "" {
+			indices = append(indices, i)
+			keys = append(keys, strings.TrimSuffix(line.Text, ","))
+		}
+	}
+	return indices, keys
+}
+
+// commonLines returns the index pairs of a longest common subsequence of a and b using the Myers
+// difference algorithm. It reports false if the sequences differ in more than maxAlignEdits elements.
+func commonLines(a, b []string) ([][2]int, bool) {
+	n, m := len(a), len(b)
+	limit := min(n+m, maxAlignEdits)
+	offset := limit + 1
+	v := make([]int, 2*limit+3)
+	trace := make([][]int, 0)
+	for d := 0; d <= limit; d++ {
+		trace = append(trace, slices.Clone(v))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrackCommonLines(trace, offset, n, m), true
+			}
+		}
+	}
+	return nil, false
+}
+
+// backtrackCommonLines follows the paths recorded by commonLines back from the end of both sequences
+// and returns the matched index pairs in ascending order
+func backtrackCommonLines(trace [][]int, offset, x, y int) [][2]int {
+	matches := make([][2]int, 0)
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+		previous := k - 1
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			previous = k + 1
+		}
+		previousX := v[offset+previous]
+		previousY := previousX - previous
+		for x > previousX && y > previousY {
+			x, y = x-1, y-1
+			matches = append(matches, [2]int{x, y})
+		}
+		x, y = previousX, previousY
+	}
+	slices.Reverse(matches)
+	return matches
+}
+
+// changeOfPath returns the change type of path or of its closest changed parent
+func changeOfPath(marks map[string]compare.ChangeType, path string) (compare.ChangeType, bool) {
+	for {
+		if changeType, ok := marks[path]; ok {
+			return changeType, true
+		}
+		if path == "" {
+			return "", false
+		}
+		path = path[:strings.LastIndex(path, "/")]
+	}
+}
@@ -0,0 +1,215 @@
+package jsonedit
+
+import (
+	"testing"
+
+	"github.com/sascha-andres/jsonedit/json"
+	"github.com/sascha-andres/jsonedit/json/compare"
+)
+
+func TestBuildChangeTree(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    string
+		options []compare.Option
+		want    []*changeTreeNode
+	}{
+		{
+			name: "modified member",
+			a:    `{"a":{"b":1}}`,
+			b:    `{"a":{"b":2}}`,
+			want: []*changeTreeNode{{Name: "a", Children: []*changeTreeNode{
+				{Name: "b", Type: compare.Modified, Old: "1", New: "2"},
+			}}},
+		},
+		{
+			name:    "removed and added at same index",
+			a:       `{"a":[1,2]}`,
+			b:       `{"a":[3,2]}`,
+			options: []compare.Option{compare.WithUnorderedArrays(true)},
+			want: []*changeTreeNode{{Name: "a", Children: []*changeTreeNode{
+				{Name: "0", Type: compare.Removed, Old: "1"},
+				{Name: "0", Type: compare.Added, New: "3"},
+			}}},
+		},
+		{
+			name:    "moved element",
+			a:       `[{"id":1,"v":"x"},{"id":2,"v":"y"}]`,
+			b:       `[{"id":3},{"id":1,"v":"z"}]`,
+			options: []compare.Option{compare.WithArrayKey("id")},
+			want: []*changeTreeNode{
+				{Name: "0 → 1", Children: []*changeTreeNode{
+					{Name: "v", Type: compare.Modified, Old: `"x"`, New: `"z"`},
+				}},
+				{Name: "1", Type: compare.Removed, Old: `{"id":2,"v":"y"}`},
+				{Name: "0", Type: compare.Added, New: `{"id":3}`},
+			},
+		},
+		{
+			name: "document",
+			a:    `1`,
+			b:    `"1"`,
+			want: []*changeTreeNode{{Name: "(document)", Type: compare.TypeChanged, Old: "1", New: `"1"`}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := json.Parse([]byte(tt.a))
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := json.Parse([]byte(tt.b))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := buildChangeTree(compare.Compare(a, b, tt.options...).Changes)
+			if !equalChangeTrees(got, tt.want) {
+				t.Errorf("buildChangeTree() = %s, want %s", formatChangeTree(got), formatChangeTree(tt.want))
+			}
+		})
+	}
+}
+
+// equalChangeTrees reports whether both trees hold the same elements in the same order
+func equalChangeTrees(a, b []*changeTreeNode) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Name != b[i].Name || a[i].Type != b[i].Type || a[i].Old != b[i].Old || a[i].New != b[i].New ||
+			!equalChangeTrees(a[i].Children, b[i].Children) {
+			return false
+		}
+	}
+	return true
+}
+
+// formatChangeTree writes the tree in a compact form for error messages
+func formatChangeTree(nodes []*changeTreeNode) string {
+	result := "["
+	for i, node := range nodes {
+		if i > 0 {
+			result += " "
+		}
+		result += node.Name
+		if node.Type != "" {
+			result += ":" + string(node.Type) + "(" + node.Old + "," + node.New + ")"
+		}
+		if len(node.Children) > 0 {
+			result += formatChangeTree(node.Children)
+		}
+	}
+	return result + "]"
+}
+
+func TestBuildDiffPanes(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    string
+		options []compare.Option
+		want    [][2]string
+	}{
+		{
+			name: "added member",
+			a:    `{"a":1,"b":2}`,
+			b:    `{"a":1,"x":[1,2],"b":2}`,
+			want: [][2]string{
+				{"{", "{"},
+				{`  "a": 1,`, `  "a": 1,`},
+				{"", `  "x": [`},
+				{"", `    1,`},
+				{"", `    2`},
+				{"", `  ],`},
+				{`  "b": 2`, `  "b": 2`},
+				{"}", "}"},
+			},
+		},
+		{
+			name: "removed last member and modified value",
+			a:    `{"a":1,"b":2,"c":3}`,
+			b:    `{"a":1,"b":5}`,
+			want: [][2]string{
+				{"{", "{"},
+				{`  "a": 1,`, `  "a": 1,`},
+				{`  "b": 2,`, `  "b": 5`},
+				{`  "c": 3`, ""},
+				{"}", "}"},
+			},
+		},
+		{
+			name:    "removed and added at same index",
+			a:       `[1,2]`,
+			b:       `[3,2]`,
+			options: []compare.Option{compare.WithUnorderedArrays(true)},
+			want: [][2]string{
+				{"[", "["},
+				{"  1,", "  3,"},
+				{"  2", "  2"},
+				{"]", "]"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := json.Parse([]byte(tt.a))
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := json.Parse([]byte(tt.b))
+			if err != nil {
+				t.Fatal(err)
+			}
+			left, right, err := buildDiffPanes(a, b, "  ", compare.Compare(a, b, tt.options...).Changes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(left) != len(right) {
+				t.Fatalf("got %d left and %d right blocks", len(left), len(right))
+			}
+			var got [][2]string
+			for i := range left {
+				if len(left[i].Lines) != len(right[i].Lines) || left[i].Collapsed != right[i].Collapsed {
+					t.Fatalf("block %d differs between panes", i)
+				}
+				for j := range left[i].Lines {
+					got = append(got, [2]string{left[i].Lines[j].Text, right[i].Lines[j].Text})
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("rows = %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("row %d = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBuildDiffPanesCollapsesBothPanes(t *testing.T) {
+	a, err := json.Parse([]byte(`{"a":0,"b":1,"c":2,"d":3,"e":4,"f":5,"g":6,"h":7,"i":8,"j":9,"k":10,"l":11,"m":12}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Parse([]byte(`{"new":true,"a":0,"b":1,"c":2,"d":3,"e":4,"f":5,"g":6,"h":7,"i":8,"j":9,"k":10,"l":11,"m":13}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	left, right, err := buildDiffPanes(a, b, "  ", compare.Compare(a, b).Changes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	collapsed := 0
+	for i := range left {
+		if left[i].Collapsed != right[i].Collapsed || len(left[i].Lines) != len(right[i].Lines) {
+			t.Fatalf("block %d differs between panes", i)
+		}
+		if left[i].Collapsed {
+			collapsed++
+		}
+	}
+	if collapsed != 1 {
+		t.Errorf("got %d collapsed blocks, want 1", collapsed)
+	}
+}
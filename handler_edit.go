@@ -1,16 +1,22 @@
 package jsonedit
 
 import (
-	"encoding/json"
+	stdJson "encoding/json"
+	"errors"
+	"fmt"
 	"html/template"
 	"net/http"
+	"strings"
 
+	"github.com/sascha-andres/jsonedit/json"
 	"github.com/sascha-andres/jsonedit/json/form"
+	"github.com/sascha-andres/jsonedit/json/fromschema"
+	"github.com/sascha-andres/jsonedit/json/validate"
 )
 
 // handleEdit processes the JSON content from a GET or POST request and renders the edit page
 func (app *App) handleEdit(w http.ResponseWriter, r *http.Request) {
-	var jsonContent string
+	var jsonContent, filePath, highlight, schema string
 
 	// Check request method and get JSON content accordingly
 	if r.Method == "POST" {
@@ -21,12 +27,25 @@ func (app *App) handleEdit(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "Failed to parse form data", http.StatusBadRequest)
 			return
 		}
-		jsonContent = r.FormValue("jsonContent")
+		jsonContent, err = app.editedContent(r)
+		if err != nil {
+			app.logger.Error("failed to apply edits", "err", err)
+			http.Error(w, "Failed to apply edits: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		filePath = r.FormValue("filePath")
+		highlight = r.FormValue("highlight")
+		schema = r.FormValue("schema")
 	} else {
 		// For GET requests, get the JSON content from the query parameter
 		jsonContent = r.URL.Query().Get("jsonContent")
 	}
 
+	// Only keep the file path if the content can be saved back to the workspace
+	if app.workspace == nil {
+		filePath = ""
+	}
+
 	if jsonContent == "" {
 		app.logger.Error("invalid json content")
 		http.Error(w, "Missing JSON content", http.StatusBadRequest)
@@ -34,8 +53,7 @@ func (app *App) handleEdit(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Validate JSON
-	var jsonData interface{}
-	err := json.Unmarshal([]byte(jsonContent), &jsonData)
+	jsonData, err := json.Parse([]byte(jsonContent))
 	if err != nil {
 		// Try to create a simple object with the content as a string
 		simpleData := map[string]interface{}{
@@ -49,13 +67,15 @@ func (app *App) handleEdit(w http.ResponseWriter, r *http.Request) {
 			Error:       "Invalid JSON: " + err.Error(),
 			FormContent: template.HTML(formContent),
 			ReadOnly:    app.readOnly,
+			Path:        filePath,
+			Schema:      schema,
 		}
 		app.renderEditPage(w, data)
 		return
 	}
 
 	// Pretty print the JSON
-	prettyJSON, err := json.MarshalIndent(jsonData, "", app.indent)
+	prettyJSON, err := jsonData.Format("", app.indent)
 	if err != nil {
 		app.logger.Error("failed to marshal json data", "err", err)
 		http.Error(w, "Failed to format JSON", http.StatusInternalServerError)
@@ -63,19 +83,32 @@ func (app *App) handleEdit(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Generate form elements for each JSON field
-	formContent := form.GenerateJSONForm(app.logger.With("module", "form"), app.readOnly, jsonData, "", 0)
+	formContent := app.generateEditForm(jsonData, schema)
 
 	// Render the edit page with the JSON content and form elements
 	data := EditPageData{
 		Content:     string(prettyJSON),
 		FormContent: template.HTML(formContent),
 		ReadOnly:    app.readOnly,
+		Path:        filePath,
+		Schema:      schema,
+	}
+
+	// Highlight the field given as JSON Pointer, e.g. a value failing validation
+	if highlight != "" {
+		fieldPath, err := form.FieldPath(jsonData, highlight)
+		if err != nil {
+			data.Error = "Cannot highlight field: " + err.Error()
+		} else {
+			data.Highlight = fieldPath
+		}
 	}
 	app.renderEditPage(w, data)
 }
 
 // renderEditPage renders the edit page template
 func (app *App) renderEditPage(w http.ResponseWriter, data EditPageData) {
+	data.BlockInvalidSave = app.blockInvalidSave
 	tmpl := template.Must(template.New("edit").Parse(editPageTemplate))
 	err := tmpl.Execute(w, data)
 	if err != nil {
@@ -83,3 +116,144 @@ func (app *App) renderEditPage(w http.ResponseWriter, data EditPageData) {
 		http.Error(w, "Failed to render page", http.StatusInternalServerError)
 	}
 }
+
+// generateEditForm generates the form elements of the edit page, driven by the schema attached to
+// the edit session if there is one
+func (app *App) generateEditForm(document *json.Node, schema string) string {
+	logger := app.logger.With("module", "form")
+	if strings.TrimSpace(schema) != "" {
+		parser, err := fromschema.NewSchemaParser(app.logger.With("module", "from_schema"), []byte(schema))
+		if err == nil {
+			return form.GenerateSchemaForm(logger, app.readOnly, document, parser.Schema(), "", 0)
+		}
+		// The validation status of the edit page reports the broken schema
+		app.logger.Warn("failed to compile attached schema", "err", err)
+	}
+	return form.GenerateJSONForm(logger, app.readOnly, document, "", 0)
+}
+
+// editedContent returns the document of the edit form with the edits made in the form applied. The
+// field edits holds them as JSON array, the document is returned unchanged if there are none.
+func (app *App) editedContent(r *http.Request) (string, error) {
+	jsonContent := r.FormValue("jsonContent")
+	var edits []form.Edit
+	if value := r.FormValue("edits"); value != "" {
+		if err := stdJson.Unmarshal([]byte(value), &edits); err != nil {
+			return "", err
+		}
+	}
+	if len(edits) == 0 {
+		return jsonContent, nil
+	}
+
+	document, err := json.Parse([]byte(jsonContent))
+	if err != nil {
+		return "", err
+	}
+	if err := form.ApplyEdits(document, edits); err != nil {
+		return "", err
+	}
+	content, err := document.Format("", app.indent)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// editViolation is a schema violation as shown next to the form fields of the edit page
+type editViolation struct {
+
+	// Pointer is the JSON Pointer of the failing value
+	Pointer string
+
+	// Field is the path of the form field showing the failing value, empty if there is none
+	Field string
+
+	// Message describes the violation
+	Message string
+}
+
+// handleEditValidate validates the edited document against the schema attached to the edit
+// session and renders the validation status shown on the edit page
+func (app *App) handleEditValidate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	err := r.ParseForm()
+	if err != nil {
+		app.logger.Error("failed to parse form", "err", err)
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	// Without a schema there is nothing to show
+	schema := r.FormValue("schema")
+	if strings.TrimSpace(schema) == "" {
+		return
+	}
+
+	data := EditValidationData{Violations: []editViolation{}}
+	content, err := app.editedContent(r)
+	jsonContent := []byte(content)
+	var violations []validate.Violation
+	if err == nil {
+		violations, err = app.schemaViolations([]byte(schema), jsonContent)
+	}
+	if err != nil {
+		data.Error = err.Error()
+	} else if doc, err := json.Parse(jsonContent); err == nil {
+		for _, violation := range violations {
+			// Values without a form field, e.g. missing properties, are listed without marker
+			field, _ := form.FieldPath(doc, violation.InstanceLocation)
+			data.Violations = append(data.Violations, editViolation{
+				Pointer: violation.InstanceLocation,
+				Field:   field,
+				Message: violation.Message,
+			})
+		}
+	}
+
+	tmpl := template.Must(template.New("editValidation").Parse(editValidationTemplate))
+	err = tmpl.Execute(w, data)
+	if err != nil {
+		app.logger.Error("failed to render edit validation template", "err", err)
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
+
+// schemaViolations validates content against schema and returns the violations, an error
+// is returned if the schema or the document cannot be read
+func (app *App) schemaViolations(schema, content []byte) ([]validate.Violation, error) {
+	validator, err := validate.NewJSONValidator(
+		validate.WithJSONSchema(schema),
+		validate.WithJSONDocument(content),
+		validate.WithLogger(app.logger.With("module", "validate")),
+	)
+	if err != nil {
+		return nil, err
+	}
+	err = validator.Validate()
+	var validationErr *validate.ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr.Violations, nil
+	}
+	return nil, err
+}
+
+// checkSaveAgainstSchema validates content against the schema attached in the editor before saving.
+// It returns a message describing the violations, empty if there is no schema or the document is valid,
+// and whether saving is refused.
+func (app *App) checkSaveAgainstSchema(schema string, content []byte) (string, bool) {
+	if strings.TrimSpace(schema) == "" {
+		return "", false
+	}
+	violations, err := app.schemaViolations([]byte(schema), content)
+	if err != nil {
+		return "Failed to validate against schema: " + err.Error(), app.blockInvalidSave
+	}
+	if len(violations) == 0 {
+		return "", false
+	}
+	return fmt.Sprintf("Document does not satisfy the schema (%d violation(s))", len(violations)), app.blockInvalidSave
+}
@@ -1,8 +1,8 @@
 package jsonedit
 
 import (
+	"fmt"
 	"html/template"
-	"io"
 	"net/http"
 
 	"github.com/sascha-andres/jsonedit/json/flatten"
@@ -18,7 +18,9 @@ func (app *App) handleFlatten(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// renderFlattenResult processes a JSON file and flattens it and renders the result on a separate page
+// renderFlattenResult processes a JSON file and flattens it and renders the result on a separate page.
+// In unflatten mode the submitted input is read with the selected format and options and turned
+// back into a document opened in the editor.
 func (app *App) renderFlattenResult(w http.ResponseWriter, r *http.Request) {
 	// Parse the multipart form
 	err := r.ParseMultipartForm(10 << 20) // 10 MB max
@@ -28,30 +30,35 @@ func (app *App) renderFlattenResult(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get the file from the form
-	file, _, err := r.FormFile("jsonFileFlat")
+	// Get the input from the uploaded file or the pasted text
+	content, err := app.readFormFileOrText(r, "jsonFileFlat", "flatText")
 	if err != nil {
-		app.logger.Error("failed to get file from form", "err", err)
-		http.Error(w, "Failed to get file from form", http.StatusBadRequest)
+		app.logger.Error("failed to read input", "err", err)
+		http.Error(w, "Failed to read input: "+err.Error(), http.StatusBadRequest)
 		return
 	}
-	defer func() {
-		err := file.Close()
+
+	if r.FormValue("mode") == "unflatten" {
+		// Rebuild the document and open it in the editor
+		document, err := flatten.UnflattenFormat(content, flatten.Format(r.FormValue("format")), flattenOptions(r)...)
 		if err != nil {
-			app.logger.Error("failed to close file", "err", err)
+			app.logger.Error("failed to unflatten input", "err", err)
+			http.Error(w, "Failed to unflatten input: "+err.Error(), http.StatusBadRequest)
+			return
 		}
-	}()
-
-	// Read the file content
-	content, err := io.ReadAll(file)
-	if err != nil {
-		app.logger.Error("failed to read file", "err", err)
-		http.Error(w, "Failed to read file", http.StatusInternalServerError)
+		app.renderDocumentEditor(w, document)
 		return
 	}
 
-	// Validate and flatten JSON
-	flattenedLines, err := flatten.FlattenJSON(content)
+	// Flatten the JSON in the selected format
+	data := FlattenResultData{
+		Document:     string(content),
+		KeyStyle:     r.FormValue("keyStyle"),
+		EscapeKeys:   r.FormValue("escapeKeys") == "true",
+		QuoteStrings: r.FormValue("quoteStrings") == "true",
+		Format:       r.FormValue("format"),
+	}
+	flattened, err := flatten.Flatten(content, flatten.Format(data.Format), flattenOptions(r)...)
 	if err != nil {
 		app.logger.Error("failed to flatten JSON file", "err", err)
 		http.Error(w, "Failed to flatten JSON file: "+err.Error(), http.StatusBadRequest)
@@ -59,24 +66,14 @@ func (app *App) renderFlattenResult(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Prepare the flattened result
-	var result string
-	if len(flattenedLines) == 0 {
-		result = "No properties found in JSON"
+	if len(flattened) == 0 {
+		data.FlattenResult = "No properties found in JSON"
 	} else {
-		for _, line := range flattenedLines {
-			result += line + "\n"
-		}
+		data.FlattenResult = string(flattened)
 	}
 
 	// Render the flattened result on a separate page
 	tmpl := template.Must(template.New("flatten").Parse(flattenResultTemplate))
-
-	data := struct {
-		FlattenResult string
-	}{
-		FlattenResult: result,
-	}
-
 	err = tmpl.Execute(w, data)
 	if err != nil {
 		app.logger.Error("failed to render flatten result template", "err", err)
@@ -84,9 +81,62 @@ func (app *App) renderFlattenResult(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handleFlattenDownload flattens the submitted JSON document and returns the result as file download
+func (app *App) handleFlattenDownload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	err := r.ParseForm()
+	if err != nil {
+		app.logger.Error("failed to parse form", "err", err)
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	format := flatten.Format(r.FormValue("format"))
+	content, err := flatten.Flatten([]byte(r.FormValue("document")), format, flattenOptions(r)...)
+	if err != nil {
+		http.Error(w, "Failed to flatten JSON document: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filename, contentType := "flattened.txt", "text/plain; charset=utf-8"
+	switch format {
+	case flatten.CSVFormat:
+		filename, contentType = "flattened.csv", "text/csv; charset=utf-8"
+	case flatten.PropertiesFormat:
+		filename = "flattened.properties"
+	case flatten.EnvFormat:
+		filename = "flattened.env"
+	}
+
+	// Set headers for file download
+	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(content)))
+
+	_, err = w.Write(content)
+	if err != nil {
+		app.logger.Error("failed to write flattened document", "err", err)
+	}
+}
+
+// flattenOptions returns the flatten options selected in the submitted form
+func flattenOptions(r *http.Request) []flatten.Option {
+	options := []flatten.Option{
+		flatten.WithEscapedKeys(r.FormValue("escapeKeys") == "true"),
+		flatten.WithQuotedStrings(r.FormValue("quoteStrings") == "true"),
+	}
+	if keyStyle := r.FormValue("keyStyle"); keyStyle != "" {
+		options = append(options, flatten.WithKeyStyle(flatten.KeyStyle(keyStyle)))
+	}
+	return options
+}
+
 // renderFlattenForm renders the flatten form on a separate page
 func (app *App) renderFlattenForm(w http.ResponseWriter, r *http.Request) {
-	tmpl := template.Must(template.New("compare").Parse(flattenFormTemplate))
+	tmpl := template.Must(template.New("flatten").Parse(flattenFormTemplate))
 	err := tmpl.Execute(w, nil)
 	if err != nil {
 		app.logger.Error("failed to render upload page template", "err", err)
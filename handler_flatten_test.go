@@ -0,0 +1,44 @@
+package jsonedit
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRenderFlattenResultUnflatten(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want int
+	}{
+		{name: "sparse index", text: "a/1: x", want: http.StatusOK},
+		{name: "huge index", text: "a/999999999: x", want: http.StatusBadRequest},
+	}
+
+	app, err := NewApp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body bytes.Buffer
+			writer := multipart.NewWriter(&body)
+			_ = writer.WriteField("mode", "unflatten")
+			_ = writer.WriteField("format", "lines")
+			_ = writer.WriteField("flatText", tt.text)
+			_ = writer.Close()
+			req := httptest.NewRequest(http.MethodPost, "/flatten", &body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			rec := httptest.NewRecorder()
+
+			app.renderFlattenResult(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d, body %q", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+}
@@ -4,12 +4,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"html/template"
-	"io"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/sascha-andres/jsonedit/json/fromschema"
 )
 
+// maxRandomDocuments is the maximum number of random documents generated by one request
+const maxRandomDocuments = 1000
+
 // handleFromSchema processes a JSON schema file and generates an empty JSON document
 func (app *App) handleFromSchema(w http.ResponseWriter, r *http.Request) {
 	if r.Method == http.MethodPost {
@@ -23,7 +27,7 @@ func (app *App) handleFromSchema(w http.ResponseWriter, r *http.Request) {
 // renderJSONDocumentForm renders the JSON document form on a separate page
 func (app *App) renderJSONDocumentForm(w http.ResponseWriter, r *http.Request) {
 	tmpl := template.Must(template.New("compare").Parse(fromSchemaFormTemplate))
-	err := tmpl.Execute(w, nil)
+	err := tmpl.Execute(w, SchemaFormData{Workspace: app.workspace != nil, MaxDocuments: maxRandomDocuments})
 	if err != nil {
 		app.logger.Error("failed to render upload page template", "err", err)
 		http.Error(w, "Failed to render page", http.StatusInternalServerError)
@@ -40,33 +44,30 @@ func (app *App) renderJSONDocument(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get the file from the form
-	file, _, err := r.FormFile("schemaFile")
+	// Collect the schema files from the form
+	bundle, err := app.readSchemaBundle(r, "schemaFile")
 	if err != nil {
-		app.logger.Error("failed to get schema file from form", "err", err)
-		http.Error(w, "Failed to get schema file from form", http.StatusBadRequest)
+		app.logger.Error("failed to read schema files", "err", err)
+		http.Error(w, "Failed to read schema files: "+err.Error(), http.StatusBadRequest)
 		return
 	}
-	defer func() {
-		err := file.Close()
-		if err != nil {
-			app.logger.Error("failed to close schema file", "err", err)
-		}
-	}()
 
-	// Read the file content
-	content, err := io.ReadAll(file)
+	// Create a schema parser
+	schemaParser, err := fromschema.NewSchemaParserFromBundle(app.logger.With("module", "from_schema"), bundle)
 	if err != nil {
-		app.logger.Error("failed to read schema file", "err", err)
-		http.Error(w, "Failed to read schema file", http.StatusInternalServerError)
+		app.logger.Error("failed to create schema parser", "err", err)
+		http.Error(w, "Failed to parse JSON schema: "+err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// Create a schema parser
-	schemaParser, err := fromschema.NewSchemaParser(app.logger.With("module", "from_schema"), content)
+	err = chooseBranches(schemaParser, r)
 	if err != nil {
-		app.logger.Error("failed to create schema parser", "err", err)
-		http.Error(w, "Failed to parse JSON schema: "+err.Error(), http.StatusBadRequest)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if r.FormValue("mode") == "random" {
+		app.writeRandomDocuments(w, r, schemaParser)
 		return
 	}
 
@@ -99,3 +100,115 @@ func (app *App) renderJSONDocument(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+// writeRandomDocuments generates random documents satisfying the schema and sends them as JSON array or NDJSON download
+func (app *App) writeRandomDocuments(w http.ResponseWriter, r *http.Request, schemaParser *fromschema.SchemaParser) {
+	count, err := strconv.Atoi(r.FormValue("count"))
+	if err != nil || count < 1 || count > maxRandomDocuments {
+		http.Error(w, fmt.Sprintf("Number of documents must be between 1 and %d", maxRandomDocuments), http.StatusBadRequest)
+		return
+	}
+	options := []fromschema.GenerateOption{fromschema.WithOptionalProperties(r.FormValue("optional") == "true")}
+	if seed := r.FormValue("seed"); seed != "" {
+		value, err := strconv.ParseUint(seed, 10, 64)
+		if err != nil {
+			http.Error(w, "Seed must be a positive number", http.StatusBadRequest)
+			return
+		}
+		options = append(options, fromschema.WithSeed(value))
+	}
+
+	documents, err := schemaParser.GenerateDocuments(count, options...)
+	if err != nil {
+		app.logger.Error("failed to generate random documents", "err", err)
+		http.Error(w, "Failed to generate documents: "+err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	filename, contentType := "documents.json", "application/json"
+	var content []byte
+	if r.FormValue("output") == "ndjson" {
+		filename, contentType = "documents.ndjson", "application/x-ndjson"
+		for _, document := range documents {
+			line, err := json.Marshal(document)
+			if err != nil {
+				app.logger.Error("failed to format json data", "err", err)
+				http.Error(w, "Failed to format JSON", http.StatusInternalServerError)
+				return
+			}
+			content = append(append(content, line...), '\n')
+		}
+	} else {
+		content, err = json.MarshalIndent(documents, "", app.indent)
+		if err != nil {
+			app.logger.Error("failed to format json data", "err", err)
+			http.Error(w, "Failed to format JSON", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// Set headers for file download
+	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(content)))
+
+	_, err = w.Write(content)
+	if err != nil {
+		app.logger.Error("failed to write random documents", "err", err)
+	}
+}
+
+// handleFromSchemaChoices renders a select for every oneOf and anyOf of the uploaded schema
+func (app *App) handleFromSchemaChoices(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	err := r.ParseMultipartForm(10 << 20) // 10 MB max
+	if err != nil {
+		app.logger.Error("failed to parse form", "err", err)
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	bundle, err := app.readSchemaBundle(r, "schemaFile")
+	if err != nil {
+		app.logger.Error("failed to read schema files", "err", err)
+		http.Error(w, "Failed to read schema files: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	schemaParser, err := fromschema.NewSchemaParserFromBundle(app.logger.With("module", "from_schema"), bundle)
+	if err != nil {
+		app.logger.Error("failed to create schema parser", "err", err)
+		http.Error(w, "Failed to parse JSON schema: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = chooseBranches(schemaParser, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tmpl := template.Must(template.New("choices").Parse(fromSchemaChoicesTemplate))
+	err = tmpl.Execute(w, schemaParser.Choices())
+	if err != nil {
+		app.logger.Error("failed to render branch choices template", "err", err)
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
+
+// chooseBranches selects the oneOf and anyOf branches submitted as location=index values of the branch field
+func chooseBranches(schemaParser *fromschema.SchemaParser, r *http.Request) error {
+	for _, value := range r.Form["branch"] {
+		separator := strings.LastIndex(value, "=")
+		if separator < 0 {
+			return fmt.Errorf("invalid branch %q", value)
+		}
+		branch, err := strconv.Atoi(value[separator+1:])
+		if err != nil {
+			return fmt.Errorf("invalid branch %q", value)
+		}
+		schemaParser.Choose(value[:separator], branch)
+	}
+	return nil
+}
@@ -8,7 +8,12 @@ import (
 // handleUploadPage displays the upload form
 func (app *App) handleIndex(w http.ResponseWriter, _ *http.Request) {
 	tmpl := template.Must(template.New("upload").Parse(indexTemplate))
-	err := tmpl.Execute(w, nil)
+	data := struct {
+		Workspace bool
+	}{
+		Workspace: app.workspace != nil,
+	}
+	err := tmpl.Execute(w, data)
 	if err != nil {
 		app.logger.Error("failed to render index template", "err", err)
 		http.Error(w, "Failed to render page", http.StatusInternalServerError)
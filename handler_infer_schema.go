@@ -0,0 +1,147 @@
+package jsonedit
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/sascha-andres/jsonedit/json"
+	"github.com/sascha-andres/jsonedit/json/infer"
+	"github.com/sascha-andres/jsonedit/json/validate"
+)
+
+// handleInferSchema infers a JSON schema from sample documents
+func (app *App) handleInferSchema(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		app.renderInferSchemaResult(w, r)
+	}
+	if r.Method == http.MethodGet {
+		app.renderInferSchemaForm(w, r)
+	}
+}
+
+// renderInferSchemaForm renders the schema inference form on a separate page
+func (app *App) renderInferSchemaForm(w http.ResponseWriter, _ *http.Request) {
+	tmpl := template.Must(template.New("infer").Parse(inferSchemaFormTemplate))
+	err := tmpl.Execute(w, nil)
+	if err != nil {
+		app.logger.Error("failed to render upload page template", "err", err)
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
+
+// renderInferSchemaResult infers a schema from the uploaded or pasted samples and renders it
+// together with forms handing it to the validate, from-schema and edit tools
+func (app *App) renderInferSchemaResult(w http.ResponseWriter, r *http.Request) {
+	// Parse the multipart form
+	err := r.ParseMultipartForm(10 << 20) // 10 MB max
+	if err != nil {
+		app.logger.Error("failed to parse form", "err", err)
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	samples, err := app.readSamples(r, "sampleFiles", "sampleText")
+	if err != nil {
+		http.Error(w, "Invalid samples: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	options := []infer.Option{infer.WithFormats(r.FormValue("detectFormats") == "true")}
+	if limit := r.FormValue("enumLimit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			http.Error(w, "Enum limit must be a number of at least 0", http.StatusBadRequest)
+			return
+		}
+		options = append(options, infer.WithEnumLimit(n))
+	}
+	schema, err := infer.Infer(samples, options...)
+	if err != nil {
+		http.Error(w, "Failed to infer schema: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	content, err := schema.Format("", app.indent)
+	if err != nil {
+		app.logger.Error("failed to format schema", "err", err)
+		http.Error(w, "Failed to format schema", http.StatusInternalServerError)
+		return
+	}
+	sample, err := samples[0].Format("", app.indent)
+	if err != nil {
+		app.logger.Error("failed to format sample", "err", err)
+		http.Error(w, "Failed to format sample", http.StatusInternalServerError)
+		return
+	}
+
+	tmpl := template.Must(template.New("infer").Parse(inferSchemaResultTemplate))
+	err = tmpl.Execute(w, InferSchemaResultData{Schema: string(content), Sample: string(sample), Samples: len(samples)})
+	if err != nil {
+		app.logger.Error("failed to render schema inference result template", "err", err)
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
+
+// handleInferSchemaDownload sends the inferred schema as file download
+func (app *App) handleInferSchemaDownload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	err := r.ParseForm()
+	if err != nil {
+		app.logger.Error("failed to parse form", "err", err)
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+	content := []byte(r.FormValue("schema"))
+
+	// Set headers for file download
+	w.Header().Set("Content-Disposition", "attachment; filename=schema.json")
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(content)))
+
+	_, err = w.Write(content)
+	if err != nil {
+		app.logger.Error("failed to write inferred schema", "err", err)
+	}
+}
+
+// readSamples parses the sample documents uploaded in fileField, JSON, NDJSON or zip archives, and
+// pasted in textField, either a single document or one document per line
+func (app *App) readSamples(r *http.Request, fileField, textField string) ([]*json.Node, error) {
+	documents := make([]validate.Document, 0)
+	if r.MultipartForm != nil && len(r.MultipartForm.File[fileField]) > 0 {
+		var err error
+		documents, _, err = app.readDocuments(r, fileField)
+		if err != nil {
+			app.logger.Error("failed to read sample documents", "err", err)
+			return nil, fmt.Errorf("failed to read sample documents: %w", err)
+		}
+	}
+	if text := strings.TrimSpace(r.FormValue(textField)); text != "" {
+		if _, err := json.Parse([]byte(text)); err == nil {
+			documents = append(documents, validate.Document{Name: "pasted", Content: []byte(text)})
+		} else {
+			// Several pasted documents are read as NDJSON
+			lines, err := validate.ReadNDJSON("pasted", []byte(text))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read pasted samples: %w", err)
+			}
+			documents = append(documents, lines...)
+		}
+	}
+
+	samples := make([]*json.Node, 0, len(documents))
+	for _, document := range documents {
+		sample, err := json.Parse(document.Content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse sample %s: %w", document.Name, err)
+		}
+		samples = append(samples, sample)
+	}
+	return samples, nil
+}
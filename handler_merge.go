@@ -0,0 +1,184 @@
+package jsonedit
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+
+	"github.com/sascha-andres/jsonedit/json"
+	"github.com/sascha-andres/jsonedit/json/form"
+	"github.com/sascha-andres/jsonedit/json/merge"
+)
+
+// mergeConflictView is a conflict as shown on the conflict resolution page
+type mergeConflictView struct {
+
+	// Path is the JSON Pointer of the conflicting value
+	Path string
+
+	// Base is the compact value in the common base
+	Base string
+
+	// Ours is the compact value in our document
+	Ours string
+
+	// Theirs is the compact value in their document
+	Theirs string
+}
+
+// handleMerge merges two edited copies of a JSON file with their common base
+func (app *App) handleMerge(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		app.renderMergeResult(w, r)
+	}
+	if r.Method == http.MethodGet {
+		app.renderMergeForm(w)
+	}
+}
+
+// renderMergeResult merges the uploaded files and shows the conflicts or opens the result in the editor
+func (app *App) renderMergeResult(w http.ResponseWriter, r *http.Request) {
+	// Parse the multipart form
+	err := r.ParseMultipartForm(10 << 20) // 10 MB max
+	if err != nil {
+		app.logger.Error("failed to parse form", "err", err)
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	var contents [3][]byte
+	for i, name := range []string{"jsonFileBase", "jsonFileOurs", "jsonFileTheirs"} {
+		contents[i], err = app.readFormFile(r, name)
+		if err != nil {
+			app.logger.Error("failed to read file from form", "name", name, "err", err)
+			http.Error(w, "Failed to read file "+name, http.StatusBadRequest)
+			return
+		}
+	}
+	base, ours, theirs, ok := app.parseMergeDocuments(w, contents[0], contents[1], contents[2])
+	if !ok {
+		return
+	}
+
+	result := merge.Merge(base, ours, theirs)
+	if len(result.Conflicts) == 0 {
+		app.renderDocumentEditor(w, result.Document)
+		return
+	}
+
+	conflicts := make([]mergeConflictView, len(result.Conflicts))
+	for i, conflict := range result.Conflicts {
+		conflicts[i] = mergeConflictView{
+			Path:   conflict.Path,
+			Base:   mergeValue(conflict.Base),
+			Ours:   mergeValue(conflict.Ours),
+			Theirs: mergeValue(conflict.Theirs),
+		}
+	}
+	data := MergeConflictsData{
+		Conflicts: conflicts,
+		Base:      string(contents[0]),
+		Ours:      string(contents[1]),
+		Theirs:    string(contents[2]),
+	}
+
+	tmpl := template.Must(template.New("merge").Parse(mergeConflictsTemplate))
+	err = tmpl.Execute(w, data)
+	if err != nil {
+		app.logger.Error("failed to render merge conflicts template", "err", err)
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
+
+// handleMergeResolve merges the documents again using the picked sides and opens the result in the editor
+func (app *App) handleMergeResolve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	err := r.ParseForm()
+	if err != nil {
+		app.logger.Error("failed to parse form", "err", err)
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	base, ours, theirs, ok := app.parseMergeDocuments(w, []byte(r.FormValue("base")), []byte(r.FormValue("ours")), []byte(r.FormValue("theirs")))
+	if !ok {
+		return
+	}
+
+	var options []merge.Option
+	for i := 0; ; i++ {
+		path, ok := r.Form[fmt.Sprintf("conflictPath%d", i)]
+		if !ok || len(path) == 0 {
+			break
+		}
+		side := merge.Ours
+		if r.FormValue(fmt.Sprintf("resolution%d", i)) == string(merge.Theirs) {
+			side = merge.Theirs
+		}
+		options = append(options, merge.WithResolution(path[0], side))
+	}
+
+	result := merge.Merge(base, ours, theirs, options...)
+	app.renderDocumentEditor(w, result.Document)
+}
+
+// parseMergeDocuments parses the three documents of a merge, writing an error response if one is invalid
+func (app *App) parseMergeDocuments(w http.ResponseWriter, baseContent, oursContent, theirsContent []byte) (*json.Node, *json.Node, *json.Node, bool) {
+	base, err := json.Parse(baseContent)
+	if err != nil {
+		http.Error(w, "Failed to parse base JSON file: "+err.Error(), http.StatusBadRequest)
+		return nil, nil, nil, false
+	}
+	ours, err := json.Parse(oursContent)
+	if err != nil {
+		http.Error(w, "Failed to parse our JSON file: "+err.Error(), http.StatusBadRequest)
+		return nil, nil, nil, false
+	}
+	theirs, err := json.Parse(theirsContent)
+	if err != nil {
+		http.Error(w, "Failed to parse their JSON file: "+err.Error(), http.StatusBadRequest)
+		return nil, nil, nil, false
+	}
+	return base, ours, theirs, true
+}
+
+// renderDocumentEditor opens document in the editor
+func (app *App) renderDocumentEditor(w http.ResponseWriter, document *json.Node) {
+	prettyJSON, err := document.Format("", app.indent)
+	if err != nil {
+		app.logger.Error("failed to format json data", "err", err)
+		http.Error(w, "Failed to format JSON", http.StatusInternalServerError)
+		return
+	}
+
+	// Generate form elements for each JSON field
+	formContent := form.GenerateJSONForm(app.logger.With("module", "form"), app.readOnly, document, "", 0)
+
+	data := EditPageData{
+		Content:     string(prettyJSON),
+		FormContent: template.HTML(formContent),
+		ReadOnly:    app.readOnly,
+	}
+	app.renderEditPage(w, data)
+}
+
+// renderMergeForm renders the merge form
+func (app *App) renderMergeForm(w http.ResponseWriter) {
+	tmpl := template.Must(template.New("merge").Parse(mergeFormTemplate))
+	err := tmpl.Execute(w, nil)
+	if err != nil {
+		app.logger.Error("failed to render merge page template", "err", err)
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
+
+// mergeValue returns the compact JSON of a conflicting value, a placeholder if it does not exist
+func mergeValue(node *json.Node) string {
+	if node == nil {
+		return "(missing)"
+	}
+	return compactValue(node)
+}
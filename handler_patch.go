@@ -0,0 +1,116 @@
+package jsonedit
+
+import (
+	"html/template"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/sascha-andres/jsonedit/json"
+	"github.com/sascha-andres/jsonedit/json/form"
+	"github.com/sascha-andres/jsonedit/json/patch"
+)
+
+// handlePatch applies a JSON Patch or Merge Patch to a JSON file
+func (app *App) handlePatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		app.renderPatchResult(w, r)
+	}
+	if r.Method == http.MethodGet {
+		app.renderPatchForm(w, "")
+	}
+}
+
+// renderPatchResult applies the uploaded patch and opens the result in the editor
+func (app *App) renderPatchResult(w http.ResponseWriter, r *http.Request) {
+	// Parse the multipart form
+	err := r.ParseMultipartForm(10 << 20) // 10 MB max
+	if err != nil {
+		app.logger.Error("failed to parse form", "err", err)
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	content, err := app.readFormFile(r, "jsonFilePatch")
+	if err != nil {
+		app.logger.Error("failed to read JSON file from form", "err", err)
+		http.Error(w, "Failed to read JSON file", http.StatusBadRequest)
+		return
+	}
+	patchContent, err := app.readFormFile(r, "patchFile")
+	if err != nil {
+		app.logger.Error("failed to read patch file from form", "err", err)
+		http.Error(w, "Failed to read patch file", http.StatusBadRequest)
+		return
+	}
+
+	doc, err := json.Parse(content)
+	if err != nil {
+		app.renderPatchForm(w, "Failed to parse JSON file: "+err.Error())
+		return
+	}
+	patchDoc, err := json.Parse(patchContent)
+	if err != nil {
+		app.renderPatchForm(w, "Failed to parse patch file: "+err.Error())
+		return
+	}
+
+	// Apply the patch in the selected format
+	var result *json.Node
+	if r.FormValue("patchType") == "merge-patch" {
+		result = patch.ApplyMergePatch(doc, patchDoc)
+	} else {
+		result, err = patch.ApplyJSONPatch(doc, patchDoc)
+		if err != nil {
+			app.renderPatchForm(w, "Failed to apply patch: "+err.Error())
+			return
+		}
+	}
+
+	// Pretty print the JSON
+	prettyJSON, err := result.Format("", app.indent)
+	if err != nil {
+		app.logger.Error("failed to format json data", "err", err)
+		http.Error(w, "Failed to format JSON", http.StatusInternalServerError)
+		return
+	}
+
+	// Generate form elements for each JSON field
+	formContent := form.GenerateJSONForm(app.logger.With("module", "form"), app.readOnly, result, "", 0)
+
+	data := EditPageData{
+		Content:     string(prettyJSON),
+		FormContent: template.HTML(formContent),
+		ReadOnly:    app.readOnly,
+	}
+	app.renderEditPage(w, data)
+}
+
+// readFormFile reads the content of the uploaded file name
+func (app *App) readFormFile(r *http.Request, name string) ([]byte, error) {
+	file, _, err := r.FormFile(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func(file multipart.File) {
+		err := file.Close()
+		if err != nil {
+			app.logger.Error("failed to close file", "name", name, "err", err)
+		}
+	}(file)
+	return io.ReadAll(file)
+}
+
+// renderPatchForm renders the patch form, showing message as error if not empty
+func (app *App) renderPatchForm(w http.ResponseWriter, message string) {
+	tmpl := template.Must(template.New("patch").Parse(patchFormTemplate))
+	err := tmpl.Execute(w, struct {
+		Error string
+	}{
+		Error: message,
+	})
+	if err != nil {
+		app.logger.Error("failed to render patch page template", "err", err)
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
@@ -1,11 +1,11 @@
 package jsonedit
 
 import (
-	"encoding/json"
 	"fmt"
 	"html/template"
 	"net/http"
 
+	"github.com/sascha-andres/jsonedit/json"
 	"github.com/sascha-andres/jsonedit/json/form"
 )
 
@@ -19,12 +19,16 @@ func (app *App) handleSave(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get the JSON content from the form
-	jsonContent := r.FormValue("jsonContent")
+	// Get the JSON content from the form with the edits made in the form applied
+	jsonContent, err := app.editedContent(r)
+	if err != nil {
+		app.logger.Error("failed to apply edits", "err", err)
+		http.Error(w, "Failed to apply edits: "+err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	// Validate JSON
-	var jsonData interface{}
-	err = json.Unmarshal([]byte(jsonContent), &jsonData)
+	jsonData, err := json.Parse([]byte(jsonContent))
 	if err != nil {
 		// Try to create a simple object with the content as a string
 		simpleData := map[string]interface{}{
@@ -38,13 +42,20 @@ func (app *App) handleSave(w http.ResponseWriter, r *http.Request) {
 			Error:       "Invalid JSON: " + err.Error(),
 			FormContent: template.HTML(formContent),
 			ReadOnly:    app.readOnly,
+			Schema:      r.FormValue("schema"),
 		}
 		app.renderEditPage(w, data)
 		return
 	}
 
+	// Check the document against the schema attached in the editor, the editor asks before saving an invalid document
+	if message, blocked := app.checkSaveAgainstSchema(r.FormValue("schema"), []byte(jsonContent)); blocked {
+		http.Error(w, message, http.StatusUnprocessableEntity)
+		return
+	}
+
 	// Pretty print the JSON
-	prettyJSON, err := json.MarshalIndent(jsonData, "", app.indent)
+	prettyJSON, err := jsonData.Format("", app.indent)
 	if err != nil {
 		app.logger.Error("failed to format json data", "err", err)
 		http.Error(w, "Failed to format JSON", http.StatusInternalServerError)
@@ -0,0 +1,87 @@
+package jsonedit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestHandleSaveAppliesEdits(t *testing.T) {
+	const document = `{"b":1,"2":1.0,"id":12345678901234567890}`
+
+	tests := []struct {
+		name  string
+		edits string
+		want  string
+	}{
+		{
+			name:  "no edits",
+			edits: "",
+			want:  document,
+		},
+		{
+			name:  "unchanged value",
+			edits: `[{"op":"set","path":"b","type":"number","value":"1"}]`,
+			want:  document,
+		},
+		{
+			name:  "large integer",
+			edits: `[{"op":"set","path":"id","type":"number","value":"98765432109876543210"}]`,
+			want:  `{"b":1,"2":1.0,"id":98765432109876543210}`,
+		},
+		{
+			name:  "number literal kept",
+			edits: `[{"op":"set","path":"b","type":"number","value":"2.50"}]`,
+			want:  `{"b":2.50,"2":1.0,"id":12345678901234567890}`,
+		},
+		{
+			name:  "add and delete",
+			edits: `[{"op":"add","path":"","key":"c","type":"string","value":"x"},{"op":"delete","path":"","key":"b"}]`,
+			want:  `{"2":1.0,"id":12345678901234567890,"c":"x"}`,
+		},
+	}
+
+	app, err := NewApp(WithIndent(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form := url.Values{"jsonContent": {document}, "edits": {tt.edits}}
+			req := httptest.NewRequest(http.MethodPost, "/save", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			rec := httptest.NewRecorder()
+
+			app.handleSave(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %q", rec.Code, rec.Body.String())
+			}
+			if got := rec.Body.String(); got != tt.want {
+				t.Errorf("body = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleSaveRejectsInvalidEdits(t *testing.T) {
+	app, err := NewApp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	form := url.Values{
+		"jsonContent": {`{"a":1}`},
+		"edits":       {`[{"op":"set","path":"a","type":"number","value":"1,5"}]`},
+	}
+	req := httptest.NewRequest(http.MethodPost, "/save", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+
+	app.handleSave(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
@@ -1,11 +1,11 @@
 package jsonedit
 
 import (
-	"encoding/json"
 	"html/template"
 	"io"
 	"net/http"
 
+	"github.com/sascha-andres/jsonedit/json"
 	"github.com/sascha-andres/jsonedit/json/form"
 )
 
@@ -51,8 +51,7 @@ func (app *App) handleUpload(w http.ResponseWriter, r *http.Request) {
 		}
 
 		// Validate JSON
-		var jsonData interface{}
-		err = json.Unmarshal(content, &jsonData)
+		jsonData, err := json.Parse(content)
 		if err != nil {
 			// Try to create a simple object with the content as a string
 			simpleData := map[string]interface{}{
@@ -71,7 +70,7 @@ func (app *App) handleUpload(w http.ResponseWriter, r *http.Request) {
 		}
 
 		// Pretty print the JSON
-		prettyJSON, err := json.MarshalIndent(jsonData, "", app.indent)
+		prettyJSON, err := jsonData.Format("", app.indent)
 		if err != nil {
 			app.logger.Error("failed to marshal json data", "err", err)
 			http.Error(w, "Failed to format JSON", http.StatusInternalServerError)
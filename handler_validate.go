@@ -1,10 +1,18 @@
 package jsonedit
 
 import (
+	stdJson "encoding/json"
+	"errors"
+	"fmt"
 	"html/template"
 	"io"
+	"mime/multipart"
 	"net/http"
+	"os"
+	"path"
+	"strings"
 
+	"github.com/sascha-andres/jsonedit/json"
 	"github.com/sascha-andres/jsonedit/json/validate"
 )
 
@@ -21,7 +29,7 @@ func (app *App) handleValidate(w http.ResponseWriter, r *http.Request) {
 // renderValidateForm renders the validation form on a separate page
 func (app *App) renderValidateForm(w http.ResponseWriter, _ *http.Request) {
 	tmpl := template.Must(template.New("compare").Parse(validateFormTemplate))
-	err := tmpl.Execute(w, nil)
+	err := tmpl.Execute(w, SchemaFormData{Workspace: app.workspace != nil})
 	if err != nil {
 		app.logger.Error("failed to render upload page template", "err", err)
 		http.Error(w, "Failed to render page", http.StatusInternalServerError)
@@ -38,81 +46,229 @@ func (app *App) renderValidateResult(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get the schema file from the form
-	schemaFile, _, err := r.FormFile("schemaFileValidate")
+	// Collect the schema files from the form
+	bundle, err := app.readSchemaBundle(r, "schemaFileValidate")
 	if err != nil {
-		app.logger.Error("failed to get schema file from form", "err", err)
-		http.Error(w, "Failed to get schema file from form", http.StatusBadRequest)
+		app.logger.Error("failed to read schema files", "err", err)
+		http.Error(w, "Failed to read schema files: "+err.Error(), http.StatusBadRequest)
 		return
 	}
-	defer func() {
-		err := schemaFile.Close()
-		if err != nil {
-			app.logger.Error("failed to close schema file", "err", err)
-		}
-	}()
 
-	// Get the JSON document file from the form
-	jsonFile, _, err := r.FormFile("jsonFileValidate")
+	// Collect the JSON documents from the form
+	documents, batch, err := app.readDocuments(r, "jsonFileValidate")
 	if err != nil {
-		app.logger.Error("failed to get JSON document file from form", "err", err)
-		http.Error(w, "Failed to get JSON document file from form", http.StatusBadRequest)
+		app.logger.Error("failed to read JSON documents", "err", err)
+		http.Error(w, "Failed to read JSON documents: "+err.Error(), http.StatusBadRequest)
 		return
 	}
-	defer func() {
-		err := jsonFile.Close()
-		if err != nil {
-			app.logger.Error("failed to close JSON document file", "err", err)
+
+	// Create a new JSON validator with the schema, compiled once for all documents
+	validator, err := validate.NewJSONValidator(append(validatorOptions(r),
+		validate.WithJSONSchemaBundle(bundle),
+		validate.WithLogger(app.logger.With("module", "validate")),
+	)...)
+	if err != nil {
+		app.logger.Error("failed to create JSON validator", "err", err)
+		http.Error(w, "Failed to create JSON validator: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if batch {
+		app.renderValidateBatchResult(w, bundle.MainName(), validator.ValidateBatch(documents))
+		return
+	}
+
+	// Validate the JSON document against the schema
+	jsonContent := documents[0].Content
+	data := ValidateResultData{Violations: []validate.Violation{}, Document: string(jsonContent)}
+	if validationErr := validator.ValidateDocument(jsonContent); validationErr != nil {
+		var violations *validate.ValidationError
+		if errors.As(validationErr, &violations) {
+			data.Violations = violations.Violations
+		} else {
+			data.Error = validationErr.Error()
 		}
-	}()
+	}
 
-	// Read the schema file content
-	schemaContent, err := io.ReadAll(schemaFile)
+	// Render the validation result
+	tmpl := template.Must(template.New("validate").Parse(validateResultTemplate))
+	err = tmpl.Execute(w, data)
 	if err != nil {
-		app.logger.Error("failed to read schema file", "err", err)
-		http.Error(w, "Failed to read schema file", http.StatusInternalServerError)
+		app.logger.Error("failed to render validation result template", "err", err)
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
+
+// renderValidateBatchResult renders the summary and the per document results of a batch validation
+func (app *App) renderValidateBatchResult(w http.ResponseWriter, schema string, result *validate.BatchResult) {
+	export, err := stdJson.Marshal(result)
+	if err != nil {
+		app.logger.Error("failed to format validation result", "err", err)
+		http.Error(w, "Failed to format validation result", http.StatusInternalServerError)
 		return
 	}
 
-	// Read the JSON document file content
-	jsonContent, err := io.ReadAll(jsonFile)
+	tmpl := template.Must(template.New("validate").Parse(validateBatchResultTemplate))
+	err = tmpl.Execute(w, ValidateBatchResultData{Schema: schema, Result: result, Export: string(export)})
 	if err != nil {
-		app.logger.Error("failed to read JSON document file", "err", err)
-		http.Error(w, "Failed to read JSON document file", http.StatusInternalServerError)
+		app.logger.Error("failed to render validation result template", "err", err)
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
+
+// handleValidateExport returns the submitted batch validation result as JSON or JUnit XML file download
+func (app *App) handleValidateExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	err := r.ParseForm()
+	if err != nil {
+		app.logger.Error("failed to parse form", "err", err)
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
 		return
 	}
 
-	// Create a new JSON validator with the schema and document
-	validator, err := validate.NewJSONValidator(
-		validate.WithJSONSchema(schemaContent),
-		validate.WithJSONDocument(jsonContent),
-		validate.WithLogger(app.logger.With("module", "validate")),
-	)
+	var result validate.BatchResult
+	if err := stdJson.Unmarshal([]byte(r.FormValue("result")), &result); err != nil {
+		http.Error(w, "Failed to parse validation result: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var content []byte
+	filename, contentType := "validation.json", "application/json"
+	switch r.FormValue("format") {
+	case "junit":
+		content, err = result.JUnit(r.FormValue("schema"))
+		filename, contentType = "validation.xml", "application/xml"
+	default:
+		content, err = stdJson.MarshalIndent(result, "", app.indent)
+	}
 	if err != nil {
-		app.logger.Error("failed to create JSON validator", "err", err)
-		http.Error(w, "Failed to create JSON validator: "+err.Error(), http.StatusInternalServerError)
+		app.logger.Error("failed to format validation result", "err", err)
+		http.Error(w, "Failed to format validation result", http.StatusInternalServerError)
 		return
 	}
 
-	// Validate the JSON document against the schema
-	validationErr := validator.Validate()
+	// Set headers for file download
+	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(content)))
 
-	// Render the validation result
-	tmpl := template.Must(template.New("validate").Parse(validateResultTemplate))
+	_, err = w.Write(content)
+	if err != nil {
+		app.logger.Error("failed to write validation result", "err", err)
+	}
+}
 
-	data := struct {
-		Error string
-	}{
-		Error: "",
+// validatorOptions returns the draft and assertion options selected in the submitted form
+func validatorOptions(r *http.Request) []validate.JsonValidatorOption {
+	return []validate.JsonValidatorOption{
+		validate.WithDefaultDraft(r.FormValue("draft")),
+		validate.WithFormatAssertion(r.FormValue("assertFormat") == "true"),
+		validate.WithContentAssertion(r.FormValue("assertContent") == "true"),
 	}
+}
 
-	if validationErr != nil {
-		data.Error = validationErr.Error()
+// readDocuments collects the JSON documents uploaded as field. Zip archives are unpacked and NDJSON
+// files, with the extension .ndjson or .jsonl, are split into one document per line. batch is false
+// if a single JSON file was uploaded.
+func (app *App) readDocuments(r *http.Request, field string) (documents []validate.Document, batch bool, err error) {
+	var headers []*multipart.FileHeader
+	if r.MultipartForm != nil {
+		headers = r.MultipartForm.File[field]
+	}
+	if len(headers) == 0 {
+		return nil, false, errors.New("no JSON document uploaded")
 	}
 
-	err = tmpl.Execute(w, data)
+	for _, header := range headers {
+		content, err := app.readFileHeader(header)
+		if err != nil {
+			return nil, false, err
+		}
+		switch {
+		case strings.EqualFold(path.Ext(header.Filename), ".zip"):
+			archived, err := validate.ReadZip(content)
+			if err != nil {
+				return nil, false, err
+			}
+			documents = append(documents, archived...)
+			batch = true
+		case validate.IsNDJSON(header.Filename):
+			lines, err := validate.ReadNDJSON(header.Filename, content)
+			if err != nil {
+				return nil, false, err
+			}
+			documents = append(documents, lines...)
+			batch = true
+		default:
+			documents = append(documents, validate.Document{Name: header.Filename, Content: content})
+		}
+	}
+	return documents, batch || len(headers) > 1, nil
+}
+
+// readSchemaBundle collects the schema files of a form. A schema pasted into the field schemaText
+// is used as single file. In workspace mode the field schemaPath may name the main schema within
+// the workspace, its directory is read as bundle. Otherwise the files uploaded as field are used,
+// zip archives are unpacked. The optional field schemaMain names the main schema of uploaded files,
+// the first file is used if it is empty.
+func (app *App) readSchemaBundle(r *http.Request, field string) (*json.SchemaBundle, error) {
+	bundle := json.NewSchemaBundle()
+
+	if schemaText := r.FormValue("schemaText"); strings.TrimSpace(schemaText) != "" {
+		bundle.Add("schema.json", []byte(schemaText))
+		return bundle, nil
+	}
+
+	if schemaPath := r.FormValue("schemaPath"); schemaPath != "" && app.workspace != nil {
+		dir, err := app.workspace.Resolve(path.Dir(schemaPath))
+		if err != nil {
+			return nil, err
+		}
+		if err := bundle.AddFS(os.DirFS(dir), "."); err != nil {
+			return nil, err
+		}
+		bundle.Main = path.Base(schemaPath)
+		return bundle, nil
+	}
+
+	var headers []*multipart.FileHeader
+	if r.MultipartForm != nil {
+		headers = r.MultipartForm.File[field]
+	}
+	if len(headers) == 0 {
+		return nil, errors.New("no schema file uploaded")
+	}
+	for _, header := range headers {
+		content, err := app.readFileHeader(header)
+		if err != nil {
+			return nil, err
+		}
+		if strings.EqualFold(path.Ext(header.Filename), ".zip") {
+			if err := bundle.AddZip(content); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		bundle.Add(header.Filename, content)
+	}
+	bundle.Main = r.FormValue("schemaMain")
+	return bundle, nil
+}
+
+// readFileHeader reads the content of an uploaded file
+func (app *App) readFileHeader(header *multipart.FileHeader) ([]byte, error) {
+	file, err := header.Open()
 	if err != nil {
-		app.logger.Error("failed to render validation result template", "err", err)
-		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+		return nil, err
 	}
+	defer func(file multipart.File) {
+		err := file.Close()
+		if err != nil {
+			app.logger.Error("failed to close file", "name", header.Filename, "err", err)
+		}
+	}(file)
+	return io.ReadAll(file)
 }
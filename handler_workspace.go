@@ -0,0 +1,195 @@
+package jsonedit
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"os"
+
+	"github.com/sascha-andres/jsonedit/internal/workspace"
+	"github.com/sascha-andres/jsonedit/json"
+	"github.com/sascha-andres/jsonedit/json/form"
+)
+
+// handleFiles renders the directory tree of the workspace
+func (app *App) handleFiles(w http.ResponseWriter, _ *http.Request) {
+	if app.workspace == nil {
+		http.Error(w, "Workspace mode is not enabled", http.StatusNotFound)
+		return
+	}
+
+	entries, err := app.workspace.List()
+	if err != nil {
+		app.logger.Error("failed to list workspace", "err", err)
+		http.Error(w, "Failed to list workspace", http.StatusInternalServerError)
+		return
+	}
+
+	tmpl := template.Must(template.New("files").Parse(filesTemplate))
+	data := struct {
+		Root    string
+		Entries []workspace.Entry
+	}{
+		Root:    app.workspace.Root(),
+		Entries: entries,
+	}
+	err = tmpl.Execute(w, data)
+	if err != nil {
+		app.logger.Error("failed to render files template", "err", err)
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
+
+// handleOpen reads a file from the workspace and renders the edit page for it
+func (app *App) handleOpen(w http.ResponseWriter, r *http.Request) {
+	if app.workspace == nil {
+		http.Error(w, "Workspace mode is not enabled", http.StatusNotFound)
+		return
+	}
+
+	path := r.URL.Query().Get("path")
+	if path == "" {
+		http.Error(w, "Missing path", http.StatusBadRequest)
+		return
+	}
+
+	content, err := app.workspace.ReadFile(path)
+	if err != nil {
+		app.logger.Error("failed to read workspace file", "path", path, "err", err)
+		if errors.Is(err, workspace.ErrOutsideRoot) {
+			http.Error(w, "Path is outside of workspace", http.StatusForbidden)
+			return
+		}
+		if errors.Is(err, os.ErrNotExist) {
+			http.Error(w, "File not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to read file", http.StatusInternalServerError)
+		return
+	}
+
+	app.renderWorkspaceEditPage(w, content, path, "", "")
+}
+
+// handleSaveFile writes the edited JSON back to its file in the workspace
+func (app *App) handleSaveFile(w http.ResponseWriter, r *http.Request) {
+	if app.workspace == nil {
+		http.Error(w, "Workspace mode is not enabled", http.StatusNotFound)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if app.readOnly {
+		http.Error(w, "Saving is disabled in read-only mode", http.StatusForbidden)
+		return
+	}
+
+	err := r.ParseForm()
+	if err != nil {
+		app.logger.Error("failed to parse form", "err", err)
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	path := r.FormValue("filePath")
+	if path == "" {
+		http.Error(w, "Missing path", http.StatusBadRequest)
+		return
+	}
+	jsonContent, err := app.editedContent(r)
+	if err != nil {
+		app.logger.Error("failed to apply edits", "err", err)
+		http.Error(w, "Failed to apply edits: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	schema := r.FormValue("schema")
+
+	// Validate JSON
+	jsonData, err := json.Parse([]byte(jsonContent))
+	if err != nil {
+		app.renderWorkspaceEditPage(w, []byte(jsonContent), path, schema, "")
+		return
+	}
+
+	// Check the document against the schema attached in the editor
+	warning, blocked := app.checkSaveAgainstSchema(schema, []byte(jsonContent))
+	if blocked {
+		app.renderWorkspaceEditPage(w, []byte(jsonContent), path, schema, "Not saved: "+warning)
+		return
+	}
+
+	// Pretty print the JSON
+	prettyJSON, err := jsonData.Format("", app.indent)
+	if err != nil {
+		app.logger.Error("failed to format json data", "err", err)
+		http.Error(w, "Failed to format JSON", http.StatusInternalServerError)
+		return
+	}
+	prettyJSON = append(prettyJSON, '\n')
+
+	err = app.workspace.WriteFile(path, prettyJSON, app.backup)
+	if err != nil {
+		app.logger.Error("failed to write workspace file", "path", path, "err", err)
+		if errors.Is(err, workspace.ErrOutsideRoot) {
+			http.Error(w, "Path is outside of workspace", http.StatusForbidden)
+			return
+		}
+		http.Error(w, "Failed to write file: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	app.logger.Info("saved workspace file", "path", path)
+	message := "Saved " + path
+	if warning != "" {
+		message += ". " + warning
+	}
+	app.renderWorkspaceEditPage(w, prettyJSON, path, schema, message)
+}
+
+// renderWorkspaceEditPage renders the edit page for content read from or written to the workspace file at path,
+// keeping the schema attached to the edit session
+func (app *App) renderWorkspaceEditPage(w http.ResponseWriter, content []byte, path, schema, message string) {
+	// Validate JSON
+	jsonData, err := json.Parse(content)
+	if err != nil {
+		// Try to create a simple object with the content as a string
+		simpleData := map[string]interface{}{
+			"content": string(content),
+		}
+		formContent := form.GenerateJSONForm(app.logger.With("module", "form"), app.readOnly, simpleData, "", 0)
+
+		data := EditPageData{
+			Content:     string(content),
+			Error:       "Invalid JSON: " + err.Error(),
+			FormContent: template.HTML(formContent),
+			ReadOnly:    app.readOnly,
+			Path:        path,
+			Schema:      schema,
+		}
+		app.renderEditPage(w, data)
+		return
+	}
+
+	// Pretty print the JSON
+	prettyJSON, err := jsonData.Format("", app.indent)
+	if err != nil {
+		app.logger.Error("failed to marshal json data", "err", err)
+		http.Error(w, "Failed to format JSON", http.StatusInternalServerError)
+		return
+	}
+
+	// Generate form elements for each JSON field
+	formContent := app.generateEditForm(jsonData, schema)
+
+	data := EditPageData{
+		Content:     string(prettyJSON),
+		FormContent: template.HTML(formContent),
+		ReadOnly:    app.readOnly,
+		Path:        path,
+		Message:     message,
+		Schema:      schema,
+	}
+	app.renderEditPage(w, data)
+}
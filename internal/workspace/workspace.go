@@ -0,0 +1,231 @@
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ErrOutsideRoot is returned when a path resolves to a location outside the workspace root
+var ErrOutsideRoot = errors.New("path is outside of workspace root")
+
+type (
+	// Workspace provides access to the files below a root directory on the local disk
+	Workspace struct {
+
+		// root is the absolute, symlink free path of the workspace directory
+		root string
+	}
+
+	// Entry represents a file or directory within the workspace
+	Entry struct {
+
+		// Name is the base name of the file or directory
+		Name string
+
+		// Path is the slash separated path relative to the workspace root
+		Path string
+
+		// IsDir is true for directories
+		IsDir bool
+
+		// Children contains the entries of a directory, sorted with directories first
+		Children []Entry
+	}
+)
+
+// New creates a workspace for the given root directory
+func New(root string) (*Workspace, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve workspace root: %w", err)
+	}
+	abs, err = filepath.EvalSymlinks(abs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve workspace root: %w", err)
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access workspace root: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("workspace root %q is not a directory", root)
+	}
+	return &Workspace{root: abs}, nil
+}
+
+// Root returns the absolute path of the workspace directory
+func (ws *Workspace) Root() string {
+	return ws.root
+}
+
+// Resolve converts a path relative to the workspace root into an absolute path on disk.
+// An error wrapping ErrOutsideRoot is returned if the path, after following symlinks, is not
+// located below the workspace root.
+func (ws *Workspace) Resolve(rel string) (string, error) {
+	p := filepath.Join(ws.root, filepath.FromSlash(strings.TrimLeft(rel, "/")))
+	if !ws.contains(p) {
+		return "", fmt.Errorf("%w: %s", ErrOutsideRoot, rel)
+	}
+
+	// Follow symlinks of the longest existing part of the path so links cannot escape the root
+	existing := p
+	rest := ""
+	for {
+		resolved, err := filepath.EvalSymlinks(existing)
+		if err == nil {
+			if !ws.contains(resolved) {
+				return "", fmt.Errorf("%w: %s", ErrOutsideRoot, rel)
+			}
+			return filepath.Join(resolved, rest), nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			return "", err
+		}
+		rest = filepath.Join(filepath.Base(existing), rest)
+		existing = parent
+	}
+}
+
+// contains reports whether p is the root or located below it
+func (ws *Workspace) contains(p string) bool {
+	rel, err := filepath.Rel(ws.root, p)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+// List returns the directory tree below the workspace root. Hidden files and directories are skipped.
+func (ws *Workspace) List() ([]Entry, error) {
+	return ws.list(ws.root, "")
+}
+
+// list reads the directory dir recursively, rel is the slash separated path of dir relative to the root
+func (ws *Workspace) list(dir, rel string) ([]Entry, error) {
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]Entry, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		if strings.HasPrefix(dirEntry.Name(), ".") {
+			continue
+		}
+		entry := Entry{
+			Name:  dirEntry.Name(),
+			Path:  strings.TrimPrefix(rel+"/"+dirEntry.Name(), "/"),
+			IsDir: dirEntry.IsDir(),
+		}
+		if entry.IsDir {
+			entry.Children, err = ws.list(filepath.Join(dir, dirEntry.Name()), entry.Path)
+			if err != nil {
+				return nil, err
+			}
+		} else if !dirEntry.Type().IsRegular() {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].IsDir != entries[j].IsDir {
+			return entries[i].IsDir
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	return entries, nil
+}
+
+// ReadFile reads the file at the path relative to the workspace root
+func (ws *Workspace) ReadFile(rel string) ([]byte, error) {
+	p, err := ws.Resolve(rel)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(p)
+}
+
+// WriteFile atomically replaces the file at the path relative to the workspace root with data.
+// The content is written to a temporary file in the same directory which is then renamed.
+// If backup is true and the file exists, its previous content is kept as <name>.bak.
+func (ws *Workspace) WriteFile(rel string, data []byte, backup bool) (err error) {
+	p, err := ws.Resolve(rel)
+	if err != nil {
+		return err
+	}
+
+	mode := os.FileMode(0o644)
+	info, err := os.Stat(p)
+	exists := err == nil
+	if exists {
+		if info.IsDir() {
+			return fmt.Errorf("%s is a directory", rel)
+		}
+		mode = info.Mode().Perm()
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(p), "."+filepath.Base(p)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err = tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to sync temporary file: %w", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary file: %w", err)
+	}
+	if err = os.Chmod(tmp.Name(), mode); err != nil {
+		return fmt.Errorf("failed to set file mode: %w", err)
+	}
+
+	if backup && exists {
+		if err = copyFile(p, p+".bak", mode); err != nil {
+			return fmt.Errorf("failed to create backup: %w", err)
+		}
+	}
+
+	if err = os.Rename(tmp.Name(), p); err != nil {
+		return fmt.Errorf("failed to replace file: %w", err)
+	}
+	return nil
+}
+
+// copyFile copies the content of src to dst, replacing dst if it exists
+func copyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
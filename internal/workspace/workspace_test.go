@@ -0,0 +1,168 @@
+package workspace
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setupWorkspace creates a temporary directory with a few files and returns the workspace for it
+func setupWorkspace(t *testing.T) (*Workspace, string) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "configs", "nested"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"a.json":                    `{"a": 1}`,
+		"configs/b.json":            `{"b": 2}`,
+		"configs/nested/c.json":     `{"c": 3}`,
+		".hidden/secret.json":       `{}`,
+		"configs/nested/readme.txt": "text",
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ws, err := New(dir)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return ws, dir
+}
+
+func TestNew(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := New(dir); err != nil {
+		t.Errorf("New() error = %v", err)
+	}
+	if _, err := New(filepath.Join(dir, "missing")); err == nil {
+		t.Errorf("New() expected error for missing directory")
+	}
+	file := filepath.Join(dir, "file.json")
+	if err := os.WriteFile(file, []byte("{}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New(file); err == nil {
+		t.Errorf("New() expected error for file as root")
+	}
+}
+
+func TestWorkspace_Resolve(t *testing.T) {
+	ws, dir := setupWorkspace(t)
+	outside := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(dir, "escape")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{name: "File in root", path: "a.json"},
+		{name: "Nested file", path: "configs/nested/c.json"},
+		{name: "Leading slash stays in root", path: "/configs/b.json"},
+		{name: "Not yet existing file", path: "configs/new.json"},
+		{name: "Parent traversal", path: "../a.json", wantErr: true},
+		{name: "Hidden traversal", path: "configs/../../a.json", wantErr: true},
+		{name: "Symlink escaping root", path: "escape/x.json", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ws.Resolve(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, ErrOutsideRoot) {
+					t.Errorf("Resolve() error = %v, want ErrOutsideRoot", err)
+				}
+				return
+			}
+			if !ws.contains(got) {
+				t.Errorf("Resolve() = %s, not below root %s", got, ws.Root())
+			}
+		})
+	}
+}
+
+func TestWorkspace_List(t *testing.T) {
+	ws, _ := setupWorkspace(t)
+	entries, err := ws.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("List() returned %d entries, want 2: %+v", len(entries), entries)
+	}
+	if !entries[0].IsDir || entries[0].Name != "configs" {
+		t.Errorf("List() first entry = %+v, want directory configs", entries[0])
+	}
+	if entries[1].Path != "a.json" {
+		t.Errorf("List() second entry = %+v, want a.json", entries[1])
+	}
+	nested := entries[0].Children[0]
+	if nested.Path != "configs/nested" || len(nested.Children) != 2 {
+		t.Errorf("List() nested entry = %+v", nested)
+	}
+	if nested.Children[0].Path != "configs/nested/c.json" {
+		t.Errorf("List() nested file = %+v", nested.Children[0])
+	}
+}
+
+func TestWorkspace_WriteFile(t *testing.T) {
+	ws, dir := setupWorkspace(t)
+
+	t.Run("Replace with backup", func(t *testing.T) {
+		if err := ws.WriteFile("configs/b.json", []byte(`{"b": 3}`), true); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+		content, err := ws.ReadFile("configs/b.json")
+		if err != nil || string(content) != `{"b": 3}` {
+			t.Errorf("ReadFile() = %s, %v", content, err)
+		}
+		backup, err := os.ReadFile(filepath.Join(dir, "configs", "b.json.bak"))
+		if err != nil || string(backup) != `{"b": 2}` {
+			t.Errorf("backup = %s, %v", backup, err)
+		}
+		info, err := os.Stat(filepath.Join(dir, "configs", "b.json"))
+		if err != nil || info.Mode().Perm() != 0o600 {
+			t.Errorf("file mode = %v, %v, want 0600", info.Mode().Perm(), err)
+		}
+	})
+
+	t.Run("Replace without backup", func(t *testing.T) {
+		if err := ws.WriteFile("a.json", []byte(`{"a": 2}`), false); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "a.json.bak")); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("unexpected backup file, stat error = %v", err)
+		}
+	})
+
+	t.Run("No temporary files left", func(t *testing.T) {
+		matches, err := filepath.Glob(filepath.Join(dir, "configs", ".*.tmp"))
+		if err != nil || len(matches) != 0 {
+			t.Errorf("temporary files left: %v, %v", matches, err)
+		}
+	})
+
+	t.Run("Outside root", func(t *testing.T) {
+		if err := ws.WriteFile("../evil.json", []byte(`{}`), false); !errors.Is(err, ErrOutsideRoot) {
+			t.Errorf("WriteFile() error = %v, want ErrOutsideRoot", err)
+		}
+	})
+
+	t.Run("Directory", func(t *testing.T) {
+		if err := ws.WriteFile("configs", []byte(`{}`), false); err == nil {
+			t.Errorf("WriteFile() expected error for directory")
+		}
+	})
+}
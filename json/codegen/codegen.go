@@ -0,0 +1,519 @@
+package codegen
+
+import (
+	stdJson "encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+
+	"github.com/sascha-andres/jsonedit/json"
+	"github.com/sascha-andres/jsonedit/json/fromschema"
+	"github.com/sascha-andres/jsonedit/json/infer"
+)
+
+// Language is a language types are generated for
+type Language string
+
+const (
+	// Go generates structs with json tags
+	Go Language = "go"
+
+	// TypeScript generates interfaces
+	TypeScript Language = "typescript"
+)
+
+// initialisms are written in upper case within Go names
+var initialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true,
+	"SQL": true, "TCP": true, "TTL": true, "UI": true, "URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+type (
+	// typeRef describes the type of a value
+	typeRef struct {
+
+		// kind is one of string, integer, number, boolean, any, array, map, union or named
+		kind string
+
+		// name is the name of named types
+		name string
+
+		// elem is the type of array elements and map values
+		elem *typeRef
+
+		// union holds the alternatives of oneOf, anyOf and multiple types
+		union []*typeRef
+
+		// nullable indicates whether null is allowed as well
+		nullable bool
+	}
+
+	// typeDef is a named type, either an object with fields or an enum
+	typeDef struct {
+
+		// name is the type name
+		name string
+
+		// description is the schema description written as comment
+		description string
+
+		// fields are the properties of objects sorted by name
+		fields []field
+
+		// base is string or integer for enums, empty for objects
+		base string
+
+		// alias is the type of root schemas that are no object, nil otherwise
+		alias *typeRef
+
+		// values are the values of enums
+		values []enumValue
+	}
+
+	// field is a property of an object
+	field struct {
+
+		// key is the property name within JSON documents
+		key string
+
+		// description is the schema description written as comment
+		description string
+
+		// ref is the type of the property
+		ref *typeRef
+
+		// required indicates whether the property is required
+		required bool
+	}
+
+	// enumValue is a value of an enum
+	enumValue struct {
+
+		// name is the name of the constant without type name
+		name string
+
+		// value is either a string or an int64
+		value any
+	}
+
+	// generator collects the named types of a schema
+	generator struct {
+
+		// rootName is the name of the type of the root schema
+		rootName string
+
+		// packageName is the package of generated Go code
+		packageName string
+
+		// logger is used when compiling schemas inferred from samples
+		logger *slog.Logger
+
+		// root is the root schema, its type is named by rootName
+		root *jsonschema.Schema
+
+		// defs holds the named types in the order they were found
+		defs []*typeDef
+
+		// named maps schemas to the name of their type, schemas are named before their fields
+		// are walked to stop at recursive references
+		named map[*jsonschema.Schema]string
+
+		// walking maps the schemas being walked to the name of their type, a schema reached again
+		// while it is walked references itself through arrays, maps or unions
+		walking map[*jsonschema.Schema]string
+
+		// recursive maps schemas referencing themselves through arrays, maps or unions to the name
+		// of the alias written for them
+		recursive map[*jsonschema.Schema]string
+
+		// names holds the type names in use
+		names map[string]bool
+	}
+
+	// Option configures the code generation
+	Option func(*generator)
+)
+
+// WithRootName sets the name of the type of the root schema, by default its title or Root
+func WithRootName(name string) Option {
+	return func(g *generator) {
+		g.rootName = name
+	}
+}
+
+// WithPackage sets the package of generated Go code, models by default
+func WithPackage(name string) Option {
+	return func(g *generator) {
+		g.packageName = name
+	}
+}
+
+// WithLogger sets the logger used when compiling schemas inferred from samples
+func WithLogger(logger *slog.Logger) Option {
+	return func(g *generator) {
+		g.logger = logger
+	}
+}
+
+// FromSchema generates the types described by the compiled schema. Objects with properties become
+// named types, named after $defs they are referenced from, their title or the property path.
+// Properties that are not required are optional, string and integer enums get constants.
+// Fields are sorted by name as the compiled schema does not keep the order of properties. Schemas
+// referencing themselves through arrays, maps or unions become named aliases.
+func FromSchema(schema *jsonschema.Schema, language Language, options ...Option) (string, error) {
+	g := newGenerator(options...)
+	g.root = schema
+	name := g.rootName
+	if name == "" {
+		name = typeName(schema.Title)
+	}
+	if name == "" {
+		name = "Root"
+	}
+	root := g.walk(schema, name)
+	if root.kind != "named" {
+		// Root schemas that are no object are written as alias
+		g.defs = append([]*typeDef{{name: g.unique(name), description: schema.Description, alias: root}}, g.defs...)
+	}
+
+	switch language {
+	case Go:
+		return g.golang()
+	case TypeScript:
+		return g.typescript(), nil
+	}
+	return "", fmt.Errorf("unsupported language %q", language)
+}
+
+// FromSamples infers a schema from sample documents and generates its types, see infer.Infer and FromSchema
+func FromSamples(samples []*json.Node, language Language, options ...Option) (string, error) {
+	schema, err := infer.Infer(samples, infer.WithEnumLimit(0))
+	if err != nil {
+		return "", err
+	}
+	content, err := schema.Format("", "  ")
+	if err != nil {
+		return "", err
+	}
+	parser, err := fromschema.NewSchemaParser(newGenerator(options...).logger, content)
+	if err != nil {
+		return "", err
+	}
+	return FromSchema(parser.Schema(), language, options...)
+}
+
+// ParseLanguage returns the language for its name, go, typescript or ts
+func ParseLanguage(name string) (Language, error) {
+	switch strings.ToLower(name) {
+	case "go", "golang":
+		return Go, nil
+	case "typescript", "ts":
+		return TypeScript, nil
+	}
+	return "", errors.New("language must be go or typescript")
+}
+
+// newGenerator returns a generator configured by the options
+func newGenerator(options ...Option) *generator {
+	g := &generator{
+		packageName: "models",
+		logger:      slog.Default(),
+		named:       make(map[*jsonschema.Schema]string),
+		walking:     make(map[*jsonschema.Schema]string),
+		recursive:   make(map[*jsonschema.Schema]string),
+		names:       make(map[string]bool),
+	}
+	for _, option := range options {
+		option(g)
+	}
+	return g
+}
+
+// walk returns the type of values of the schema, name is used if the schema needs a named type
+func (g *generator) walk(schema *jsonschema.Schema, name string) *typeRef {
+	if schema == nil {
+		return &typeRef{kind: "any"}
+	}
+	if existing, ok := g.named[schema]; ok {
+		return &typeRef{kind: "named", name: existing}
+	}
+	if schema.Ref != nil && len(types(schema)) == 0 && len(schema.Properties) == 0 {
+		return g.walk(schema.Ref, g.refName(schema.Ref, name))
+	}
+	if schema.Title != "" && schema != g.root {
+		name = typeName(schema.Title)
+	}
+
+	// A schema referencing itself without passing an object gets a named alias, its references
+	// use the name before the alias is written
+	if walking, ok := g.walking[schema]; ok {
+		if _, ok := g.recursive[schema]; !ok {
+			g.recursive[schema] = g.unique(walking)
+		}
+		return &typeRef{kind: "named", name: g.recursive[schema]}
+	}
+	g.walking[schema] = name
+	ref := g.walkType(schema, name)
+	delete(g.walking, schema)
+	if alias, ok := g.recursive[schema]; ok {
+		g.named[schema] = alias
+		g.defs = append(g.defs, &typeDef{name: alias, description: schema.Description, alias: ref})
+		return &typeRef{kind: "named", name: alias}
+	}
+	return ref
+}
+
+// walkType returns the type of values of the schema without following a plain $ref
+func (g *generator) walkType(schema *jsonschema.Schema, name string) *typeRef {
+	if schema.Enum != nil {
+		return g.enum(schema, schema.Enum.Values, name)
+	}
+	if schema.Const != nil {
+		return g.enum(schema, []any{*schema.Const}, name)
+	}
+
+	allowed := types(schema)
+	nullable := slices.Contains(allowed, "null")
+	allowed = slices.DeleteFunc(allowed, func(t string) bool { return t == "null" })
+	if len(allowed) == 0 {
+		switch {
+		case len(schema.Properties) > 0 || len(schema.AllOf) > 0:
+			allowed = []string{"object"}
+		case len(schema.OneOf) > 0 || len(schema.AnyOf) > 0:
+			ref := &typeRef{kind: "union", nullable: nullable}
+			for i, branch := range append(append([]*jsonschema.Schema(nil), schema.OneOf...), schema.AnyOf...) {
+				ref.union = append(ref.union, g.walk(branch, fmt.Sprintf("%sOption%d", name, i+1)))
+			}
+			return ref
+		default:
+			return &typeRef{kind: "any", nullable: nullable}
+		}
+	}
+	if len(allowed) > 1 {
+		ref := &typeRef{kind: "union", nullable: nullable}
+		for _, t := range allowed {
+			ref.union = append(ref.union, &typeRef{kind: primitive(t)})
+		}
+		return ref
+	}
+
+	var ref *typeRef
+	switch allowed[0] {
+	case "object":
+		ref = g.object(schema, name)
+	case "array":
+		ref = &typeRef{kind: "array", elem: g.walk(items(schema), name+"Item")}
+	default:
+		ref = &typeRef{kind: primitive(allowed[0])}
+	}
+	ref.nullable = ref.nullable || nullable
+	return ref
+}
+
+// object returns a named type for objects with properties, a map otherwise
+func (g *generator) object(schema *jsonschema.Schema, name string) *typeRef {
+	properties := make(map[string]*jsonschema.Schema)
+	required := make(map[string]bool)
+	collectProperties(schema, properties, required, make(map[*jsonschema.Schema]bool))
+	if len(properties) == 0 {
+		elem := &typeRef{kind: "any"}
+		if additional, ok := schema.AdditionalProperties.(*jsonschema.Schema); ok {
+			elem = g.walk(additional, name+"Value")
+		}
+		return &typeRef{kind: "map", elem: elem}
+	}
+
+	name = g.unique(name)
+	g.named[schema] = name
+	def := &typeDef{name: name, description: schema.Description}
+	g.defs = append(g.defs, def)
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		property := properties[key]
+		def.fields = append(def.fields, field{
+			key:         key,
+			description: description(property),
+			ref:         g.walk(property, name+typeName(key)),
+			required:    required[key],
+		})
+	}
+	return &typeRef{kind: "named", name: name}
+}
+
+// collectProperties adds the properties of the schema and the schemas of allOf and $ref
+func collectProperties(schema *jsonschema.Schema, properties map[string]*jsonschema.Schema, required map[string]bool, seen map[*jsonschema.Schema]bool) {
+	if schema == nil || seen[schema] {
+		return
+	}
+	seen[schema] = true
+	for key, property := range schema.Properties {
+		if _, exists := properties[key]; !exists {
+			properties[key] = property
+		}
+	}
+	for _, key := range schema.Required {
+		required[key] = true
+	}
+	collectProperties(schema.Ref, properties, required, seen)
+	for _, subSchema := range schema.AllOf {
+		collectProperties(subSchema, properties, required, seen)
+	}
+}
+
+// enum returns a named enum for string and integer values, the common type of other values
+func (g *generator) enum(schema *jsonschema.Schema, values []any, name string) *typeRef {
+	base, nullable := "", false
+	enumValues := make([]enumValue, 0, len(values))
+	for _, value := range values {
+		var current string
+		switch v := value.(type) {
+		case nil:
+			nullable = true
+			continue
+		case string:
+			current = "string"
+			enumValues = append(enumValues, enumValue{name: typeName(v), value: v})
+		case stdJson.Number:
+			number, ok := new(big.Rat).SetString(v.String())
+			if !ok || !number.IsInt() || !number.Num().IsInt64() {
+				return &typeRef{kind: "number", nullable: nullable}
+			}
+			current = "integer"
+			enumValues = append(enumValues, enumValue{name: integerName(number.Num().Int64()), value: number.Num().Int64()})
+		case bool:
+			return &typeRef{kind: "boolean"}
+		default:
+			return &typeRef{kind: "any"}
+		}
+		if base != "" && base != current {
+			return &typeRef{kind: "any"}
+		}
+		base = current
+	}
+	if base == "" {
+		return &typeRef{kind: "any", nullable: nullable}
+	}
+
+	name = g.unique(name)
+	g.named[schema] = name
+	g.defs = append(g.defs, &typeDef{name: name, description: schema.Description, base: base, values: uniqueValueNames(enumValues)})
+	return &typeRef{kind: "named", name: name, nullable: nullable}
+}
+
+// refName returns the name for the type of a referenced schema, the last segment of its location
+func (g *generator) refName(schema *jsonschema.Schema, fallback string) string {
+	location := schema.Location
+	if index := strings.LastIndexAny(location, "/#"); index >= 0 {
+		location = location[index+1:]
+	}
+	location = strings.TrimSuffix(location, ".json")
+	if name := typeName(location); name != "" {
+		return name
+	}
+	return fallback
+}
+
+// unique returns the name, followed by a number if it is already in use
+func (g *generator) unique(name string) string {
+	candidate := name
+	for i := 2; g.names[candidate]; i++ {
+		candidate = fmt.Sprintf("%s%d", name, i)
+	}
+	g.names[candidate] = true
+	return candidate
+}
+
+// types returns the types the schema allows, empty if it has no type keyword
+func types(schema *jsonschema.Schema) []string {
+	if schema.Types == nil {
+		return nil
+	}
+	return schema.Types.ToStrings()
+}
+
+// items returns the schema of array elements, nil if elements are not described by a single schema
+func items(schema *jsonschema.Schema) *jsonschema.Schema {
+	if schema.Items2020 != nil {
+		return schema.Items2020
+	}
+	if items, ok := schema.Items.(*jsonschema.Schema); ok {
+		return items
+	}
+	return nil
+}
+
+// primitive returns the kind of a JSON schema type that is neither object nor array
+func primitive(schemaType string) string {
+	switch schemaType {
+	case "string", "integer", "number", "boolean":
+		return schemaType
+	}
+	return "any"
+}
+
+// description returns the description of the schema or the schema it references
+func description(schema *jsonschema.Schema) string {
+	if schema.Description == "" && schema.Ref != nil {
+		return schema.Ref.Description
+	}
+	return schema.Description
+}
+
+// typeName returns an exported name for the text, splitting words at characters that are neither
+// letters nor digits and writing initialisms in upper case
+func typeName(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var builder strings.Builder
+	for _, word := range words {
+		if upper := strings.ToUpper(word); initialisms[upper] {
+			builder.WriteString(upper)
+			continue
+		}
+		runes := []rune(word)
+		builder.WriteRune(unicode.ToUpper(runes[0]))
+		builder.WriteString(string(runes[1:]))
+	}
+	name := builder.String()
+	if name != "" && unicode.IsDigit([]rune(name)[0]) {
+		name = "N" + name
+	}
+	return name
+}
+
+// integerName returns the name of an integer enum value
+func integerName(value int64) string {
+	if value < 0 {
+		return fmt.Sprintf("Minus%d", -value)
+	}
+	return fmt.Sprintf("N%d", value)
+}
+
+// uniqueValueNames makes the names of enum values unique, empty names are replaced by Value
+func uniqueValueNames(values []enumValue) []enumValue {
+	used := make(map[string]bool)
+	for i := range values {
+		name := values[i].name
+		if name == "" {
+			name = "Value"
+		}
+		candidate := name
+		for n := 2; used[candidate]; n++ {
+			candidate = fmt.Sprintf("%s%d", name, n)
+		}
+		used[candidate] = true
+		values[i].name = candidate
+	}
+	return values
+}
@@ -0,0 +1,195 @@
+package codegen
+
+import (
+	"go/parser"
+	"go/token"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/sascha-andres/jsonedit/json"
+	"github.com/sascha-andres/jsonedit/json/fromschema"
+)
+
+const orderSchema = `{
+	"title": "order",
+	"description": "An order of a customer",
+	"type": "object",
+	"properties": {
+		"id": { "type": "string", "description": "Unique id" },
+		"status": { "enum": ["new", "in-progress", "done"] },
+		"priority": { "type": "integer", "enum": [1, 2, 3] },
+		"total": { "type": ["number", "null"] },
+		"customer": { "$ref": "#/$defs/customer" },
+		"items": { "type": "array", "items": { "type": "object", "properties": { "sku": { "type": "string" }, "quantity": { "type": "integer" } }, "required": ["sku"] } },
+		"labels": { "type": "object", "additionalProperties": { "type": "string" } },
+		"content-type": { "type": "string" },
+		"parent": { "$ref": "#" }
+	},
+	"required": ["id", "status", "customer"],
+	"$defs": {
+		"customer": { "type": "object", "properties": { "name": { "type": "string" } }, "required": ["name"] }
+	}
+}`
+
+func compile(t *testing.T, schema string) *fromschema.SchemaParser {
+	t.Helper()
+	parser, err := fromschema.NewSchemaParser(slog.New(slog.NewTextHandler(os.Stdout, nil)), []byte(schema))
+	if err != nil {
+		t.Fatalf("Failed to create schema parser: %v", err)
+	}
+	return parser
+}
+
+// normalize replaces the alignment of gofmt by single spaces
+func normalize(code string) string {
+	return regexp.MustCompile(`[ \t]+`).ReplaceAllString(code, " ")
+}
+
+func TestFromSchemaGo(t *testing.T) {
+	code, err := FromSchema(compile(t, orderSchema).Schema(), Go, WithPackage("orders"))
+	if err != nil {
+		t.Fatalf("FromSchema() error = %v", err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "order.go", code, parser.AllErrors); err != nil {
+		t.Fatalf("FromSchema() generated invalid Go code: %v\n%s", err, code)
+	}
+	for _, expected := range []string{
+		"package orders",
+		"// An order of a customer\ntype Order struct {",
+		"// Unique id\n\tID string `json:\"id\"`",
+		"Status OrderStatus `json:\"status\"`",
+		"Priority *OrderPriority `json:\"priority,omitempty\"`",
+		"Total *float64 `json:\"total,omitempty\"`",
+		"Customer Customer `json:\"customer\"`",
+		"Items []OrderItemsItem `json:\"items,omitempty\"`",
+		"Labels map[string]string `json:\"labels,omitempty\"`",
+		"ContentType *string `json:\"content-type,omitempty\"`",
+		"Parent *Order `json:\"parent,omitempty\"`",
+		"type OrderStatus string",
+		"OrderStatusInProgress OrderStatus = \"in-progress\"",
+		"type OrderPriority int64",
+		"OrderPriorityN2 OrderPriority = 2",
+		"type Customer struct {\n\tName string `json:\"name\"`\n}",
+		"type OrderItemsItem struct {",
+	} {
+		if !strings.Contains(normalize(code), normalize(expected)) {
+			t.Errorf("FromSchema() does not contain %q:\n%s", expected, code)
+		}
+	}
+}
+
+func TestFromSchemaTypeScript(t *testing.T) {
+	code, err := FromSchema(compile(t, orderSchema).Schema(), TypeScript, WithRootName("PurchaseOrder"))
+	if err != nil {
+		t.Fatalf("FromSchema() error = %v", err)
+	}
+	for _, expected := range []string{
+		"/**\n * An order of a customer\n */\nexport interface PurchaseOrder {",
+		"  id: string;",
+		"  status: PurchaseOrderStatus;",
+		"  priority?: PurchaseOrderPriority;",
+		"  total?: number | null;",
+		"  customer: Customer;",
+		"  items?: PurchaseOrderItemsItem[];",
+		"  labels?: Record<string, string>;",
+		"  \"content-type\"?: string;",
+		"  parent?: PurchaseOrder;",
+		"export enum PurchaseOrderStatus {\n  New = \"new\",\n  InProgress = \"in-progress\",\n  Done = \"done\",\n}",
+		"export enum PurchaseOrderPriority {\n  N1 = 1,",
+	} {
+		if !strings.Contains(code, expected) {
+			t.Errorf("FromSchema() does not contain %q:\n%s", expected, code)
+		}
+	}
+}
+
+func TestFromSchemaAlias(t *testing.T) {
+	code, err := FromSchema(compile(t, `{"type": "array", "items": {"type": ["string", "integer"]}}`).Schema(), TypeScript)
+	if err != nil {
+		t.Fatalf("FromSchema() error = %v", err)
+	}
+	if !strings.Contains(code, "export type Root = (number | string)[];") {
+		t.Errorf("FromSchema() = %s, want alias of array", code)
+	}
+	if _, err := FromSchema(compile(t, `{}`).Schema(), Language("java")); err == nil {
+		t.Errorf("FromSchema() expected error for unsupported language")
+	}
+}
+
+func TestFromSamples(t *testing.T) {
+	samples := make([]*json.Node, 0)
+	for _, sample := range []string{`{"name": "a", "tags": ["x"], "address": {"city": "b"}}`, `{"name": "c", "age": 3}`} {
+		node, err := json.Parse([]byte(sample))
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		samples = append(samples, node)
+	}
+
+	code, err := FromSamples(samples, Go, WithRootName("Person"))
+	if err != nil {
+		t.Fatalf("FromSamples() error = %v", err)
+	}
+	for _, expected := range []string{
+		"type Person struct {",
+		"Address *PersonAddress `json:\"address,omitempty\"`",
+		"Age *int64 `json:\"age,omitempty\"`",
+		"Name string `json:\"name\"`",
+		"Tags []string `json:\"tags,omitempty\"`",
+		"type PersonAddress struct {\n\tCity string `json:\"city\"`\n}",
+	} {
+		if !strings.Contains(normalize(code), normalize(expected)) {
+			t.Errorf("FromSamples() does not contain %q:\n%s", expected, code)
+		}
+	}
+
+	if _, err := FromSamples(nil, Go); err == nil {
+		t.Errorf("FromSamples() expected error without samples")
+	}
+}
+
+func TestFromSchemaRecursiveAlias(t *testing.T) {
+	schema := `{
+		"$defs": {
+			"a": { "type": "array", "items": { "$ref": "#/$defs/a" } },
+			"tree": { "type": "object", "additionalProperties": { "$ref": "#/$defs/tree" } },
+			"value": { "anyOf": [{ "type": "string" }, { "type": "array", "items": { "$ref": "#/$defs/value" } }] }
+		},
+		"type": "object",
+		"properties": { "x": { "$ref": "#/$defs/a" }, "tree": { "$ref": "#/$defs/tree" }, "value": { "$ref": "#/$defs/value" } }
+	}`
+
+	code, err := FromSchema(compile(t, schema).Schema(), Go)
+	if err != nil {
+		t.Fatalf("FromSchema() error = %v", err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "root.go", code, parser.AllErrors); err != nil {
+		t.Fatalf("FromSchema() generated invalid Go code: %v\n%s", err, code)
+	}
+	for _, expected := range []string{"type A []A", "type Tree map[string]Tree", "type Value any", "X *A `json:\"x,omitempty\"`"} {
+		if !strings.Contains(normalize(code), normalize(expected)) {
+			t.Errorf("FromSchema() does not contain %q:\n%s", expected, code)
+		}
+	}
+
+	code, err = FromSchema(compile(t, schema).Schema(), TypeScript)
+	if err != nil {
+		t.Fatalf("FromSchema() error = %v", err)
+	}
+	for _, expected := range []string{"export type A = A[];", "export type Tree = Record<string, Tree>;", "export type Value = string | Value[];", "  x?: A;"} {
+		if !strings.Contains(code, expected) {
+			t.Errorf("FromSchema() does not contain %q:\n%s", expected, code)
+		}
+	}
+
+	code, err = FromSchema(compile(t, `{"type": "array", "items": {"$ref": "#"}}`).Schema(), TypeScript)
+	if err != nil {
+		t.Fatalf("FromSchema() error = %v", err)
+	}
+	if !strings.Contains(code, "export type Root = Root[];") {
+		t.Errorf("FromSchema() = %s, want recursive alias of the root", code)
+	}
+}
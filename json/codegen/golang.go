@@ -0,0 +1,102 @@
+package codegen
+
+import (
+	"fmt"
+	"go/format"
+	"strings"
+)
+
+// golang writes the named types as Go code. Optional properties are pointers except for slices,
+// maps and any, and are omitted when empty.
+func (g *generator) golang() (string, error) {
+	var builder strings.Builder
+	builder.WriteString("// Code generated by jsonedit. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&builder, "package %s\n", g.packageName)
+
+	for _, def := range g.defs {
+		builder.WriteString("\n")
+		writeGoComment(&builder, def.description, "")
+		switch {
+		case def.alias != nil:
+			fmt.Fprintf(&builder, "type %s %s\n", def.name, goType(def.alias, false))
+		case def.base != "":
+			base := "string"
+			if def.base == "integer" {
+				base = "int64"
+			}
+			fmt.Fprintf(&builder, "type %s %s\n\n", def.name, base)
+			fmt.Fprintf(&builder, "// Values of %s\nconst (\n", def.name)
+			for _, value := range def.values {
+				fmt.Fprintf(&builder, "\t%s%s %s = %#v\n", def.name, value.name, def.name, value.value)
+			}
+			builder.WriteString(")\n")
+		default:
+			fmt.Fprintf(&builder, "type %s struct {\n", def.name)
+			used := make(map[string]bool)
+			for _, field := range def.fields {
+				writeGoComment(&builder, field.description, "\t")
+				tag := field.key
+				if !field.required {
+					tag += ",omitempty"
+				}
+				fmt.Fprintf(&builder, "\t%s %s `json:%q`\n", fieldName(field.key, used), goType(field.ref, !field.required), tag)
+			}
+			builder.WriteString("}\n")
+		}
+	}
+
+	source, err := format.Source([]byte(builder.String()))
+	if err != nil {
+		return "", fmt.Errorf("failed to format Go code: %w", err)
+	}
+	return string(source), nil
+}
+
+// goType returns the Go type, optional and nullable values are pointers unless nil is a valid value
+func goType(ref *typeRef, optional bool) string {
+	pointer := ""
+	if optional || ref.nullable {
+		pointer = "*"
+	}
+	switch ref.kind {
+	case "named":
+		return pointer + ref.name
+	case "string":
+		return pointer + "string"
+	case "integer":
+		return pointer + "int64"
+	case "number":
+		return pointer + "float64"
+	case "boolean":
+		return pointer + "bool"
+	case "array":
+		return "[]" + goType(ref.elem, false)
+	case "map":
+		return "map[string]" + goType(ref.elem, false)
+	}
+	return "any"
+}
+
+// fieldName returns a unique exported field name for the property
+func fieldName(key string, used map[string]bool) string {
+	name := typeName(key)
+	if name == "" {
+		name = "Field"
+	}
+	candidate := name
+	for i := 2; used[candidate]; i++ {
+		candidate = fmt.Sprintf("%s%d", name, i)
+	}
+	used[candidate] = true
+	return candidate
+}
+
+// writeGoComment writes the description as line comments
+func writeGoComment(builder *strings.Builder, description, indent string) {
+	if description == "" {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSpace(description), "\n") {
+		fmt.Fprintf(builder, "%s// %s\n", indent, strings.TrimSpace(line))
+	}
+}
@@ -0,0 +1,97 @@
+package codegen
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// identifierPattern matches property names that need no quotes in TypeScript
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// typescript writes the named types as exported TypeScript interfaces, enums and type aliases
+func (g *generator) typescript() string {
+	var builder strings.Builder
+	builder.WriteString("// Code generated by jsonedit. DO NOT EDIT.\n")
+
+	for _, def := range g.defs {
+		builder.WriteString("\n")
+		writeTSComment(&builder, def.description, "")
+		switch {
+		case def.alias != nil:
+			fmt.Fprintf(&builder, "export type %s = %s;\n", def.name, tsType(def.alias))
+		case def.base != "":
+			fmt.Fprintf(&builder, "export enum %s {\n", def.name)
+			for _, value := range def.values {
+				literal := fmt.Sprintf("%d", value.value)
+				if text, ok := value.value.(string); ok {
+					literal = strconv.Quote(text)
+				}
+				fmt.Fprintf(&builder, "  %s = %s,\n", value.name, literal)
+			}
+			builder.WriteString("}\n")
+		default:
+			fmt.Fprintf(&builder, "export interface %s {\n", def.name)
+			for _, field := range def.fields {
+				writeTSComment(&builder, field.description, "  ")
+				key := field.key
+				if !identifierPattern.MatchString(key) {
+					key = strconv.Quote(key)
+				}
+				optional := ""
+				if !field.required {
+					optional = "?"
+				}
+				fmt.Fprintf(&builder, "  %s%s: %s;\n", key, optional, tsType(field.ref))
+			}
+			builder.WriteString("}\n")
+		}
+	}
+	return builder.String()
+}
+
+// tsType returns the TypeScript type
+func tsType(ref *typeRef) string {
+	var result string
+	switch ref.kind {
+	case "named":
+		result = ref.name
+	case "string", "boolean":
+		result = ref.kind
+	case "integer", "number":
+		result = "number"
+	case "array":
+		elem := tsType(ref.elem)
+		if strings.Contains(elem, " ") {
+			elem = "(" + elem + ")"
+		}
+		result = elem + "[]"
+	case "map":
+		result = "Record<string, " + tsType(ref.elem) + ">"
+	case "union":
+		alternatives := make([]string, 0, len(ref.union))
+		for _, alternative := range ref.union {
+			alternatives = append(alternatives, tsType(alternative))
+		}
+		result = strings.Join(alternatives, " | ")
+	default:
+		result = "unknown"
+	}
+	if ref.nullable {
+		result += " | null"
+	}
+	return result
+}
+
+// writeTSComment writes the description as doc comment
+func writeTSComment(builder *strings.Builder, description, indent string) {
+	if description == "" {
+		return
+	}
+	fmt.Fprintf(builder, "%s/**\n", indent)
+	for _, line := range strings.Split(strings.TrimSpace(description), "\n") {
+		fmt.Fprintf(builder, "%s * %s\n", indent, strings.TrimSpace(line))
+	}
+	fmt.Fprintf(builder, "%s */\n", indent)
+}
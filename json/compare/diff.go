@@ -0,0 +1,320 @@
+package compare
+
+import (
+	stdJson "encoding/json"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/sascha-andres/jsonedit/json"
+)
+
+// ChangeType describes how a value differs between two documents
+type ChangeType string
+
+const (
+	// Added marks a value only present in the second document
+	Added ChangeType = "added"
+
+	// Removed marks a value only present in the first document
+	Removed ChangeType = "removed"
+
+	// Modified marks a scalar value that differs between both documents
+	Modified ChangeType = "modified"
+
+	// TypeChanged marks a value whose JSON type differs between both documents
+	TypeChanged ChangeType = "type-changed"
+)
+
+type (
+	// Change is a single difference between two documents
+	Change struct {
+
+		// Type describes the kind of change
+		Type ChangeType `json:"type"`
+
+		// Path is the JSON Pointer of the changed value, in the first document for removed values
+		// and in the second document otherwise
+		Path string `json:"path"`
+
+		// From is the JSON Pointer of the value in the first document if it differs from Path,
+		// which happens when array elements are not matched by their position
+		From string `json:"from,omitempty"`
+
+		// Old is the value in the first document, nil for added values
+		Old *json.Node `json:"old,omitempty"`
+
+		// New is the value in the second document, nil for removed values
+		New *json.Node `json:"new,omitempty"`
+	}
+
+	// Result holds all changes found by Compare in document order
+	Result struct {
+
+		// Changes lists the differences between both documents
+		Changes []Change `json:"changes"`
+
+		// Suppressed is the number of differences not reported because of ignore patterns or the numeric tolerance
+		Suppressed int `json:"suppressed"`
+	}
+
+	// comparer holds the configuration and the result of a comparison
+	comparer struct {
+
+		// arrayKey is the member used to match object elements of arrays
+		arrayKey string
+
+		// unorderedArrays indicates whether array elements are matched regardless of their position
+		unorderedArrays bool
+
+		// ignore holds the tokens of the ignore patterns, * matches any single token
+		ignore [][]string
+
+		// absoluteTolerance is the maximum absolute difference of numbers considered equal
+		absoluteTolerance float64
+
+		// relativeTolerance is the maximum difference of numbers relative to the larger value considered equal
+		relativeTolerance float64
+
+		// result collects the changes
+		result *Result
+	}
+
+	// Option configures a comparison
+	Option func(*comparer)
+)
+
+// WithArrayKey matches object elements of arrays by the value of the member key instead of
+// their position. Elements without the key are matched with equal elements.
+func WithArrayKey(key string) Option {
+	return func(c *comparer) {
+		c.arrayKey = key
+	}
+}
+
+// WithUnorderedArrays treats arrays as unordered sets, elements are matched with equal elements
+// regardless of their position. A changed element is reported as removed and added.
+func WithUnorderedArrays(unordered bool) Option {
+	return func(c *comparer) {
+		c.unorderedArrays = unordered
+	}
+}
+
+// WithIgnore suppresses changes at paths matching one of the patterns and below them.
+// Patterns are JSON Pointers where a * token matches any single token, e.g. /items/*/updatedAt.
+func WithIgnore(patterns ...string) Option {
+	return func(c *comparer) {
+		for _, pattern := range patterns {
+			pattern = strings.TrimSpace(pattern)
+			if pattern == "" {
+				continue
+			}
+			if !strings.HasPrefix(pattern, "/") {
+				pattern = "/" + pattern
+			}
+			tokens, _ := json.ParsePointer(pattern)
+			c.ignore = append(c.ignore, tokens)
+		}
+	}
+}
+
+// WithTolerance treats numbers as equal if they differ by at most absolute or by at most
+// relative times the larger absolute value. A tolerance of zero is not applied.
+func WithTolerance(absolute, relative float64) Option {
+	return func(c *comparer) {
+		c.absoluteTolerance = absolute
+		c.relativeTolerance = relative
+	}
+}
+
+// Equal reports whether no changes were found
+func (r *Result) Equal() bool {
+	return len(r.Changes) == 0
+}
+
+// Count returns the number of changes of the given type
+func (r *Result) Count(changeType ChangeType) int {
+	count := 0
+	for _, change := range r.Changes {
+		if change.Type == changeType {
+			count++
+		}
+	}
+	return count
+}
+
+// Compare computes the structural difference between the documents a and b.
+// Object members are matched by key, so a different member order is not reported.
+// Array elements are matched by their position unless configured otherwise by options.
+func Compare(a, b *json.Node, options ...Option) *Result {
+	c := &comparer{result: &Result{Changes: []Change{}}}
+	for _, option := range options {
+		option(c)
+	}
+	c.diff("", "", a, b)
+	return c.result
+}
+
+// diff appends the changes between a and b to the result. path is the location of b in the
+// second document, from the location of a in the first document.
+func (c *comparer) diff(path, from string, a, b *json.Node) {
+	if c.ignored(path) {
+		if !a.Equal(b) {
+			c.result.Suppressed++
+		}
+		return
+	}
+	switch {
+	case a.Kind != b.Kind:
+		c.add(Change{Type: TypeChanged, Path: path, From: differentFrom(path, from), Old: a, New: b})
+	case a.Kind == json.ObjectKind:
+		for _, member := range a.Members {
+			other := b.Get(member.Key)
+			if other == nil {
+				c.add(Change{Type: Removed, Path: json.AppendPointer(from, member.Key), Old: member.Value})
+				continue
+			}
+			c.diff(json.AppendPointer(path, member.Key), json.AppendPointer(from, member.Key), member.Value, other)
+		}
+		for _, member := range b.Members {
+			if a.Get(member.Key) == nil {
+				c.add(Change{Type: Added, Path: json.AppendPointer(path, member.Key), New: member.Value})
+			}
+		}
+	case a.Kind == json.ArrayKind:
+		if c.arrayKey != "" || c.unorderedArrays {
+			c.diffMatchedArray(path, from, a, b)
+			return
+		}
+		for i := 0; i < len(a.Items) || i < len(b.Items); i++ {
+			index := strconv.Itoa(i)
+			switch {
+			case i >= len(b.Items):
+				c.add(Change{Type: Removed, Path: json.AppendPointer(from, index), Old: a.Items[i]})
+			case i >= len(a.Items):
+				c.add(Change{Type: Added, Path: json.AppendPointer(path, index), New: b.Items[i]})
+			default:
+				c.diff(json.AppendPointer(path, index), json.AppendPointer(from, index), a.Items[i], b.Items[i])
+			}
+		}
+	case a.Equal(b):
+		// Equal scalars are not reported
+	case a.Kind == json.NumberKind && c.withinTolerance(a.Number, b.Number):
+		c.result.Suppressed++
+	default:
+		c.add(Change{Type: Modified, Path: path, From: differentFrom(path, from), Old: a, New: b})
+	}
+}
+
+// differentFrom returns from if it differs from path, an empty string otherwise
+func differentFrom(path, from string) string {
+	if path == from {
+		return ""
+	}
+	return from
+}
+
+// diffMatchedArray compares arrays whose elements are matched by the array key or by
+// equality instead of their position
+func (c *comparer) diffMatchedArray(path, from string, a, b *json.Node) {
+	// matches maps the index of an element in a to the index of its counterpart in b
+	matches := make(map[int]int)
+	used := make(map[int]bool)
+	if c.arrayKey != "" {
+		for i, item := range a.Items {
+			key := item.Get(c.arrayKey)
+			if key == nil {
+				continue
+			}
+			for j, other := range b.Items {
+				if !used[j] && key.Equal(other.Get(c.arrayKey)) {
+					matches[i] = j
+					used[j] = true
+					break
+				}
+			}
+		}
+	}
+	for i, item := range a.Items {
+		if _, ok := matches[i]; ok {
+			continue
+		}
+		for j, other := range b.Items {
+			if !used[j] && item.Equal(other) {
+				matches[i] = j
+				used[j] = true
+				break
+			}
+		}
+	}
+
+	for i, item := range a.Items {
+		j, ok := matches[i]
+		if !ok {
+			c.add(Change{Type: Removed, Path: json.AppendPointer(from, strconv.Itoa(i)), Old: item})
+			continue
+		}
+		c.diff(json.AppendPointer(path, strconv.Itoa(j)), json.AppendPointer(from, strconv.Itoa(i)), item, b.Items[j])
+	}
+	for j, item := range b.Items {
+		if !used[j] {
+			c.add(Change{Type: Added, Path: json.AppendPointer(path, strconv.Itoa(j)), New: item})
+		}
+	}
+}
+
+// add records a change unless its path is ignored
+func (c *comparer) add(change Change) {
+	if c.ignored(change.Path) {
+		c.result.Suppressed++
+		return
+	}
+	c.result.Changes = append(c.result.Changes, change)
+}
+
+// ignored reports whether path or one of its parents matches an ignore pattern
+func (c *comparer) ignored(path string) bool {
+	if len(c.ignore) == 0 {
+		return false
+	}
+	tokens, err := json.ParsePointer(path)
+	if err != nil {
+		return false
+	}
+	for _, pattern := range c.ignore {
+		if len(pattern) > len(tokens) {
+			continue
+		}
+		matches := true
+		for i, token := range pattern {
+			if token != "*" && token != tokens[i] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+// withinTolerance reports whether the numbers a and b differ by no more than the configured tolerance
+func (c *comparer) withinTolerance(a, b stdJson.Number) bool {
+	if c.absoluteTolerance <= 0 && c.relativeTolerance <= 0 {
+		return false
+	}
+	x, err := a.Float64()
+	if err != nil {
+		return false
+	}
+	y, err := b.Float64()
+	if err != nil {
+		return false
+	}
+	difference := math.Abs(x - y)
+	if c.absoluteTolerance > 0 && difference <= c.absoluteTolerance {
+		return true
+	}
+	return c.relativeTolerance > 0 && difference <= c.relativeTolerance*math.Max(math.Abs(x), math.Abs(y))
+}
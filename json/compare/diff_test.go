@@ -0,0 +1,275 @@
+package compare
+
+import (
+	"testing"
+
+	"github.com/sascha-andres/jsonedit/json"
+)
+
+// mustParse parses doc or fails the test
+func mustParse(t *testing.T, doc string) *json.Node {
+	t.Helper()
+	n, err := json.Parse([]byte(doc))
+	if err != nil {
+		t.Fatalf("Parse(%s) error = %v", doc, err)
+	}
+	return n
+}
+
+// changeSummary is the comparable part of a change
+type changeSummary struct {
+	Type ChangeType
+	Path string
+	From string
+	Old  string
+	New  string
+}
+
+// summarize converts changes into comparable summaries with compact values
+func summarize(changes []Change) []changeSummary {
+	result := make([]changeSummary, len(changes))
+	for i, change := range changes {
+		result[i] = changeSummary{Type: change.Type, Path: change.Path, From: change.From}
+		if change.Old != nil {
+			old, _ := change.Old.Format("", "")
+			result[i].Old = string(old)
+		}
+		if change.New != nil {
+			n, _ := change.New.Format("", "")
+			result[i].New = string(n)
+		}
+	}
+	return result
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name   string
+		first  string
+		second string
+		want   []changeSummary
+	}{
+		{
+			name:   "Identical documents with different member order",
+			first:  `{"a": 1, "b": {"c": true, "d": null}}`,
+			second: `{"b": {"d": null, "c": true}, "a": 1.0}`,
+			want:   []changeSummary{},
+		},
+		{
+			name:   "Modified scalar",
+			first:  `{"name": "John"}`,
+			second: `{"name": "Jane"}`,
+			want:   []changeSummary{{Type: Modified, Path: "/name", Old: `"John"`, New: `"Jane"`}},
+		},
+		{
+			name:   "Added and removed members",
+			first:  `{"a": 1, "b": 2}`,
+			second: `{"b": 2, "c": {"x": 1}}`,
+			want: []changeSummary{
+				{Type: Removed, Path: "/a", Old: `1`},
+				{Type: Added, Path: "/c", New: `{"x":1}`},
+			},
+		},
+		{
+			name:   "Type changed",
+			first:  `{"a": "1", "b": [1]}`,
+			second: `{"a": 1, "b": {"0": 1}}`,
+			want: []changeSummary{
+				{Type: TypeChanged, Path: "/a", Old: `"1"`, New: `1`},
+				{Type: TypeChanged, Path: "/b", Old: `[1]`, New: `{"0":1}`},
+			},
+		},
+		{
+			name:   "Array items",
+			first:  `{"list": [1, 2, 3]}`,
+			second: `{"list": [1, 5]}`,
+			want: []changeSummary{
+				{Type: Modified, Path: "/list/1", Old: `2`, New: `5`},
+				{Type: Removed, Path: "/list/2", Old: `3`},
+			},
+		},
+		{
+			name:   "Appended array item",
+			first:  `[{"id": 1}]`,
+			second: `[{"id": 1}, {"id": 2}]`,
+			want:   []changeSummary{{Type: Added, Path: "/1", New: `{"id":2}`}},
+		},
+		{
+			name:   "Keys are escaped",
+			first:  `{"a/b": {"c~d": 1}}`,
+			second: `{"a/b": {"c~d": 2}}`,
+			want:   []changeSummary{{Type: Modified, Path: "/a~1b/c~0d", Old: `1`, New: `2`}},
+		},
+		{
+			name:   "Root scalar",
+			first:  `1`,
+			second: `2`,
+			want:   []changeSummary{{Type: Modified, Path: "", Old: `1`, New: `2`}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Compare(mustParse(t, tt.first), mustParse(t, tt.second))
+			got := summarize(result.Changes)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Compare() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Compare() change %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+			if result.Equal() != (len(tt.want) == 0) {
+				t.Errorf("Equal() = %v", result.Equal())
+			}
+		})
+	}
+}
+
+func TestResultCount(t *testing.T) {
+	result := Compare(mustParse(t, `{"a": 1, "b": 2}`), mustParse(t, `{"a": 2, "c": 3}`))
+	if result.Count(Modified) != 1 || result.Count(Added) != 1 || result.Count(Removed) != 1 || result.Count(TypeChanged) != 0 {
+		t.Errorf("Count() returned wrong numbers for %v", summarize(result.Changes))
+	}
+}
+
+func TestCompareArrayMatching(t *testing.T) {
+	first := `{"users": [{"id": 1, "name": "a"}, {"id": 2, "name": "b"}, {"id": 3, "name": "c"}]}`
+	second := `{"users": [{"id": 0, "name": "new"}, {"id": 1, "name": "a"}, {"id": 3, "name": "x"}]}`
+	tests := []struct {
+		name    string
+		first   string
+		second  string
+		options []Option
+		want    []changeSummary
+	}{
+		{
+			name:    "Matched by key",
+			first:   first,
+			second:  second,
+			options: []Option{WithArrayKey("id")},
+			want: []changeSummary{
+				{Type: Removed, Path: "/users/1", Old: `{"id":2,"name":"b"}`},
+				{Type: Modified, Path: "/users/2/name", Old: `"c"`, New: `"x"`},
+				{Type: Added, Path: "/users/0", New: `{"id":0,"name":"new"}`},
+			},
+		},
+		{
+			name:    "Unordered",
+			first:   `{"tags": ["a", "b", "c"]}`,
+			second:  `{"tags": ["c", "a", "d"]}`,
+			options: []Option{WithUnorderedArrays(true)},
+			want: []changeSummary{
+				{Type: Removed, Path: "/tags/1", Old: `"b"`},
+				{Type: Added, Path: "/tags/2", New: `"d"`},
+			},
+		},
+		{
+			name:    "Elements without key are matched by equality",
+			first:   `[{"id": 1, "v": 1}, {"other": true}]`,
+			second:  `[{"other": true}, {"id": 1, "v": 2}]`,
+			options: []Option{WithArrayKey("id")},
+			want:    []changeSummary{{Type: Modified, Path: "/1/v", From: "/0/v", Old: `1`, New: `2`}},
+		},
+		{
+			name:   "Positional without options",
+			first:  `["a", "b"]`,
+			second: `["b", "a"]`,
+			want: []changeSummary{
+				{Type: Modified, Path: "/0", Old: `"a"`, New: `"b"`},
+				{Type: Modified, Path: "/1", Old: `"b"`, New: `"a"`},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := summarize(Compare(mustParse(t, tt.first), mustParse(t, tt.second), tt.options...).Changes)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Compare() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Compare() change %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCompareSuppression(t *testing.T) {
+	tests := []struct {
+		name       string
+		first      string
+		second     string
+		options    []Option
+		want       []changeSummary
+		suppressed int
+	}{
+		{
+			name:    "Ignore with wildcard",
+			first:   `{"items": [{"id": 1, "updatedAt": "a"}, {"id": 2, "updatedAt": "b"}], "requestId": "x"}`,
+			second:  `{"items": [{"id": 1, "updatedAt": "c"}, {"id": 3, "updatedAt": "d"}], "requestId": "y"}`,
+			options: []Option{WithIgnore("/items/*/updatedAt", "/requestId")},
+			want: []changeSummary{
+				{Type: Modified, Path: "/items/1/id", Old: `2`, New: `3`},
+			},
+			suppressed: 3,
+		},
+		{
+			name:       "Ignore subtree",
+			first:      `{"meta": {"a": 1, "b": 2}, "c": 1}`,
+			second:     `{"meta": {"a": 2}, "c": 1}`,
+			options:    []Option{WithIgnore("meta")},
+			want:       []changeSummary{},
+			suppressed: 1,
+		},
+		{
+			name:       "Ignore added member",
+			first:      `{"a": 1}`,
+			second:     `{"a": 1, "b": {"c": 1}}`,
+			options:    []Option{WithIgnore("/b/c", "/b")},
+			want:       []changeSummary{},
+			suppressed: 1,
+		},
+		{
+			name:       "Absolute tolerance",
+			first:      `{"a": 1.0001, "b": 1.1}`,
+			second:     `{"a": 1.0002, "b": 1.2}`,
+			options:    []Option{WithTolerance(0.001, 0)},
+			want:       []changeSummary{{Type: Modified, Path: "/b", Old: `1.1`, New: `1.2`}},
+			suppressed: 1,
+		},
+		{
+			name:       "Relative tolerance",
+			first:      `[1000, 10]`,
+			second:     `[1001, 11]`,
+			options:    []Option{WithTolerance(0, 0.01)},
+			want:       []changeSummary{{Type: Modified, Path: "/1", Old: `10`, New: `11`}},
+			suppressed: 1,
+		},
+		{
+			name:       "No tolerance",
+			first:      `[1.0000001]`,
+			second:     `[1.0000002]`,
+			want:       []changeSummary{{Type: Modified, Path: "/0", Old: `1.0000001`, New: `1.0000002`}},
+			suppressed: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Compare(mustParse(t, tt.first), mustParse(t, tt.second), tt.options...)
+			got := summarize(result.Changes)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Compare() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Compare() change %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+			if result.Suppressed != tt.suppressed {
+				t.Errorf("Suppressed = %d, want %d", result.Suppressed, tt.suppressed)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package compare
+
+import (
+	"fmt"
+
+	"github.com/sascha-andres/jsonedit/json"
+)
+
+// Operation is a single operation of an RFC 6902 JSON Patch document
+type Operation struct {
+
+	// Op is the operation to perform: add, remove or replace
+	Op string `json:"op"`
+
+	// Path is the JSON Pointer of the target location
+	Path string `json:"path"`
+
+	// Value is the value to add or replace with, nil for remove operations
+	Value *json.Node `json:"value,omitempty"`
+}
+
+// JSONPatch returns an RFC 6902 JSON Patch that transforms a into b.
+// Array elements are matched by position.
+func JSONPatch(a, b *json.Node) []Operation {
+	result := Compare(a, b)
+
+	operations := make([]Operation, 0, len(result.Changes))
+	removals := make([]Operation, 0)
+	for _, change := range result.Changes {
+		switch change.Type {
+		case Added:
+			operations = append(operations, Operation{Op: "add", Path: change.Path, Value: change.New})
+		case Removed:
+			removals = append(removals, Operation{Op: "remove", Path: change.Path})
+		default:
+			operations = append(operations, Operation{Op: "replace", Path: change.Path, Value: change.New})
+		}
+	}
+
+	// Removed array elements are reported in ascending order, removing them from
+	// the end keeps the indices of the remaining elements valid
+	for i := len(removals) - 1; i >= 0; i-- {
+		operations = append(operations, removals[i])
+	}
+	return operations
+}
+
+// MergePatch returns an RFC 7386 Merge Patch that transforms a into b.
+// A merge patch uses null to remove members, so an error is returned if b sets a
+// member to null where a does not already have that null value.
+func MergePatch(a, b *json.Node) (*json.Node, error) {
+	return mergePatch("", a, b)
+}
+
+// mergePatch creates the merge patch for the value at path
+func mergePatch(path string, a, b *json.Node) (*json.Node, error) {
+	if b.Kind != json.ObjectKind {
+		return b.Clone(), nil
+	}
+	if a.Kind != json.ObjectKind {
+		// The target is replaced by an empty object before the patch is merged in
+		if err := checkNoNullMembers(path, b); err != nil {
+			return nil, err
+		}
+		return b.Clone(), nil
+	}
+
+	patch := json.NewObject()
+	for _, member := range a.Members {
+		if b.Get(member.Key) == nil {
+			patch.Set(member.Key, json.NewNull())
+		}
+	}
+	for _, member := range b.Members {
+		memberPath := json.AppendPointer(path, member.Key)
+		old := a.Get(member.Key)
+		if old != nil && old.Equal(member.Value) {
+			continue
+		}
+		if member.Value.Kind == json.NullKind {
+			return nil, fmt.Errorf("cannot express null value at %q in a merge patch", memberPath)
+		}
+		if old == nil {
+			old = json.NewNull()
+		}
+		value, err := mergePatch(memberPath, old, member.Value)
+		if err != nil {
+			return nil, err
+		}
+		patch.Set(member.Key, value)
+	}
+	return patch, nil
+}
+
+// checkNoNullMembers returns an error if the object n or one of its nested objects has a null member
+func checkNoNullMembers(path string, n *json.Node) error {
+	for _, member := range n.Members {
+		memberPath := json.AppendPointer(path, member.Key)
+		switch member.Value.Kind {
+		case json.NullKind:
+			return fmt.Errorf("cannot express null value at %q in a merge patch", memberPath)
+		case json.ObjectKind:
+			if err := checkNoNullMembers(memberPath, member.Value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
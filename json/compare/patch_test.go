@@ -0,0 +1,104 @@
+package compare
+
+import (
+	stdJson "encoding/json"
+	"testing"
+
+	"github.com/sascha-andres/jsonedit/json"
+	"github.com/sascha-andres/jsonedit/json/patch"
+)
+
+// roundTripDocuments are pairs of documents used to verify generated patches
+var roundTripDocuments = []struct {
+	name   string
+	first  string
+	second string
+}{
+	{name: "Identical", first: `{"a": 1}`, second: `{"a": 1}`},
+	{name: "Modified member", first: `{"a": 1, "b": "x"}`, second: `{"a": 2, "b": "x"}`},
+	{name: "Added and removed members", first: `{"a": 1, "b": 2}`, second: `{"b": 2, "c": {"d": [1, 2]}}`},
+	{name: "Nested objects", first: `{"a": {"b": {"c": 1, "d": 2}}}`, second: `{"a": {"b": {"c": 3}, "e": true}}`},
+	{name: "Array shrinks", first: `{"list": [1, 2, 3, 4]}`, second: `{"list": [1, 5]}`},
+	{name: "Array grows", first: `{"list": [1]}`, second: `{"list": [0, 2, 3]}`},
+	{name: "Type changed", first: `{"a": [1], "b": "x", "c": 1}`, second: `{"a": {"x": 1}, "b": ["x"], "c": {"d": {"e": 1}}}`},
+	{name: "Null values kept", first: `{"a": null, "b": 1}`, second: `{"a": null, "b": 2}`},
+	{name: "Removed null member", first: `{"a": null, "b": 1}`, second: `{"b": 1}`},
+	{name: "Escaped keys", first: `{"a/b": 1, "c~d": {"e": 1}}`, second: `{"a/b": 2, "c~d": {}}`},
+	{name: "Root array", first: `[1, {"a": 1}, 3]`, second: `[1, {"a": 2}]`},
+	{name: "Root type changed", first: `[1]`, second: `{"a": 1}`},
+	{name: "Root scalar", first: `"x"`, second: `"y"`},
+}
+
+func TestJSONPatchRoundTrip(t *testing.T) {
+	for _, tt := range roundTripDocuments {
+		t.Run(tt.name, func(t *testing.T) {
+			first := mustParse(t, tt.first)
+			second := mustParse(t, tt.second)
+			content, err := stdJson.Marshal(JSONPatch(first, second))
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			operations, err := json.Parse(content)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			patched, err := patch.ApplyJSONPatch(first, operations)
+			if err != nil {
+				t.Fatalf("applying %s failed: %v", content, err)
+			}
+			if !patched.Equal(second) {
+				got, _ := patched.Format("", "")
+				t.Errorf("patched document = %s, want %s", got, tt.second)
+			}
+		})
+	}
+}
+
+func TestMergePatchRoundTrip(t *testing.T) {
+	for _, tt := range roundTripDocuments {
+		t.Run(tt.name, func(t *testing.T) {
+			first := mustParse(t, tt.first)
+			second := mustParse(t, tt.second)
+			mergePatch, err := MergePatch(first, second)
+			if err != nil {
+				t.Fatalf("MergePatch() error = %v", err)
+			}
+			patched := patch.ApplyMergePatch(first, mergePatch)
+			if !patched.Equal(second) {
+				got, _ := patched.Format("", "")
+				t.Errorf("patched document = %s, want %s", got, tt.second)
+			}
+		})
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	mergePatch, err := MergePatch(mustParse(t, `{"a": 1, "b": {"c": 1, "d": 2}, "e": [1, 2]}`), mustParse(t, `{"b": {"c": 1, "d": 3}, "e": [1]}`))
+	if err != nil {
+		t.Fatalf("MergePatch() error = %v", err)
+	}
+	got, _ := mergePatch.Format("", "")
+	if string(got) != `{"a":null,"b":{"d":3},"e":[1]}` {
+		t.Errorf("MergePatch() = %s", got)
+	}
+}
+
+func TestMergePatchNullValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		first  string
+		second string
+	}{
+		{name: "Modified to null", first: `{"a": 1}`, second: `{"a": null}`},
+		{name: "Added null", first: `{}`, second: `{"a": null}`},
+		{name: "Added object with null", first: `{}`, second: `{"a": {"b": {"c": null}}}`},
+		{name: "Replaced by object with null", first: `{"a": [1]}`, second: `{"a": {"b": null}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := MergePatch(mustParse(t, tt.first), mustParse(t, tt.second)); err == nil {
+				t.Errorf("MergePatch() expected error")
+			}
+		})
+	}
+}
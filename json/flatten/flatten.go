@@ -1,57 +1,426 @@
 package flatten
 
 import (
-	"encoding/json"
+	"bytes"
+	"encoding/csv"
+	stdJson "encoding/json"
 	"fmt"
 	"sort"
 	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/sascha-andres/jsonedit/json"
+)
+
+// KeyStyle defines how the path of a value is written
+type KeyStyle string
+
+const (
+	// DottedKeys joins object keys with . and appends array indices as /index padded with leading zeros, e.g. a.b/01
+	DottedKeys KeyStyle = "dotted"
+
+	// PointerKeys writes paths as RFC 6901 JSON Pointer, e.g. /a/b/1
+	PointerKeys KeyStyle = "pointer"
+
+	// BracketKeys joins object keys with . and writes array indices in brackets, e.g. a.b[1]
+	BracketKeys KeyStyle = "bracket"
+)
+
+// Format defines the output format of Flatten
+type Format string
+
+const (
+	// LinesFormat writes one key: value line per value
+	LinesFormat Format = "lines"
+
+	// CSVFormat writes a CSV document with the columns path, type and value
+	CSVFormat Format = "csv"
+
+	// PropertiesFormat writes a Java .properties file
+	PropertiesFormat Format = "properties"
+
+	// EnvFormat writes KEY=VALUE lines of an env file, keys are upper case with all path tokens joined by _
+	EnvFormat Format = "env"
+)
+
+type (
+	// Entry is a single flattened value
+	Entry struct {
+
+		// Key is the path of the value written in the configured key style
+		Key string
+
+		// Tokens are the unescaped object keys and array indices of the path
+		Tokens []string
+
+		// Type is the JSON type of the value
+		Type json.Kind
+
+		// Value is the scalar value, {} or [] for empty containers
+		Value string
+	}
+
+	// flattener holds the configuration of a flattening
+	flattener struct {
+
+		// keyStyle is the style used to write paths
+		keyStyle KeyStyle
+
+		// escapeKeys indicates whether separators within object keys are escaped
+		escapeKeys bool
+
+		// quoteStrings indicates whether strings and null are written as JSON literals
+		quoteStrings bool
+	}
+
+	// Option configures a flattening
+	Option func(*flattener)
 )
 
+// WithKeyStyle sets the style used to write paths, DottedKeys is the default
+func WithKeyStyle(style KeyStyle) Option {
+	return func(f *flattener) {
+		f.keyStyle = style
+	}
+}
+
+// WithEscapedKeys escapes separators within object keys so paths are unambiguous.
+// Dotted keys escape \ . / and : with a backslash, bracket keys write such keys as ["key"].
+// JSON Pointer tokens are always escaped as defined by RFC 6901.
+func WithEscapedKeys(escape bool) Option {
+	return func(f *flattener) {
+		f.escapeKeys = escape
+	}
+}
+
+// WithQuotedStrings writes strings as quoted JSON strings and null as null, so the
+// string "true" can be told apart from the boolean true
+func WithQuotedStrings(quote bool) Option {
+	return func(f *flattener) {
+		f.quoteStrings = quote
+	}
+}
+
 // FlattenJSON accepts a JSON document as a string and returns a sorted slice of strings
 // where each string represents a flattened property:value pair.
 // Nested objects are prefixed with parent property names.
 // Array items are suffixed with /index where index is padded with leading zeros.
-func FlattenJSON(jsonDoc []byte) ([]string, error) {
-	var data interface{}
-	if err := json.Unmarshal(jsonDoc, &data); err != nil {
+// Strings that would be read back by Unflatten as another value, e.g. "123" or "nil", or that
+// span lines are written as quoted JSON strings, so the lines can be unflattened without loss.
+// Options change how keys and values are written.
+func FlattenJSON(jsonDoc []byte, options ...Option) ([]string, error) {
+	f := newFlattener(options)
+	entries, err := f.entries(jsonDoc)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]string, len(entries))
+	for i, entry := range entries {
+		lines[i] = entry.Key + ": " + f.lineValue(entry)
+	}
+	return lines, nil
+}
+
+// Flatten returns the flattened JSON document in the given format. Lines and properties quote
+// strings like FlattenJSON, CSV keeps the type of every value in a column of its own.
+func Flatten(jsonDoc []byte, format Format, options ...Option) ([]byte, error) {
+	f := newFlattener(options)
+	entries, err := f.entries(jsonDoc)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case "", LinesFormat:
+		for _, entry := range entries {
+			buf.WriteString(entry.Key + ": " + f.lineValue(entry) + "\n")
+		}
+	case CSVFormat:
+		writer := csv.NewWriter(&buf)
+		_ = writer.Write([]string{"path", "type", "value"})
+		for _, entry := range entries {
+			_ = writer.Write([]string{entry.Key, entry.Type.String(), entry.Value})
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return nil, err
+		}
+	case PropertiesFormat:
+		for _, entry := range entries {
+			buf.WriteString(escapeProperty(entry.Key, true) + "=" + escapeProperty(f.lineValue(entry), false) + "\n")
+		}
+	case EnvFormat:
+		for _, entry := range entries {
+			buf.WriteString(envKey(entry.Tokens) + "=" + envValue(entry.Value) + "\n")
+		}
+	default:
+		return nil, fmt.Errorf("unknown output format %q", format)
+	}
+	return buf.Bytes(), nil
+}
+
+// Entries parses the JSON document and returns its flattened values sorted by path,
+// array elements in the order of their index. Nested empty containers are returned as
+// entries with the value {} or [].
+func Entries(jsonDoc []byte, options ...Option) ([]Entry, error) {
+	return newFlattener(options).entries(jsonDoc)
+}
+
+// newFlattener returns a flattener configured by the options
+func newFlattener(options []Option) *flattener {
+	f := &flattener{keyStyle: DottedKeys}
+	for _, option := range options {
+		option(f)
+	}
+	return f
+}
+
+// entries parses the JSON document and returns its flattened values sorted by path
+func (f *flattener) entries(jsonDoc []byte) ([]Entry, error) {
+	switch f.keyStyle {
+	case DottedKeys, PointerKeys, BracketKeys:
+	default:
+		return nil, fmt.Errorf("unknown key style %q", f.keyStyle)
+	}
+
+	data, err := json.Parse(jsonDoc)
+	if err != nil {
 		return nil, fmt.Errorf("error parsing JSON: %w", err)
 	}
 
-	lines := make([]string, 0)
-	flattenValue("", data, &lines)
+	entries := make([]Entry, 0)
+	f.flattenValue("", nil, data, &entries)
 
-	// Sort the lines in ascending order
-	sort.Strings(lines)
+	// Sort the entries by path, comparing array indices by value
+	sort.SliceStable(entries, func(i, j int) bool {
+		return lessPath(entries[i].Tokens, entries[j].Tokens)
+	})
 
-	return lines, nil
+	return entries, nil
 }
 
-// flattenValue recursively processes JSON values and adds flattened property:value pairs to lines
-func flattenValue(prefix string, value interface{}, lines *[]string) {
-	switch v := value.(type) {
-	case map[string]interface{}:
-		// Handle objects
-		for k, val := range v {
-			newPrefix := k
-			if prefix != "" {
-				newPrefix = prefix + "." + k
-			}
-			flattenValue(newPrefix, val, lines)
-		}
-	case []interface{}:
-		// Handle arrays
-		padding := len(strconv.Itoa(len(v)))
-		for i, val := range v {
-			// Format index with leading zeros
-			indexStr := fmt.Sprintf("%0*d", padding, i)
-			newPrefix := prefix + "/" + indexStr
-			flattenValue(newPrefix, val, lines)
-		}
-	case nil:
+// flattenValue recursively processes JSON values and adds flattened entries. key is the path
+// written in the key style, tokens the unescaped path.
+func (f *flattener) flattenValue(key string, tokens []string, value *json.Node, entries *[]Entry) {
+	add := func(text string) {
+		*entries = append(*entries, Entry{Key: key, Tokens: tokens, Type: value.Kind, Value: text})
+	}
+	switch value.Kind {
+	case json.ObjectKind:
+		// Handle objects, nested empty objects are kept so they survive unflattening
+		if len(value.Members) == 0 && len(tokens) > 0 {
+			add("{}")
+		}
+		for _, member := range value.Members {
+			f.flattenValue(f.appendKey(key, member.Key), appendToken(tokens, member.Key), member.Value, entries)
+		}
+	case json.ArrayKind:
+		// Handle arrays, nested empty arrays are kept so they survive unflattening
+		if len(value.Items) == 0 && len(tokens) > 0 {
+			add("[]")
+		}
+		padding := len(strconv.Itoa(len(value.Items)))
+		for i, val := range value.Items {
+			f.flattenValue(f.appendIndex(key, i, padding), appendToken(tokens, strconv.Itoa(i)), val, entries)
+		}
+	case json.NullKind:
 		// Handle null values
-		*lines = append(*lines, fmt.Sprintf("%s: nil", prefix))
+		if f.quoteStrings {
+			add("null")
+		} else {
+			add("nil")
+		}
+	case json.BoolKind:
+		add(strconv.FormatBool(value.Boolean))
+	case json.NumberKind:
+		// Numbers are written as in the document to keep their precision
+		add(string(value.Number))
+	default:
+		if f.quoteStrings {
+			quoted, _ := stdJson.Marshal(value.Text)
+			add(string(quoted))
+		} else {
+			add(value.Text)
+		}
+	}
+}
+
+// lineValue returns the value of the entry as written to lines. Unless all strings are quoted,
+// strings that Unflatten would read as another value or that span lines are quoted.
+func (f *flattener) lineValue(entry Entry) string {
+	if entry.Type != json.StringKind || f.quoteStrings || !needsQuotes(entry.Value) {
+		return entry.Value
+	}
+	quoted, _ := stdJson.Marshal(entry.Value)
+	return string(quoted)
+}
+
+// needsQuotes reports whether the string would be read back by Unflatten as another value or
+// does not fit on a single line
+func needsQuotes(text string) bool {
+	return parseValue(text).Kind != json.StringKind || strings.HasPrefix(text, `"`) || strings.ContainsAny(text, "\r\n")
+}
+
+// appendToken returns a copy of tokens with token appended
+func appendToken(tokens []string, token string) []string {
+	return append(tokens[:len(tokens):len(tokens)], token)
+}
+
+// appendKey appends the object key to the path written in the configured key style
+func (f *flattener) appendKey(path, key string) string {
+	switch f.keyStyle {
+	case PointerKeys:
+		return json.AppendPointer(path, key)
+	case BracketKeys:
+		if f.escapeKeys && !isIdentifier(key) {
+			quoted, _ := stdJson.Marshal(key)
+			return path + "[" + string(quoted) + "]"
+		}
 	default:
-		// Handle primitive values
-		*lines = append(*lines, fmt.Sprintf("%s: %v", prefix, v))
+		if f.escapeKeys {
+			key = escapeDottedKey(key)
+		}
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// appendIndex appends the array index to the path written in the configured key style
+func (f *flattener) appendIndex(path string, index, padding int) string {
+	switch f.keyStyle {
+	case PointerKeys:
+		return json.AppendPointer(path, strconv.Itoa(index))
+	case BracketKeys:
+		return path + "[" + strconv.Itoa(index) + "]"
+	default:
+		// Format index with leading zeros
+		return path + "/" + fmt.Sprintf("%0*d", padding, index)
+	}
+}
+
+// escapeDottedKey escapes the separators of the dotted key style with a backslash
+func escapeDottedKey(key string) string {
+	var b strings.Builder
+	for _, r := range key {
+		if r == '\\' || r == '.' || r == '/' || r == ':' {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// isIdentifier reports whether key can be written without brackets in the bracket key style
+func isIdentifier(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, r := range key {
+		if r != '_' && r != '$' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// lessPath orders paths token by token, numeric tokens by value, parents before their children
+func lessPath(a, b []string) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] == b[i] {
+			continue
+		}
+		x, errX := strconv.Atoi(a[i])
+		y, errY := strconv.Atoi(b[i])
+		if errX == nil && errY == nil && x != y {
+			return x < y
+		}
+		return a[i] < b[i]
+	}
+	return len(a) < len(b)
+}
+
+// escapeProperty escapes text for a Java .properties file, key additionally escapes the
+// separators = : and the comment characters # !
+func escapeProperty(text string, key bool) string {
+	var b strings.Builder
+	for i, r := range text {
+		switch {
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r == '\f':
+			b.WriteString(`\f`)
+		case r == ' ' && (key || i == 0):
+			b.WriteString(`\ `)
+		case key && (r == '=' || r == ':' || r == '#' || r == '!'):
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case r < 0x20 || r > 0x7e:
+			// Properties files are ISO 8859-1 encoded, everything else uses unicode escapes
+			for _, unit := range utf16Units(r) {
+				fmt.Fprintf(&b, `\u%04x`, unit)
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// utf16Units returns the UTF-16 code units of r
+func utf16Units(r rune) []rune {
+	if r < 0x10000 {
+		return []rune{r}
+	}
+	r -= 0x10000
+	return []rune{0xd800 + (r>>10)&0x3ff, 0xdc00 + r&0x3ff}
+}
+
+// envKey joins the path tokens with _ and turns them into an upper case variable name
+// containing only letters, digits and underscores
+func envKey(tokens []string) string {
+	var b strings.Builder
+	for i, token := range tokens {
+		if i > 0 {
+			b.WriteRune('_')
+		}
+		for _, r := range strings.ToUpper(token) {
+			if r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+				b.WriteRune(r)
+			} else {
+				b.WriteRune('_')
+			}
+		}
+	}
+	key := b.String()
+	if key == "" || key[0] >= '0' && key[0] <= '9' {
+		key = "_" + key
+	}
+	return key
+}
+
+// envValue quotes value with double quotes unless it consists of characters that need no quoting
+func envValue(value string) string {
+	safe := true
+	for _, r := range value {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("_-.,:/@+%", r)) {
+			safe = false
+			break
+		}
+	}
+	if safe {
+		return value
 	}
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`", "\n", `\n`, "\r", `\r`)
+	return `"` + replacer.Replace(value) + `"`
 }
@@ -2,7 +2,10 @@ package flatten
 
 import (
 	"reflect"
+	"slices"
 	"testing"
+
+	"github.com/sascha-andres/jsonedit/json"
 )
 
 func TestFlattenJSON(t *testing.T) {
@@ -30,6 +33,12 @@ func TestFlattenJSON(t *testing.T) {
 			expected: []string{"person.age: 30", "person.name: John"},
 			wantErr:  false,
 		},
+		{
+			name:     "Big numbers keep their precision",
+			jsonDoc:  `{"id": 12345678901234567890, "price": 1.50}`,
+			expected: []string{"id: 12345678901234567890", "price: 1.50"},
+			wantErr:  false,
+		},
 		{
 			name:     "JSON with array",
 			jsonDoc:  `{"numbers": [1, 2, 3]}`,
@@ -98,3 +107,251 @@ func TestFlattenJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestUnflatten(t *testing.T) {
+	tests := []struct {
+		name    string
+		lines   []string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "Nested objects and padded indices",
+			lines: []string{"a.b/00: x", "a.b/01: nil", "a.c: 1.50", "d: true"},
+			want:  `{"a":{"b":["x",null],"c":1.50},"d":true}`,
+		},
+		{
+			name:  "Root array",
+			lines: []string{"/1.name: b", "/0.name: a"},
+			want:  `[{"name":"a"},{"name":"b"}]`,
+		},
+		{
+			name:  "Strings that look like other values",
+			lines: []string{"a: 0x10", "b: True", "c: 1e", "d: "},
+			want:  `{"a":"0x10","b":"True","c":"1e","d":""}`,
+		},
+		{
+			name:  "Quoted strings",
+			lines: []string{`a: "123"`, `b: "nil"`, `c: "a\nb"`, `d: "unterminated`},
+			want:  `{"a":"123","b":"nil","c":"a\nb","d":"\"unterminated"}`,
+		},
+		{
+			name:  "Empty containers and gaps",
+			lines: []string{"a: {}", "b: []", "c/2: 1", ""},
+			want:  `{"a":{},"b":[],"c":[null,null,1]}`,
+		},
+		{
+			name:  "Sparse index",
+			lines: []string{"a/1: x"},
+			want:  `{"a":[null,"x"]}`,
+		},
+		{
+			name:    "Huge index",
+			lines:   []string{"a/999999999: x"},
+			wantErr: true,
+		},
+		{
+			name:  "No lines",
+			lines: []string{},
+			want:  `{}`,
+		},
+		{
+			name:    "Missing separator",
+			lines:   []string{"a"},
+			wantErr: true,
+		},
+		{
+			name:    "Conflicting types",
+			lines:   []string{"a: 1", "a.b: 2"},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid index",
+			lines:   []string{"a/x: 1"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Unflatten(tt.lines)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unflatten() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			compact, _ := got.Format("", "")
+			if string(compact) != tt.want {
+				t.Errorf("Unflatten() = %s, want %s", compact, tt.want)
+			}
+			if items := got.Get("a"); items != nil && slices.Contains(items.Items, nil) {
+				t.Errorf("Unflatten() left nil items in %s", compact)
+			}
+		})
+	}
+}
+
+func TestUnflattenRoundTrip(t *testing.T) {
+	docs := []string{
+		`{"a":{"b":[1,2,3,4,5,6,7,8,9,10,11]},"c":null,"d":"text","e":{},"f":[],"g":[{"h":false}]}`,
+		`[1,"two",[3.0]]`,
+		`{"big":12345678901234567890}`,
+		`{"a":"123","b":"true","c":"nil","d":"{}","e":"1e5","f":"[]","g":"-1"}`,
+		`{"a":"\"quoted\"","b":"two\nlines","c":"","d":" padded ","e":"null"}`,
+	}
+	for _, doc := range docs {
+		lines, err := FlattenJSON([]byte(doc))
+		if err != nil {
+			t.Fatalf("FlattenJSON(%s) error = %v", doc, err)
+		}
+		got, err := Unflatten(lines)
+		if err != nil {
+			t.Fatalf("Unflatten(%v) error = %v", lines, err)
+		}
+		want, _ := json.Parse([]byte(doc))
+		if !got.Equal(want) {
+			compact, _ := got.Format("", "")
+			t.Errorf("round trip of %s = %s", doc, compact)
+		}
+	}
+}
+
+func TestUnflattenFormat(t *testing.T) {
+	// Unescaped dotted keys cannot hold the separators used in the other documents
+	plain := `{"a":[1,"2",null,"null"],"b":{"c":"nil","d":{},"e":[]},"f":1.50}`
+	escaped := `{"a.b":{"c/d":[true,"true",null,"null"]},"k:e y":"v: 1","ü":{"x y":["a=b","#c"],"e":{},"f":[]},"n":1.50}`
+	tests := []struct {
+		name    string
+		doc     string
+		options []Option
+	}{
+		{name: "Dotted keys", doc: plain},
+		{name: "Escaped dotted keys", doc: escaped, options: []Option{WithEscapedKeys(true)}},
+		{name: "Quoted strings", doc: escaped, options: []Option{WithEscapedKeys(true), WithQuotedStrings(true)}},
+		{name: "Pointer keys", doc: escaped, options: []Option{WithKeyStyle(PointerKeys), WithQuotedStrings(true)}},
+		{name: "Bracket keys", doc: plain, options: []Option{WithKeyStyle(BracketKeys)}},
+		{name: "Escaped bracket keys", doc: escaped, options: []Option{WithKeyStyle(BracketKeys), WithEscapedKeys(true)}},
+	}
+	for _, tt := range tests {
+		for _, format := range []Format{LinesFormat, CSVFormat, PropertiesFormat} {
+			t.Run(tt.name+" "+string(format), func(t *testing.T) {
+				flattened, err := Flatten([]byte(tt.doc), format, tt.options...)
+				if err != nil {
+					t.Fatalf("Flatten() error = %v", err)
+				}
+				got, err := UnflattenFormat(flattened, format, tt.options...)
+				if err != nil {
+					t.Fatalf("UnflattenFormat(%s) error = %v", flattened, err)
+				}
+				want, _ := json.Parse([]byte(tt.doc))
+				if !got.Equal(want) {
+					compact, _ := got.Format("", "")
+					t.Errorf("UnflattenFormat(%s) = %s", flattened, compact)
+				}
+			})
+		}
+	}
+
+	if _, err := UnflattenFormat([]byte("A=1\n"), EnvFormat); err == nil {
+		t.Error("UnflattenFormat() accepted the env format")
+	}
+	if _, err := UnflattenFormat([]byte("a: 1\n"), LinesFormat, WithKeyStyle("unknown")); err == nil {
+		t.Error("UnflattenFormat() accepted an unknown key style")
+	}
+}
+
+func TestFlattenJSONOptions(t *testing.T) {
+	doc := `{"a.b": {"c/d": [true, "true"]}, "e": null, "list": [1,2,3,4,5,6,7,8,9,10,11]}`
+	tests := []struct {
+		name    string
+		options []Option
+		want    []string
+	}{
+		{
+			name:    "Escaped dotted keys and quoted strings",
+			options: []Option{WithEscapedKeys(true), WithQuotedStrings(true)},
+			want:    []string{`a\.b.c\/d/0: true`, `a\.b.c\/d/1: "true"`, `e: null`},
+		},
+		{
+			name:    "Pointer keys",
+			options: []Option{WithKeyStyle(PointerKeys)},
+			want:    []string{`/a.b/c~1d/0: true`, `/a.b/c~1d/1: "true"`, `/e: nil`},
+		},
+		{
+			name:    "Escaped bracket keys",
+			options: []Option{WithKeyStyle(BracketKeys), WithEscapedKeys(true)},
+			want:    []string{`["a.b"]["c/d"][0]: true`, `["a.b"]["c/d"][1]: "true"`, `e: nil`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FlattenJSON([]byte(doc), tt.options...)
+			if err != nil {
+				t.Fatalf("FlattenJSON() error = %v", err)
+			}
+			if !reflect.DeepEqual(got[:3], tt.want) {
+				t.Errorf("FlattenJSON() = %v, want %v", got[:3], tt.want)
+			}
+		})
+	}
+
+	got, _ := FlattenJSON([]byte(doc), WithKeyStyle(BracketKeys))
+	if got[len(got)-2] != "list[9]: 10" || got[len(got)-1] != "list[10]: 11" {
+		t.Errorf("FlattenJSON() does not sort indices by value: %v", got)
+	}
+
+	if _, err := FlattenJSON([]byte(doc), WithKeyStyle("unknown")); err == nil {
+		t.Error("FlattenJSON() accepted an unknown key style")
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	doc := `{"server": {"host name": "a=b", "port": 80, "tags": ["x y"]}, "note": "ü"}`
+	tests := []struct {
+		name    string
+		format  Format
+		options []Option
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "Lines",
+			format: LinesFormat,
+			want:   "note: ü\nserver.host name: a=b\nserver.port: 80\nserver.tags/0: x y\n",
+		},
+		{
+			name:    "CSV",
+			format:  CSVFormat,
+			options: []Option{WithQuotedStrings(true)},
+			want:    "path,type,value\nnote,string,\"\"\"ü\"\"\"\nserver.host name,string,\"\"\"a=b\"\"\"\nserver.port,number,80\nserver.tags/0,string,\"\"\"x y\"\"\"\n",
+		},
+		{
+			name:    "Properties",
+			format:  PropertiesFormat,
+			options: []Option{WithKeyStyle(BracketKeys)},
+			want:    "note=\\u00fc\nserver.host\\ name=a=b\nserver.port=80\nserver.tags[0]=x y\n",
+		},
+		{
+			name:   "Env",
+			format: EnvFormat,
+			want:   "NOTE=\"ü\"\nSERVER_HOST_NAME=\"a=b\"\nSERVER_PORT=80\nSERVER_TAGS_0=\"x y\"\n",
+		},
+		{
+			name:    "Unknown format",
+			format:  "xml",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Flatten([]byte(doc), tt.format, tt.options...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Flatten() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Flatten() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,512 @@
+package flatten
+
+import (
+	"bytes"
+	"encoding/csv"
+	stdJson "encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/sascha-andres/jsonedit/json"
+)
+
+// pathToken is a single step of a flattened property path, either an object key or an array index
+type pathToken struct {
+
+	// key is the object member name, used if index is negative
+	key string
+
+	// index is the array position, -1 for object members
+	index int
+}
+
+// Unflatten rebuilds a JSON document from property:value lines as produced by FlattenJSON.
+// Values are converted back to their JSON type: nil becomes null, true and false become
+// booleans, valid JSON numbers become numbers, {} and [] become empty containers, quoted JSON
+// strings are unquoted and everything else becomes a string. Empty lines are skipped, no lines
+// result in an empty object. The options must match the ones used to flatten the document, the
+// key style and escaping determine how paths are split and quoted strings read null as null.
+// Numeric tokens of JSON Pointer keys are read as array indices.
+func Unflatten(lines []string, options ...Option) (*json.Node, error) {
+	f := newFlattener(options)
+	if err := f.checkKeyStyle(); err != nil {
+		return nil, err
+	}
+
+	var root *json.Node
+	for i, line := range lines {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		separator := f.separatorIndex(line)
+		if separator < 0 {
+			return nil, fmt.Errorf("line %d: missing separator \": \"", i+1)
+		}
+		// An empty string value may have lost its trailing space
+		value := ""
+		if separator+2 <= len(line) {
+			value = line[separator+2:]
+		}
+		var err error
+		root, err = f.insertValue(root, line[:separator], f.parseValue(value), len(lines))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+	}
+	return completed(root), nil
+}
+
+// UnflattenFormat rebuilds a JSON document from the output of Flatten in the given format, read
+// with the options used to flatten it. CSV values are converted using their type column. The env
+// format cannot be unflattened, its keys lose the structure of the document.
+func UnflattenFormat(content []byte, format Format, options ...Option) (*json.Node, error) {
+	switch format {
+	case "", LinesFormat:
+		return Unflatten(strings.Split(string(content), "\n"), options...)
+	case CSVFormat:
+		return unflattenCSV(content, newFlattener(options))
+	case PropertiesFormat:
+		return unflattenProperties(content, newFlattener(options))
+	case EnvFormat:
+		return nil, errors.New("env files cannot be unflattened, their keys lose the structure of the document")
+	default:
+		return nil, fmt.Errorf("unknown input format %q", format)
+	}
+}
+
+// unflattenCSV rebuilds a document from CSV with path, type and value columns
+func unflattenCSV(content []byte, f *flattener) (*json.Node, error) {
+	if err := f.checkKeyStyle(); err != nil {
+		return nil, err
+	}
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = 3
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var root *json.Node
+	for i, record := range records {
+		if i == 0 && record[0] == "path" && record[1] == "type" && record[2] == "value" {
+			continue
+		}
+		value, err := f.typedValue(record[1], record[2])
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", i+1, err)
+		}
+		root, err = f.insertValue(root, record[0], value, len(records))
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", i+1, err)
+		}
+	}
+	return completed(root), nil
+}
+
+// typedValue converts the value of a CSV record to the JSON type named in its type column
+func (f *flattener) typedValue(kind, value string) (*json.Node, error) {
+	switch kind {
+	case json.StringKind.String():
+		if f.quoteStrings {
+			var text string
+			if err := stdJson.Unmarshal([]byte(value), &text); err != nil {
+				return nil, fmt.Errorf("invalid quoted string %s", value)
+			}
+			return json.NewString(text), nil
+		}
+		return json.NewString(value), nil
+	case json.NumberKind.String():
+		if node := parseValue(value); node.Kind == json.NumberKind {
+			return node, nil
+		}
+		return nil, fmt.Errorf("invalid number %q", value)
+	case json.BoolKind.String():
+		if value != "true" && value != "false" {
+			return nil, fmt.Errorf("invalid boolean %q", value)
+		}
+		return json.NewBool(value == "true"), nil
+	case json.NullKind.String():
+		return json.NewNull(), nil
+	case json.ObjectKind.String():
+		return json.NewObject(), nil
+	case json.ArrayKind.String():
+		return json.NewArray(), nil
+	default:
+		return nil, fmt.Errorf("unknown type %q", kind)
+	}
+}
+
+// unflattenProperties rebuilds a document from a Java .properties file
+func unflattenProperties(content []byte, f *flattener) (*json.Node, error) {
+	if err := f.checkKeyStyle(); err != nil {
+		return nil, err
+	}
+
+	var root *json.Node
+	lines := strings.Split(string(content), "\n")
+	for i, line := range lines {
+		line = strings.TrimLeft(strings.TrimRight(line, "\r"), " \t\f")
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		separator := -1
+		for j := 0; j < len(line) && separator < 0; j++ {
+			switch line[j] {
+			case '\\':
+				j++
+			case '=', ':':
+				separator = j
+			}
+		}
+		if separator < 0 {
+			return nil, fmt.Errorf("line %d: missing separator \"=\"", i+1)
+		}
+		key, err := unescapeProperty(line[:separator])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		value, err := unescapeProperty(line[separator+1:])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		root, err = f.insertValue(root, key, f.parseValue(value), len(lines))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+	}
+	return completed(root), nil
+}
+
+// unescapeProperty reverts the escapes written by escapeProperty
+func unescapeProperty(text string) (string, error) {
+	var units []uint16
+	var b strings.Builder
+	flush := func() {
+		b.WriteString(string(utf16.Decode(units)))
+		units = nil
+	}
+	for i := 0; i < len(text); i++ {
+		if text[i] != '\\' {
+			flush()
+			b.WriteByte(text[i])
+			continue
+		}
+		i++
+		if i == len(text) {
+			return "", errors.New("escape at end of text")
+		}
+		switch text[i] {
+		case 'n':
+			flush()
+			b.WriteByte('\n')
+		case 'r':
+			flush()
+			b.WriteByte('\r')
+		case 't':
+			flush()
+			b.WriteByte('\t')
+		case 'f':
+			flush()
+			b.WriteByte('\f')
+		case 'u':
+			if i+5 > len(text) {
+				return "", fmt.Errorf("invalid unicode escape in %q", text)
+			}
+			unit, err := strconv.ParseUint(text[i+1:i+5], 16, 16)
+			if err != nil {
+				return "", fmt.Errorf("invalid unicode escape in %q", text)
+			}
+			units = append(units, uint16(unit))
+			i += 4
+		default:
+			flush()
+			b.WriteByte(text[i])
+		}
+	}
+	flush()
+	return b.String(), nil
+}
+
+// checkKeyStyle returns an error for unknown key styles
+func (f *flattener) checkKeyStyle() error {
+	switch f.keyStyle {
+	case DottedKeys, PointerKeys, BracketKeys:
+		return nil
+	default:
+		return fmt.Errorf("unknown key style %q", f.keyStyle)
+	}
+}
+
+// insertValue places value at the path written in the key style below root. Indices must not exceed
+// limit, the number of input lines, so a single line cannot create a huge array.
+func (f *flattener) insertValue(root *json.Node, path string, value *json.Node, limit int) (*json.Node, error) {
+	tokens, err := f.parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, token := range tokens {
+		if token.index > limit {
+			return nil, fmt.Errorf("index %d in %q exceeds the %d lines of the input", token.index, path, limit)
+		}
+	}
+	return insert(root, tokens, value)
+}
+
+// completed returns the unflattened document, an empty object if there were no values. Array
+// items without a value are set to null.
+func completed(root *json.Node) *json.Node {
+	if root == nil {
+		return json.NewObject()
+	}
+	var fill func(node *json.Node)
+	fill = func(node *json.Node) {
+		for i, item := range node.Items {
+			if item == nil {
+				node.Items[i] = json.NewNull()
+				continue
+			}
+			fill(item)
+		}
+		for _, member := range node.Members {
+			fill(member.Value)
+		}
+	}
+	fill(root)
+	return root
+}
+
+// separatorIndex returns the position of the colon separating the key from the value, followed by
+// a space or ending the line. Escaped colons of escaped dotted keys and colons within quoted
+// bracket keys are skipped. -1 is returned if there is no separator.
+func (f *flattener) separatorIndex(line string) int {
+	quoted := false
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '\\' && (quoted || f.escapeKeys && f.keyStyle == DottedKeys):
+			i++
+		case c == '"' && f.escapeKeys && f.keyStyle == BracketKeys:
+			quoted = !quoted
+		case c == ':' && !quoted && (i+1 == len(line) || line[i+1] == ' '):
+			return i
+		}
+	}
+	return -1
+}
+
+// parseValue converts a flattened value back to a JSON value, with quoted strings null is read as null
+func (f *flattener) parseValue(value string) *json.Node {
+	if f.quoteStrings && value == "null" {
+		return json.NewNull()
+	}
+	return parseValue(value)
+}
+
+// parsePath splits a flattened property path written in the key style into its tokens
+func (f *flattener) parsePath(path string) ([]pathToken, error) {
+	switch f.keyStyle {
+	case PointerKeys:
+		return parsePointerPath(path)
+	case BracketKeys:
+		return parseBracketPath(path, f.escapeKeys)
+	default:
+		return parseDottedPath(path, f.escapeKeys)
+	}
+}
+
+// parseDottedPath splits a dotted property path like a.b/01.c into its tokens, escaped paths
+// read a character following a backslash as part of the key
+func parseDottedPath(path string, escaped bool) ([]pathToken, error) {
+	tokens := make([]pathToken, 0)
+	if path == "" {
+		return tokens, nil
+	}
+
+	var segment strings.Builder
+	separator, start := byte('.'), 0
+	if path[0] == '/' {
+		separator, start = '/', 1
+	}
+	flush := func() error {
+		text := segment.String()
+		segment.Reset()
+		if separator == '/' {
+			index, err := strconv.Atoi(text)
+			if err != nil || index < 0 {
+				return fmt.Errorf("invalid array index %q in %q", text, path)
+			}
+			tokens = append(tokens, pathToken{index: index})
+			return nil
+		}
+		if text == "" && len(tokens) > 0 {
+			return fmt.Errorf("empty property name in %q", path)
+		}
+		tokens = append(tokens, pathToken{key: text, index: -1})
+		return nil
+	}
+	for i := start; i < len(path); i++ {
+		switch c := path[i]; {
+		case escaped && c == '\\' && i+1 < len(path):
+			i++
+			segment.WriteByte(path[i])
+		case c == '.' || c == '/':
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			separator = c
+		default:
+			segment.WriteByte(c)
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// parsePointerPath splits a JSON Pointer into its tokens, numeric tokens are array indices
+func parsePointerPath(path string) ([]pathToken, error) {
+	parts, err := json.ParsePointer(path)
+	if err != nil {
+		return nil, err
+	}
+	tokens := make([]pathToken, len(parts))
+	for i, part := range parts {
+		tokens[i] = pathToken{key: part, index: -1}
+		if index, err := strconv.Atoi(part); err == nil && index >= 0 && strconv.Itoa(index) == part {
+			tokens[i] = pathToken{index: index}
+		}
+	}
+	return tokens, nil
+}
+
+// parseBracketPath splits a property path like a.b[1] into its tokens, escaped paths read
+// keys written as ["key"] as JSON strings
+func parseBracketPath(path string, escaped bool) ([]pathToken, error) {
+	tokens := make([]pathToken, 0)
+	readKey := func(start int) int {
+		end := strings.IndexAny(path[start:], ".[")
+		if end < 0 {
+			return len(path)
+		}
+		return start + end
+	}
+
+	i := 0
+	if path != "" && path[0] != '[' {
+		i = readKey(0)
+		tokens = append(tokens, pathToken{key: path[:i], index: -1})
+	}
+	for i < len(path) {
+		if path[i] == '.' {
+			end := readKey(i + 1)
+			if end == i+1 {
+				return nil, fmt.Errorf("empty property name in %q", path)
+			}
+			tokens = append(tokens, pathToken{key: path[i+1 : end], index: -1})
+			i = end
+			continue
+		}
+
+		// path[i] is [, followed by a quoted key or an index
+		if escaped && i+1 < len(path) && path[i+1] == '"' {
+			end := i + 2
+			for end < len(path) && path[end] != '"' {
+				if path[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			var key string
+			if end+1 >= len(path) || path[end+1] != ']' || stdJson.Unmarshal([]byte(path[i+1:end+1]), &key) != nil {
+				return nil, fmt.Errorf("invalid quoted key in %q", path)
+			}
+			tokens = append(tokens, pathToken{key: key, index: -1})
+			i = end + 2
+			continue
+		}
+		end := strings.IndexByte(path[i:], ']')
+		if end < 0 {
+			return nil, fmt.Errorf("missing ] in %q", path)
+		}
+		segment := path[i+1 : i+end]
+		index, err := strconv.Atoi(segment)
+		if err != nil || index < 0 {
+			return nil, fmt.Errorf("invalid array index %q in %q", segment, path)
+		}
+		tokens = append(tokens, pathToken{index: index})
+		i += end + 1
+	}
+	return tokens, nil
+}
+
+// parseValue converts a flattened value back to a JSON value
+func parseValue(value string) *json.Node {
+	switch value {
+	case "nil":
+		return json.NewNull()
+	case "true":
+		return json.NewBool(true)
+	case "false":
+		return json.NewBool(false)
+	case "{}":
+		return json.NewObject()
+	case "[]":
+		return json.NewArray()
+	}
+	if value != "" && (value[0] == '-' || value[0] >= '0' && value[0] <= '9') && stdJson.Valid([]byte(value)) {
+		return json.NewNumber(stdJson.Number(value))
+	}
+	var text string
+	if strings.HasPrefix(value, `"`) && stdJson.Unmarshal([]byte(value), &text) == nil {
+		return json.NewString(text)
+	}
+	return json.NewString(value)
+}
+
+// insert places value at the location described by tokens below node, creating missing
+// containers, and returns the possibly new node
+func insert(node *json.Node, tokens []pathToken, value *json.Node) (*json.Node, error) {
+	if len(tokens) == 0 {
+		if node != nil {
+			return nil, fmt.Errorf("value defined more than once")
+		}
+		return value, nil
+	}
+
+	token := tokens[0]
+	if token.index < 0 {
+		if node == nil {
+			node = json.NewObject()
+		}
+		if node.Kind != json.ObjectKind {
+			return nil, fmt.Errorf("property %q used on a %s", token.key, node.Kind)
+		}
+		child, err := insert(node.Get(token.key), tokens[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		node.Set(token.key, child)
+		return node, nil
+	}
+
+	if node == nil {
+		node = json.NewArray()
+	}
+	if node.Kind != json.ArrayKind {
+		return nil, fmt.Errorf("index %d used on a %s", token.index, node.Kind)
+	}
+	// Gaps are kept nil until all values are inserted, completed sets them to null
+	for len(node.Items) <= token.index {
+		node.Items = append(node.Items, nil)
+	}
+	child, err := insert(node.Items[token.index], tokens[1:], value)
+	if err != nil {
+		return nil, err
+	}
+	node.Items[token.index] = child
+	return node, nil
+}
@@ -0,0 +1,174 @@
+package form
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sascha-andres/jsonedit/json"
+)
+
+// Edit operations applied by ApplyEdits
+const (
+	// SetEdit replaces the value at Path
+	SetEdit = "set"
+
+	// AddEdit sets the member Key of the object at Path
+	AddEdit = "add"
+
+	// AppendEdit appends an item to the array at Path, a value that is not an array is replaced by one
+	AppendEdit = "append"
+
+	// DeleteEdit removes the member Key or the item Index of the container at Path
+	DeleteEdit = "delete"
+)
+
+// Edit is a single change made in the edit form. Edits are applied to the parsed document
+// instead of the browser rewriting it, so members keep their order and numbers their literal.
+type Edit struct {
+
+	// Op is the kind of change: set, add, append or delete
+	Op string `json:"op"`
+
+	// Path is the path of the form field as used by the generated form, e.g. people[0].name
+	Path string `json:"path"`
+
+	// Key is the member added or deleted by add and delete
+	Key string `json:"key,omitempty"`
+
+	// Index is the array item deleted by delete
+	Index int `json:"index,omitempty"`
+
+	// Type is the JSON type of the new value: string, number, boolean, null, object or array
+	Type string `json:"type,omitempty"`
+
+	// Value is the text of the new value, numbers are kept as literal
+	Value string `json:"value,omitempty"`
+}
+
+// ApplyEdits applies the edits in the given order to doc, which is changed in place
+func ApplyEdits(doc *json.Node, edits []Edit) error {
+	for _, edit := range edits {
+		if err := applyEdit(doc, edit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyEdit applies a single edit to doc
+func applyEdit(doc *json.Node, edit Edit) error {
+	target, err := lookupField(doc, edit.Path)
+	if err != nil {
+		return err
+	}
+
+	switch edit.Op {
+	case SetEdit:
+		value, err := EditValue(edit.Type, edit.Value)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", edit.Path, err)
+		}
+		*target = *value
+	case AddEdit:
+		if target.Kind != json.ObjectKind {
+			return fmt.Errorf("field %s is not an object", edit.Path)
+		}
+		value, err := EditValue(edit.Type, edit.Value)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", edit.Path, err)
+		}
+		target.Set(edit.Key, value)
+	case AppendEdit:
+		value, err := EditValue(edit.Type, edit.Value)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", edit.Path, err)
+		}
+		if target.Kind != json.ArrayKind {
+			*target = *json.NewArray()
+		}
+		target.Items = append(target.Items, value)
+	case DeleteEdit:
+		switch target.Kind {
+		case json.ObjectKind:
+			target.Delete(edit.Key)
+		case json.ArrayKind:
+			if edit.Index < 0 || edit.Index >= len(target.Items) {
+				return fmt.Errorf("field %s has no item %d", edit.Path, edit.Index)
+			}
+			target.Items = append(target.Items[:edit.Index], target.Items[edit.Index+1:]...)
+		default:
+			return fmt.Errorf("field %s is not an object or array", edit.Path)
+		}
+	default:
+		return fmt.Errorf("unknown edit operation %q", edit.Op)
+	}
+	return nil
+}
+
+// EditValue converts the text entered in the edit form to a value of the given JSON type.
+// Numbers must be valid JSON number literals and are kept as written.
+func EditValue(kind, text string) (*json.Node, error) {
+	switch kind {
+	case "", "string":
+		return json.NewString(text), nil
+	case "number":
+		number, err := json.Parse([]byte(strings.TrimSpace(text)))
+		if err != nil || number.Kind != json.NumberKind {
+			return nil, fmt.Errorf("%q is not a number", text)
+		}
+		return number, nil
+	case "boolean":
+		return json.NewBool(strings.EqualFold(strings.TrimSpace(text), "true")), nil
+	case "null":
+		return json.NewNull(), nil
+	case "object":
+		return json.NewObject(), nil
+	case "array":
+		return json.NewArray(), nil
+	default:
+		return nil, fmt.Errorf("unknown type %q", kind)
+	}
+}
+
+// lookupField returns the node shown by the form field path. Object keys may contain the
+// separators . and [, the longest member matching the path is used.
+func lookupField(doc *json.Node, path string) (*json.Node, error) {
+	node, rest := doc, path
+	for first := true; rest != ""; first = false {
+		switch node.Kind {
+		case json.ArrayKind:
+			end := strings.Index(rest, "]")
+			if !strings.HasPrefix(rest, "[") || end < 0 {
+				return nil, fmt.Errorf("field %s does not exist in document", path)
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 || index >= len(node.Items) {
+				return nil, fmt.Errorf("field %s does not exist in document", path)
+			}
+			node, rest = node.Items[index], rest[end+1:]
+		case json.ObjectKind:
+			if !first {
+				if !strings.HasPrefix(rest, ".") {
+					return nil, fmt.Errorf("field %s does not exist in document", path)
+				}
+				rest = rest[1:]
+			}
+			match := -1
+			for i, member := range node.Members {
+				key := member.Key
+				if (rest == key || strings.HasPrefix(rest, key+".") || strings.HasPrefix(rest, key+"[")) &&
+					(match < 0 || len(key) > len(node.Members[match].Key)) {
+					match = i
+				}
+			}
+			if match < 0 {
+				return nil, fmt.Errorf("field %s does not exist in document", path)
+			}
+			node, rest = node.Members[match].Value, rest[len(node.Members[match].Key):]
+		default:
+			return nil, fmt.Errorf("field %s does not exist in document", path)
+		}
+	}
+	return node, nil
+}
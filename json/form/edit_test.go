@@ -0,0 +1,96 @@
+package form
+
+import (
+	"testing"
+
+	"github.com/sascha-andres/jsonedit/json"
+)
+
+func TestApplyEdits(t *testing.T) {
+	const document = `{"b":1,"2":1.0,"a.b":{"c":[1,{"d":"x"}]},"e":{"b":true},"id":12345678901234567890}`
+
+	tests := []struct {
+		name    string
+		edits   []Edit
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "set keeps number literal",
+			edits: []Edit{{Op: SetEdit, Path: "2", Type: "number", Value: "2.0"}},
+			want:  `{"b":1,"2":2.0,"a.b":{"c":[1,{"d":"x"}]},"e":{"b":true},"id":12345678901234567890}`,
+		},
+		{
+			name:  "set key containing separator",
+			edits: []Edit{{Op: SetEdit, Path: "a.b.c[1].d", Type: "string", Value: "y"}},
+			want:  `{"b":1,"2":1.0,"a.b":{"c":[1,{"d":"y"}]},"e":{"b":true},"id":12345678901234567890}`,
+		},
+		{
+			name:  "set nested member",
+			edits: []Edit{{Op: SetEdit, Path: "e.b", Type: "boolean", Value: "false"}},
+			want:  `{"b":1,"2":1.0,"a.b":{"c":[1,{"d":"x"}]},"e":{"b":false},"id":12345678901234567890}`,
+		},
+		{
+			name:  "set changes type",
+			edits: []Edit{{Op: SetEdit, Path: "b", Type: "array"}},
+			want:  `{"b":[],"2":1.0,"a.b":{"c":[1,{"d":"x"}]},"e":{"b":true},"id":12345678901234567890}`,
+		},
+		{
+			name:  "add appends member",
+			edits: []Edit{{Op: AddEdit, Path: "e", Key: "n", Type: "null"}},
+			want:  `{"b":1,"2":1.0,"a.b":{"c":[1,{"d":"x"}]},"e":{"b":true,"n":null},"id":12345678901234567890}`,
+		},
+		{
+			name:  "append item",
+			edits: []Edit{{Op: AppendEdit, Path: "a.b.c", Type: "number", Value: "1e400"}},
+			want:  `{"b":1,"2":1.0,"a.b":{"c":[1,{"d":"x"},1e400]},"e":{"b":true},"id":12345678901234567890}`,
+		},
+		{
+			name:  "append replaces non array",
+			edits: []Edit{{Op: AppendEdit, Path: "b", Type: "string", Value: "s"}},
+			want:  `{"b":["s"],"2":1.0,"a.b":{"c":[1,{"d":"x"}]},"e":{"b":true},"id":12345678901234567890}`,
+		},
+		{
+			name:  "delete member and item",
+			edits: []Edit{{Op: DeleteEdit, Path: "", Key: "b"}, {Op: DeleteEdit, Path: "a.b.c", Index: 0}},
+			want:  `{"2":1.0,"a.b":{"c":[{"d":"x"}]},"e":{"b":true},"id":12345678901234567890}`,
+		},
+		{
+			name:    "invalid number",
+			edits:   []Edit{{Op: SetEdit, Path: "b", Type: "number", Value: "0x10"}},
+			wantErr: true,
+		},
+		{
+			name:    "missing field",
+			edits:   []Edit{{Op: SetEdit, Path: "a.c", Type: "string"}},
+			wantErr: true,
+		},
+		{
+			name:    "index out of range",
+			edits:   []Edit{{Op: DeleteEdit, Path: "a.b.c", Index: 2}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := json.Parse([]byte(document))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			err = ApplyEdits(doc, tt.edits)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ApplyEdits() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got, err := doc.Format("", "")
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("ApplyEdits() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
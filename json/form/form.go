@@ -4,33 +4,50 @@ import (
 	"fmt"
 	"html"
 	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+
+	"github.com/sascha-andres/jsonedit/json"
 )
 
-// GenerateJSONForm recursively generates form elements for JSON data
+// GenerateJSONForm recursively generates form elements for JSON data.
+// data is either a *json.Node, which keeps the member order of the document,
+// or a value as produced by encoding/json, whose object members are rendered sorted by key.
 func GenerateJSONForm(logger *slog.Logger, readOnly bool, data interface{}, path string, indent int) string {
-	var result string
+	node, err := json.FromInterface(data)
+	if err != nil {
+		logger.Error("failed to convert data for form", "err", err)
+		return ""
+	}
 
 	logger.Debug("If in read-only mode, render as monospaced text without edit capabilities")
 	if readOnly {
-		return GenerateReadOnlyJSON(logger, data, path, indent)
+		return generateReadOnlyNode(logger, node, path, indent)
 	}
+	return generateFormNode(logger, node, nil, path, indent)
+}
 
-	switch v := data.(type) {
-	case map[string]interface{}:
+// generateFormNode recursively generates form elements for a JSON node described by schema, which may be nil
+func generateFormNode(logger *slog.Logger, node *json.Node, schema *jsonschema.Schema, path string, indent int) string {
+	var result string
+
+	switch node.Kind {
+	case json.ObjectKind:
 		logger.Debug("Handle objects")
-		if len(v) == 0 {
+		if len(node.Members) == 0 {
 			logger.Debug("Empty object - just add the button to add properties")
 			objectPath := path
 			result += fmt.Sprintf(`<div class="json-field" style="margin-left: %dpx;">`, indent*20)
 			result += fmt.Sprintf(`<em>Empty object</em>`)
 			result += "</div>\n"
 
-			result += fmt.Sprintf(`<div class="json-field" style="margin-left: %dpx;">`, indent*20)
-			result += fmt.Sprintf(`<button type="button" class="add-property-btn" data-path="%s" data-indent="%d" onclick="addProperty(this)">+ Add Property</button>`, objectPath, indent)
-			result += "</div>\n"
+			result += addPropertyHTML(objectPath, indent, node, schema)
 		} else {
 			logger.Debug("Handle objects with properties")
-			for key, value := range v {
+			for _, member := range node.Members {
+				key := html.EscapeString(member.Key)
 				fieldPath := path
 				if fieldPath != "" {
 					fieldPath += "." + key
@@ -44,61 +61,50 @@ func GenerateJSONForm(logger *slog.Logger, readOnly bool, data interface{}, path
 				result += fmt.Sprintf(`<button type="button" class="delete-property-btn" data-path="%s" data-key="%s" onclick="deleteProperty(this)">×</button>`, path, key)
 
 				logger.Debug("Handle nested objects and arrays differently")
-				switch value.(type) {
-				case map[string]interface{}, []interface{}:
-					result += fmt.Sprintf(`<label for="%s">%s:</label>`, fieldPath, key)
+				memberSchema := propertySchema(schema, member.Key)
+				result += labelHTML(fieldPath, key, isRequired(schema, member.Key), memberSchema)
+				switch member.Value.Kind {
+				case json.ObjectKind, json.ArrayKind:
 					result += "</div>\n"
-					result += GenerateJSONForm(logger, readOnly, value, fieldPath, indent+1)
+					result += generateFormNode(logger, member.Value, memberSchema, fieldPath, indent+1)
 				default:
 					logger.Debug("Simple value")
-					strValue := ""
-					if value != nil {
-						strValue = fmt.Sprintf("%v", value)
-					}
-					result += fmt.Sprintf(`<label for="%s">%s:</label>`, fieldPath, key)
-					result += fmt.Sprintf(`<input type="text" name="%s" id="%s" value="%s">`,
-						fieldPath, fieldPath, html.EscapeString(strValue))
+					result += inputHTML(fieldPath, member.Value, memberSchema)
 					result += "</div>\n"
 				}
 			}
 
 			logger.Debug("Add button to add new property at this level")
 			objectPath := path
-			result += fmt.Sprintf(`<div class="json-field" style="margin-left: %dpx;">`, indent*20)
-			result += fmt.Sprintf(`<button type="button" class="add-property-btn" data-path="%s" data-indent="%d" onclick="addProperty(this)">+ Add Property</button>`, objectPath, indent)
-			result += "</div>\n"
+			result += addPropertyHTML(objectPath, indent, node, schema)
 		}
-	case []interface{}:
+	case json.ArrayKind:
 		logger.Debug("Handle arrays")
-		if len(v) == 0 {
+		if len(node.Items) == 0 {
 			logger.Debug("Empty array - display a message")
 			result += fmt.Sprintf(`<div class="json-field" style="margin-left: %dpx;">`, indent*20)
 			result += fmt.Sprintf(`<em>Empty array</em>`)
 			result += "</div>\n"
 		} else {
 			logger.Debug("Handle arrays with items")
-			for i, value := range v {
+			for i, value := range node.Items {
 				fieldPath := fmt.Sprintf("%s[%d]", path, i)
 
 				logger.Debug("Add array index label with indentation")
 				result += fmt.Sprintf(`<div class="json-field" style="margin-left: %dpx;">`, indent*20)
 				logger.Debug("Add delete button for array items")
 				result += fmt.Sprintf(`<button type="button" class="delete-array-item-btn" data-path="%s" data-index="%d" onclick="deleteArrayItem(this)">×</button>`, path, i)
-				result += fmt.Sprintf(`<label for="%s">[%d]:</label>`, fieldPath, i)
+				elementSchema := itemSchema(schema, i)
+				result += labelHTML(fieldPath, fmt.Sprintf("[%d]", i), false, elementSchema)
 
 				logger.Debug("Handle nested objects and arrays differently")
-				switch value.(type) {
-				case map[string]interface{}, []interface{}:
+				switch value.Kind {
+				case json.ObjectKind, json.ArrayKind:
 					result += "</div>\n"
-					result += GenerateJSONForm(logger, readOnly, value, fieldPath, indent+1)
+					result += generateFormNode(logger, value, elementSchema, fieldPath, indent+1)
 				default:
 					logger.Debug("Simple value")
-					strValue := ""
-					if value != nil {
-						strValue = fmt.Sprintf("%v", value)
-					}
-					result += fmt.Sprintf(`<input type="text" name="%s" id="%s" value="%s">`,
-						fieldPath, fieldPath, html.EscapeString(strValue))
+					result += inputHTML(fieldPath, value, elementSchema)
 					result += "</div>\n"
 				}
 			}
@@ -111,14 +117,9 @@ func GenerateJSONForm(logger *slog.Logger, readOnly bool, data interface{}, path
 		result += "</div>\n"
 	default:
 		logger.Debug("Handle primitive values (should only happen for the root if it's not an object or array)")
-		strValue := ""
-		if v != nil {
-			strValue = fmt.Sprintf("%v", v)
-		}
 		result += fmt.Sprintf(`<div class="json-field">`)
-		result += fmt.Sprintf(`<label for="%s">Value:</label>`, path)
-		result += fmt.Sprintf(`<input type="text" name="%s" id="%s" value="%s">`,
-			path, path, html.EscapeString(strValue))
+		result += labelHTML(path, "Value", false, schema)
+		result += inputHTML(path, node, schema)
 		result += "</div>\n"
 	}
 
@@ -128,19 +129,30 @@ func GenerateJSONForm(logger *slog.Logger, readOnly bool, data interface{}, path
 // GenerateReadOnlyJSON renders JSON data as monospaced text without edit capabilities
 // but maintains the visual indentation and hierarchical structure
 func GenerateReadOnlyJSON(logger *slog.Logger, data interface{}, path string, indent int) string {
+	node, err := json.FromInterface(data)
+	if err != nil {
+		logger.Error("failed to convert data for form", "err", err)
+		return ""
+	}
+	return generateReadOnlyNode(logger, node, path, indent)
+}
+
+// generateReadOnlyNode recursively renders a JSON node as monospaced text
+func generateReadOnlyNode(logger *slog.Logger, node *json.Node, path string, indent int) string {
 	var result string
 
-	switch v := data.(type) {
-	case map[string]interface{}:
+	switch node.Kind {
+	case json.ObjectKind:
 		logger.Debug("Handle objects")
-		if len(v) == 0 {
+		if len(node.Members) == 0 {
 			logger.Debug("Empty object")
 			result += fmt.Sprintf(`<div class="json-field" style="margin-left: %dpx;">`, indent*20)
 			result += fmt.Sprintf(`<em>Empty object</em>`)
 			result += "</div>\n"
 		} else {
 			logger.Debug("Handle objects with properties")
-			for key, value := range v {
+			for _, member := range node.Members {
+				key := html.EscapeString(member.Key)
 				fieldPath := path
 				if fieldPath != "" {
 					fieldPath += "." + key
@@ -149,52 +161,46 @@ func GenerateReadOnlyJSON(logger *slog.Logger, data interface{}, path string, in
 				}
 
 				logger.Debug("Add field label with indentation")
-				result += fmt.Sprintf(`<div class="json-field" style="margin-left: %dpx;">`, indent*20)
+				result += fmt.Sprintf(`<div class="json-field" style="margin-left: %dpx;" data-field="%s">`, indent*20, fieldPath)
 				result += fmt.Sprintf(`<label>%s:</label>`, key)
 
 				logger.Debug("Handle nested objects and arrays differently")
-				switch value.(type) {
-				case map[string]interface{}, []interface{}:
+				switch member.Value.Kind {
+				case json.ObjectKind, json.ArrayKind:
 					result += "</div>\n"
-					result += GenerateReadOnlyJSON(logger, value, fieldPath, indent+1)
+					result += generateReadOnlyNode(logger, member.Value, fieldPath, indent+1)
 				default:
 					logger.Debug("Simple value")
-					strValue := ""
-					if value != nil {
-						strValue = fmt.Sprintf("%v", value)
-					}
+					strValue := scalarString(member.Value)
 					result += fmt.Sprintf(`<span style="font-family: 'CustomMonoFont', monospace;">%s</span>`, html.EscapeString(strValue))
 					result += "</div>\n"
 				}
 			}
 		}
-	case []interface{}:
+	case json.ArrayKind:
 		logger.Debug("Handle arrays")
-		if len(v) == 0 {
+		if len(node.Items) == 0 {
 			logger.Debug("Empty array")
 			result += fmt.Sprintf(`<div class="json-field" style="margin-left: %dpx;">`, indent*20)
 			result += fmt.Sprintf(`<em>Empty array</em>`)
 			result += "</div>\n"
 		} else {
 			logger.Debug("Handle arrays with items")
-			for i, value := range v {
+			for i, value := range node.Items {
 				fieldPath := fmt.Sprintf("%s[%d]", path, i)
 
 				logger.Debug("Add array index label with indentation")
-				result += fmt.Sprintf(`<div class="json-field" style="margin-left: %dpx;">`, indent*20)
+				result += fmt.Sprintf(`<div class="json-field" style="margin-left: %dpx;" data-field="%s">`, indent*20, fieldPath)
 				result += fmt.Sprintf(`<label>[%d]:</label>`, i)
 
 				logger.Debug("Handle nested objects and arrays differently")
-				switch value.(type) {
-				case map[string]interface{}, []interface{}:
+				switch value.Kind {
+				case json.ObjectKind, json.ArrayKind:
 					result += "</div>\n"
-					result += GenerateReadOnlyJSON(logger, value, fieldPath, indent+1)
+					result += generateReadOnlyNode(logger, value, fieldPath, indent+1)
 				default:
 					logger.Debug("Simple value")
-					strValue := ""
-					if value != nil {
-						strValue = fmt.Sprintf("%v", value)
-					}
+					strValue := scalarString(value)
 					result += fmt.Sprintf(`<span style="font-family: 'CustomMonoFont', monospace;">%s</span>`, html.EscapeString(strValue))
 					result += "</div>\n"
 				}
@@ -202,10 +208,7 @@ func GenerateReadOnlyJSON(logger *slog.Logger, data interface{}, path string, in
 		}
 	default:
 		logger.Debug("Handle primitive values (should only happen for the root if it's not an object or array)")
-		strValue := ""
-		if v != nil {
-			strValue = fmt.Sprintf("%v", v)
-		}
+		strValue := scalarString(node)
 		result += fmt.Sprintf(`<div class="json-field">`)
 		result += fmt.Sprintf(`<label>Value:</label>`)
 		result += fmt.Sprintf(`<span style="font-family: 'CustomMonoFont', monospace;">%s</span>`, html.EscapeString(strValue))
@@ -214,3 +217,74 @@ func GenerateReadOnlyJSON(logger *slog.Logger, data interface{}, path string, in
 
 	return result
 }
+
+// FieldPath returns the path used as id of the form field showing the value at the JSON
+// Pointer within doc, e.g. /people/0/name becomes people[0].name
+func FieldPath(doc *json.Node, pointer string) (string, error) {
+	if pointer == "" {
+		return "", nil
+	}
+	tokens, err := json.ParsePointer(pointer)
+	if err != nil {
+		return "", err
+	}
+
+	path := ""
+	node := doc
+	for _, token := range tokens {
+		switch {
+		case node == nil:
+			return "", fmt.Errorf("pointer %s does not exist in document", pointer)
+		case node.Kind == json.ObjectKind:
+			node = node.Get(token)
+			if path != "" {
+				path += "."
+			}
+			path += token
+		case node.Kind == json.ArrayKind:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(node.Items) {
+				return "", fmt.Errorf("pointer %s does not exist in document", pointer)
+			}
+			node = node.Items[index]
+			path += fmt.Sprintf("[%d]", index)
+		default:
+			return "", fmt.Errorf("pointer %s does not exist in document", pointer)
+		}
+	}
+	if node == nil {
+		return "", fmt.Errorf("pointer %s does not exist in document", pointer)
+	}
+	return path, nil
+}
+
+// valueTypes are the JSON types offered by the type selector of a form field
+var valueTypes = []json.Kind{json.StringKind, json.NumberKind, json.BoolKind, json.NullKind, json.ObjectKind, json.ArrayKind}
+
+// typeSelectHTML renders the selector holding the JSON type of the text input at fieldPath,
+// so the value is written back with its type instead of a type guessed from the text
+func typeSelectHTML(fieldPath string, node *json.Node) string {
+	var options strings.Builder
+	for _, kind := range valueTypes {
+		selected := ""
+		if kind == node.Kind {
+			selected = " selected"
+		}
+		options.WriteString(fmt.Sprintf(`<option value="%s"%s>%s</option>`, kind, selected, kind))
+	}
+	return fmt.Sprintf(`<select class="type-select" data-for="%s" title="Type">%s</select>`, fieldPath, options.String())
+}
+
+// scalarString returns the text shown for a scalar node, null is shown as an empty string
+func scalarString(node *json.Node) string {
+	switch node.Kind {
+	case json.BoolKind:
+		return fmt.Sprintf("%v", node.Boolean)
+	case json.NumberKind:
+		return node.Number.String()
+	case json.StringKind:
+		return node.Text
+	default:
+		return ""
+	}
+}
@@ -5,6 +5,10 @@ import (
 	"os"
 	"strings"
 	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+
+	"github.com/sascha-andres/jsonedit/json"
 )
 
 // TestGenerateJSONForm tests the GenerateJSONForm method of the App struct
@@ -251,4 +255,145 @@ func TestGenerateJSONForm(t *testing.T) {
 			t.Errorf("Expected empty input field for null value, not found in result")
 		}
 	})
+
+	// Test case 11: Document order is kept
+	t.Run("DocumentOrder", func(t *testing.T) {
+		data, err := json.Parse([]byte(`{"zeta": 1, "alpha": 12345678901234567890}`))
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		result := GenerateJSONForm(logger, false, data, "root", 0)
+
+		zeta := strings.Index(result, "<label for=\"root.zeta\">")
+		alpha := strings.Index(result, "<label for=\"root.alpha\">")
+		if zeta < 0 || alpha < 0 || zeta > alpha {
+			t.Errorf("Expected fields in document order, got zeta at %d and alpha at %d", zeta, alpha)
+		}
+		if !strings.Contains(result, "value=\"12345678901234567890\"") {
+			t.Errorf("Expected number literal to be kept, not found in result")
+		}
+	})
+}
+
+func TestFieldPath(t *testing.T) {
+	doc, err := json.Parse([]byte(`{"people": [{"name": "a", "a/b": {"c": 1}}], "x": 1}`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	tests := []struct {
+		pointer string
+		want    string
+		wantErr bool
+	}{
+		{pointer: "", want: ""},
+		{pointer: "/x", want: "x"},
+		{pointer: "/people/0/name", want: "people[0].name"},
+		{pointer: "/people/0/a~1b/c", want: "people[0].a/b.c"},
+		{pointer: "/people/1", wantErr: true},
+		{pointer: "/missing/a", wantErr: true},
+		{pointer: "/x/y", wantErr: true},
+		{pointer: "x", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := FieldPath(doc, tt.pointer)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("FieldPath(%q) error = %v, wantErr %v", tt.pointer, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("FieldPath(%q) = %q, want %q", tt.pointer, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateJSONFormTypes(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	data, err := json.Parse([]byte(`{"code": "123", "count": 123, "flag": "true", "none": null}`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	result := GenerateJSONForm(logger, false, data, "", 0)
+
+	tests := []struct {
+		field string
+		kind  string
+	}{
+		{"code", "string"},
+		{"count", "number"},
+		{"flag", "string"},
+		{"none", "null"},
+	}
+	for _, tt := range tests {
+		selector := `<select class="type-select" data-for="` + tt.field + `" title="Type">`
+		start := strings.Index(result, selector)
+		if start < 0 {
+			t.Errorf("Expected type selector for %s, not found in result %s", tt.field, result)
+			continue
+		}
+		end := strings.Index(result[start:], "</select>")
+		if !strings.Contains(result[start:start+end], `<option value="`+tt.kind+`" selected>`) {
+			t.Errorf("Expected type %s selected for %s, got %s", tt.kind, tt.field, result[start:start+end])
+		}
+	}
+
+	readOnly := GenerateJSONForm(logger, true, data, "", 0)
+	if strings.Contains(readOnly, "type-select") {
+		t.Errorf("Expected no type selector in read-only mode")
+	}
+}
+
+func TestGenerateSchemaForm(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	compiler := jsonschema.NewCompiler()
+	compiler.AssertFormat()
+	compiler.UseLoader(json.InMemoryLoader{Doc: []byte(`{
+		"type": "object",
+		"required": ["name"],
+		"properties": {
+			"name": { "type": "string", "description": "Full name" },
+			"role": { "enum": ["admin", "user"] },
+			"active": { "type": "boolean" },
+			"age": { "type": "integer", "minimum": 0, "maximum": 150 },
+			"born": { "type": "string", "format": "date" },
+			"address": { "$ref": "#/$defs/address" },
+			"tags": { "type": "array", "items": { "enum": ["a", "b"] } }
+		},
+		"$defs": {
+			"address": { "type": "object", "properties": { "city": { "type": "string" }, "zip": { "type": "string" } } }
+		}
+	}`)})
+	schema, err := compiler.Compile("//")
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	data, err := json.Parse([]byte(`{"name": "a", "role": "user", "active": true, "age": 3, "born": "2000-01-02", "address": {"city": "x"}, "tags": ["b"]}`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	result := GenerateSchemaForm(logger, false, data, schema, "", 0)
+	expected := []string{
+		`<label for="name" title="Full name">name:<span class="required-marker" title="required">*</span></label>`,
+		`<input type="text" name="name" id="name" value="a" title="Full name">`,
+		`<select name="role" id="role"><option value="admin" data-type="string">admin</option><option value="user" data-type="string" selected>user</option></select>`,
+		`<input type="checkbox" name="active" id="active" value="true" checked>`,
+		`<input type="number" name="age" id="age" value="3" step="1" min="0" max="150">`,
+		`<input type="date" name="born" id="born" value="2000-01-02">`,
+		`<select name="tags[0]" id="tags[0]"><option value="a" data-type="string">a</option><option value="b" data-type="string" selected>b</option></select>`,
+		`data-path="address" data-indent="1" data-properties="[&#34;zip&#34;]"`,
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %s, not found in result %s", want, result)
+		}
+	}
+	if strings.Contains(result, `data-path="" data-indent="0"`) {
+		t.Errorf("Expected no add property button for an object containing all defined properties")
+	}
+
+	if GenerateSchemaForm(logger, false, data, nil, "", 0) != GenerateJSONForm(logger, false, data, "", 0) {
+		t.Errorf("Expected the same form as GenerateJSONForm without schema")
+	}
 }
@@ -0,0 +1,295 @@
+package form
+
+import (
+	stdJson "encoding/json"
+	"fmt"
+	"html"
+	"log/slog"
+	"math/big"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+
+	"github.com/sascha-andres/jsonedit/json"
+)
+
+// GenerateSchemaForm generates form elements for JSON data like GenerateJSONForm, using schema to
+// choose the input of each value: enums become selects, booleans checkboxes, numbers numeric inputs
+// limited by minimum and maximum and strings with format date date pickers. Descriptions are shown
+// as tooltips, required properties are marked and adding a property offers only the properties
+// defined by the schema. A nil schema renders the same form as GenerateJSONForm.
+func GenerateSchemaForm(logger *slog.Logger, readOnly bool, data interface{}, schema *jsonschema.Schema, path string, indent int) string {
+	node, err := json.FromInterface(data)
+	if err != nil {
+		logger.Error("failed to convert data for form", "err", err)
+		return ""
+	}
+
+	logger.Debug("If in read-only mode, render as monospaced text without edit capabilities")
+	if readOnly {
+		return generateReadOnlyNode(logger, node, path, indent)
+	}
+	return generateFormNode(logger, node, schema, path, indent)
+}
+
+// subschemas returns schema together with the schemas it references or combines with allOf,
+// which all apply to the same value
+func subschemas(schema *jsonschema.Schema) []*jsonschema.Schema {
+	result := make([]*jsonschema.Schema, 0)
+	var collect func(s *jsonschema.Schema)
+	collect = func(s *jsonschema.Schema) {
+		if s == nil || slices.Contains(result, s) {
+			return
+		}
+		result = append(result, s)
+		collect(s.Ref)
+		for _, sub := range s.AllOf {
+			collect(sub)
+		}
+	}
+	collect(schema)
+	return result
+}
+
+// propertySchema returns the schema of the object member key, nil if the schema does not describe it
+func propertySchema(schema *jsonschema.Schema, key string) *jsonschema.Schema {
+	schemas := subschemas(schema)
+	for _, s := range schemas {
+		if property, ok := s.Properties[key]; ok {
+			return property
+		}
+	}
+	for _, s := range schemas {
+		for pattern, property := range s.PatternProperties {
+			if pattern.MatchString(key) {
+				return property
+			}
+		}
+	}
+	for _, s := range schemas {
+		if additional, ok := s.AdditionalProperties.(*jsonschema.Schema); ok {
+			return additional
+		}
+	}
+	return nil
+}
+
+// itemSchema returns the schema of the array element at index, nil if the schema does not describe it
+func itemSchema(schema *jsonschema.Schema, index int) *jsonschema.Schema {
+	for _, s := range subschemas(schema) {
+		if index < len(s.PrefixItems) {
+			return s.PrefixItems[index]
+		}
+		if s.Items2020 != nil {
+			return s.Items2020
+		}
+		switch items := s.Items.(type) {
+		case *jsonschema.Schema:
+			return items
+		case []*jsonschema.Schema:
+			if index < len(items) {
+				return items[index]
+			}
+			if additional, ok := s.AdditionalItems.(*jsonschema.Schema); ok {
+				return additional
+			}
+		}
+	}
+	return nil
+}
+
+// definedProperties returns the sorted names of the properties defined by the schema
+func definedProperties(schema *jsonschema.Schema) []string {
+	names := make([]string, 0)
+	for _, s := range subschemas(schema) {
+		for name := range s.Properties {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isRequired reports whether the schema requires the property key
+func isRequired(schema *jsonschema.Schema, key string) bool {
+	for _, s := range subschemas(schema) {
+		if slices.Contains(s.Required, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasType reports whether the schema allows the JSON type name
+func hasType(schema *jsonschema.Schema, name string) bool {
+	for _, s := range subschemas(schema) {
+		if s.Types != nil && slices.Contains(s.Types.ToStrings(), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// description returns the first description of the schema, empty if there is none
+func description(schema *jsonschema.Schema) string {
+	for _, s := range subschemas(schema) {
+		if s.Description != "" {
+			return s.Description
+		}
+	}
+	return ""
+}
+
+// enumValues returns the allowed values of the schema, nil if it has no enum of scalars
+func enumValues(schema *jsonschema.Schema) []*json.Node {
+	for _, s := range subschemas(schema) {
+		if s.Enum == nil {
+			continue
+		}
+		values := make([]*json.Node, 0, len(s.Enum.Values))
+		for _, value := range s.Enum.Values {
+			node, err := json.FromInterface(value)
+			if err != nil || node.Kind == json.ObjectKind || node.Kind == json.ArrayKind {
+				return nil
+			}
+			values = append(values, node)
+		}
+		return values
+	}
+	return nil
+}
+
+// formatName returns the format of the schema, empty if there is none
+func formatName(schema *jsonschema.Schema) string {
+	for _, s := range subschemas(schema) {
+		if s.Format != nil {
+			return s.Format.Name
+		}
+	}
+	return ""
+}
+
+// limit returns the first bound selected by bound as decimal literal, empty if there is none
+func limit(schema *jsonschema.Schema, bound func(*jsonschema.Schema) *big.Rat) string {
+	for _, s := range subschemas(schema) {
+		if r := bound(s); r != nil {
+			if r.IsInt() {
+				return r.Num().String()
+			}
+			f, _ := r.Float64()
+			return fmt.Sprintf("%v", f)
+		}
+	}
+	return ""
+}
+
+// labelHTML renders the label of a form field, with the description of the field schema as
+// tooltip and a marker for required properties
+func labelHTML(fieldPath, text string, required bool, fieldSchema *jsonschema.Schema) string {
+	attributes := ""
+	if d := description(fieldSchema); d != "" {
+		attributes = fmt.Sprintf(` title="%s"`, html.EscapeString(d))
+	}
+	marker := ""
+	if required {
+		marker = `<span class="required-marker" title="required">*</span>`
+	}
+	return fmt.Sprintf(`<label for="%s"%s>%s:%s</label>`, fieldPath, attributes, text, marker)
+}
+
+// inputHTML renders the input of a scalar value, chosen by the field schema
+func inputHTML(fieldPath string, node *json.Node, fieldSchema *jsonschema.Schema) string {
+	strValue := scalarString(node)
+	if fieldSchema == nil {
+		return fmt.Sprintf(`<input type="text" name="%s" id="%s" value="%s">`,
+			fieldPath, fieldPath, html.EscapeString(strValue)) + typeSelectHTML(fieldPath, node)
+	}
+
+	title := ""
+	if d := description(fieldSchema); d != "" {
+		title = fmt.Sprintf(` title="%s"`, html.EscapeString(d))
+	}
+
+	if values := enumValues(fieldSchema); values != nil {
+		var options strings.Builder
+		found := false
+		for _, value := range values {
+			selected := ""
+			if value.Equal(node) {
+				selected, found = " selected", true
+			}
+			options.WriteString(fmt.Sprintf(`<option value="%s" data-type="%s"%s>%s</option>`,
+				html.EscapeString(scalarString(value)), value.Kind, selected, html.EscapeString(enumLabel(value))))
+		}
+		if !found {
+			// Keep a value not allowed by the schema selectable so it is not changed silently
+			options.WriteString(fmt.Sprintf(`<option value="%s" data-type="%s" selected>%s</option>`,
+				html.EscapeString(strValue), node.Kind, html.EscapeString(enumLabel(node))))
+		}
+		return fmt.Sprintf(`<select name="%s" id="%s"%s>%s</select>`, fieldPath, fieldPath, title, options.String())
+	}
+
+	switch {
+	case hasType(fieldSchema, "boolean") && (node.Kind == json.BoolKind || node.Kind == json.NullKind):
+		checked := ""
+		if node.Boolean {
+			checked = " checked"
+		}
+		return fmt.Sprintf(`<input type="checkbox" name="%s" id="%s" value="true"%s%s>`, fieldPath, fieldPath, checked, title)
+	case (hasType(fieldSchema, "number") || hasType(fieldSchema, "integer")) && (node.Kind == json.NumberKind || node.Kind == json.NullKind):
+		step := "any"
+		if !hasType(fieldSchema, "number") {
+			step = "1"
+		}
+		attributes := fmt.Sprintf(` step="%s"`, step)
+		if minimum := limit(fieldSchema, func(s *jsonschema.Schema) *big.Rat { return s.Minimum }); minimum != "" {
+			attributes += fmt.Sprintf(` min="%s"`, minimum)
+		}
+		if maximum := limit(fieldSchema, func(s *jsonschema.Schema) *big.Rat { return s.Maximum }); maximum != "" {
+			attributes += fmt.Sprintf(` max="%s"`, maximum)
+		}
+		return fmt.Sprintf(`<input type="number" name="%s" id="%s" value="%s"%s%s>`,
+			fieldPath, fieldPath, html.EscapeString(strValue), attributes, title)
+	case formatName(fieldSchema) == "date" && node.Kind != json.NumberKind && node.Kind != json.BoolKind:
+		return fmt.Sprintf(`<input type="date" name="%s" id="%s" value="%s"%s>`,
+			fieldPath, fieldPath, html.EscapeString(strValue), title)
+	}
+	return fmt.Sprintf(`<input type="text" name="%s" id="%s" value="%s"%s>`,
+		fieldPath, fieldPath, html.EscapeString(strValue), title) + typeSelectHTML(fieldPath, node)
+}
+
+// enumLabel returns the text of an enum option, null is shown as null
+func enumLabel(node *json.Node) string {
+	if node.Kind == json.NullKind {
+		return "null"
+	}
+	return scalarString(node)
+}
+
+// addPropertyHTML renders the button adding a property to the object at objectPath. If the schema
+// defines properties, only the ones missing in node are offered and no button is rendered if all exist.
+func addPropertyHTML(objectPath string, indent int, node *json.Node, schema *jsonschema.Schema) string {
+	attributes := ""
+	if defined := definedProperties(schema); len(defined) > 0 {
+		missing := make([]string, 0)
+		for _, name := range defined {
+			if node.Get(name) == nil {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) == 0 {
+			return ""
+		}
+		names, _ := stdJson.Marshal(missing)
+		attributes = fmt.Sprintf(` data-properties="%s"`, html.EscapeString(string(names)))
+	}
+
+	result := fmt.Sprintf(`<div class="json-field" style="margin-left: %dpx;">`, indent*20)
+	result += fmt.Sprintf(`<button type="button" class="add-property-btn" data-path="%s" data-indent="%d"%s onclick="addProperty(this)">+ Add Property</button>`, objectPath, indent, attributes)
+	result += "</div>\n"
+	return result
+}
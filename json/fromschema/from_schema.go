@@ -1,9 +1,14 @@
 package fromschema
 
 import (
+	stdJson "encoding/json"
 	"fmt"
 	"log/slog"
+	"maps"
+	"math/big"
+	"math/rand/v2"
 	"slices"
+	"strings"
 
 	"github.com/sascha-andres/jsonedit/json"
 
@@ -15,14 +20,56 @@ type SchemaParser struct {
 	compiler *jsonschema.Compiler
 	schema   *jsonschema.Schema
 
+	// choices maps the location of oneOf and anyOf keywords to the index of the branch used
+	choices map[string]int
+
+	// visiting holds the schemas processed while generating, to stop at recursive references
+	visiting map[*jsonschema.Schema]bool
+
+	// expanding holds the schemas whose values are being generated, to stop at references recursing
+	// through arrays and other values that are not processed as objects
+	expanding map[*jsonschema.Schema]bool
+
+	// scope holds the schemas processed for the object being generated, their properties describe
+	// fields required by subschemas like oneOf branches
+	scope []*jsonschema.Schema
+
+	// random generates random values instead of empty ones, nil when generating empty documents
+	random *rand.Rand
+
+	// seeded generates the values empty documents need to match a pattern or to keep array items
+	// unique, its fixed seed keeps empty documents the same
+	seeded *rand.Rand
+
+	// optional indicates whether properties that are not required are added to random documents
+	optional bool
+
 	logger *slog.Logger
 }
 
+// Choice is a oneOf or anyOf keyword of the schema, only one of its branches is used to generate documents
+type Choice struct {
+
+	// Location identifies the keyword by its URL, e.g. file:///schema.json#/properties/payment/oneOf
+	Location string
+
+	// Keyword is either oneOf or anyOf
+	Keyword string
+
+	// Branches describes every branch by its title, description or types
+	Branches []string
+
+	// Selected is the index of the branch used
+	Selected int
+}
+
 // NewSchemaParser creates a new SchemaParser instance
 func NewSchemaParser(logger *slog.Logger, jsonSchema []byte) (*SchemaParser, error) {
 	// Initialize the compiler and add the schema file
 	compiler := jsonschema.NewCompiler()
 	compiler.UseLoader(json.InMemoryLoader{Doc: jsonSchema})
+	// Formats are kept in the compiled schema only if asserted, they are needed to choose form inputs
+	compiler.AssertFormat()
 
 	schema, err := compiler.Compile("//")
 	if err != nil {
@@ -30,41 +77,308 @@ func NewSchemaParser(logger *slog.Logger, jsonSchema []byte) (*SchemaParser, err
 	}
 
 	return &SchemaParser{
-		compiler: compiler,
-		schema:   schema,
-		logger:   logger,
+		compiler:  compiler,
+		schema:    schema,
+		choices:   make(map[string]int),
+		visiting:  make(map[*jsonschema.Schema]bool),
+		expanding: make(map[*jsonschema.Schema]bool),
+		logger:    logger,
 	}, nil
 }
 
-// CreateEmptyJSONDocument generates a JSON document with all required fields added empty
+// NewSchemaParserFromBundle creates a new SchemaParser instance for the main schema of a bundle,
+// resolving references between the files of the bundle
+func NewSchemaParserFromBundle(logger *slog.Logger, bundle *json.SchemaBundle) (*SchemaParser, error) {
+	compiler := jsonschema.NewCompiler()
+	compiler.AssertFormat()
+
+	schema, err := bundle.Compile(compiler)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile schema: %w", err)
+	}
+
+	return &SchemaParser{
+		compiler:  compiler,
+		schema:    schema,
+		choices:   make(map[string]int),
+		visiting:  make(map[*jsonschema.Schema]bool),
+		expanding: make(map[*jsonschema.Schema]bool),
+		logger:    logger,
+	}, nil
+}
+
+// Schema returns the compiled schema
+func (sp *SchemaParser) Schema() *jsonschema.Schema {
+	return sp.schema
+}
+
+// Choose selects the branch of the oneOf or anyOf keyword at location used to generate documents,
+// the first branch is used by default
+func (sp *SchemaParser) Choose(location string, branch int) {
+	sp.choices[location] = branch
+}
+
+// Choices returns the oneOf and anyOf keywords of the schema and the branches selected
+func (sp *SchemaParser) Choices() []Choice {
+	choices := make([]Choice, 0)
+	seen := make(map[*jsonschema.Schema]bool)
+	var walk func(schema *jsonschema.Schema)
+	walk = func(schema *jsonschema.Schema) {
+		if schema == nil || seen[schema] {
+			return
+		}
+		seen[schema] = true
+
+		for _, keyword := range []struct {
+			name     string
+			branches []*jsonschema.Schema
+		}{{"oneOf", schema.OneOf}, {"anyOf", schema.AnyOf}} {
+			if len(keyword.branches) == 0 {
+				continue
+			}
+			choice := Choice{Location: choiceLocation(schema, keyword.name), Keyword: keyword.name}
+			for i, branch := range keyword.branches {
+				choice.Branches = append(choice.Branches, sp.describe(branch, i))
+				if branch == sp.branch(schema, keyword.name, keyword.branches) {
+					choice.Selected = i
+				}
+			}
+			choices = append(choices, choice)
+		}
+
+		walk(schema.Ref)
+		for _, subSchemas := range [][]*jsonschema.Schema{schema.AllOf, schema.OneOf, schema.AnyOf, schema.PrefixItems} {
+			for _, subSchema := range subSchemas {
+				walk(subSchema)
+			}
+		}
+		walk(schema.If)
+		walk(schema.Then)
+		walk(schema.Else)
+		walk(schema.Items2020)
+		switch items := schema.Items.(type) {
+		case *jsonschema.Schema:
+			walk(items)
+		case []*jsonschema.Schema:
+			for _, item := range items {
+				walk(item)
+			}
+		}
+		if additional, ok := schema.AdditionalProperties.(*jsonschema.Schema); ok {
+			walk(additional)
+		}
+		names := make([]string, 0, len(schema.Properties))
+		for name := range schema.Properties {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			walk(schema.Properties[name])
+		}
+	}
+	walk(sp.schema)
+	return choices
+}
+
+// describe returns a label for the branch at index of a oneOf or anyOf keyword
+func (sp *SchemaParser) describe(branch *jsonschema.Schema, index int) string {
+	label := branch.Title
+	if label == "" && branch.Ref != nil {
+		label = branch.Ref.Title
+	}
+	if label == "" {
+		label = branch.Description
+	}
+	if label == "" {
+		if typed := sp.typedSchema(branch); typed != nil {
+			label = strings.Join(schemaTypes(typed), ", ")
+		}
+	}
+	if label == "" {
+		return fmt.Sprintf("%d", index+1)
+	}
+	return fmt.Sprintf("%d: %s", index+1, label)
+}
+
+// choiceLocation returns the location identifying the oneOf or anyOf keyword of the schema
+func choiceLocation(schema *jsonschema.Schema, keyword string) string {
+	return strings.TrimSuffix(schema.Location, "/") + "/" + keyword
+}
+
+// branch returns the selected branch of the oneOf or anyOf keyword, nil if there are no branches
+func (sp *SchemaParser) branch(schema *jsonschema.Schema, keyword string, branches []*jsonschema.Schema) *jsonschema.Schema {
+	if len(branches) == 0 {
+		return nil
+	}
+	index := sp.choices[choiceLocation(schema, keyword)]
+	if index < 0 || index >= len(branches) {
+		sp.logger.Warn("Branch does not exist, using the first one", "location", choiceLocation(schema, keyword), "branch", index)
+		index = 0
+	}
+	return branches[index]
+}
+
+// CreateEmptyJSONDocument generates a JSON document with all required fields added. Fields take the
+// value of const, default, the first example or the first enum value that satisfies their schema,
+// otherwise the smallest value respecting minLength, minItems and the numeric bounds. Strings match
+// pattern, arrays hold unique items and items for contains, objects reach minProperties.
 func (sp *SchemaParser) CreateEmptyJSONDocument() (interface{}, error) {
+	sp.seeded = rand.New(rand.NewPCG(0, 0))
+	defer func() { sp.seeded = nil }()
+	return sp.document(), nil
+}
+
+// generator returns the random numbers used for values an empty value does not satisfy
+func (sp *SchemaParser) generator() *rand.Rand {
+	if sp.random != nil {
+		return sp.random
+	}
+	if sp.seeded == nil {
+		sp.seeded = rand.New(rand.NewPCG(0, 0))
+	}
+	return sp.seeded
+}
+
+// document generates a document for the root schema, an object unless the root schema has annotated values
+func (sp *SchemaParser) document() interface{} {
+	if value, ok := sp.annotatedValue(sp.schema); ok {
+		return value
+	}
 	doc := make(map[string]interface{})
-	sp.processSchema(sp.schema, doc)
-	return doc, nil
+	sp.processObject(sp.schema, doc)
+	return doc
 }
 
-// processSchema recursively processes schema nodes to identify required fields
-func (sp *SchemaParser) processSchema(schema *jsonschema.Schema, currentDoc map[string]interface{}) {
-	for _, reqField := range schema.Required {
-		if _, exists := currentDoc[reqField]; exists {
+// processObject processes the schema of an object, the scope is set to the schemas applied to it
+func (sp *SchemaParser) processObject(schema *jsonschema.Schema, currentDoc map[string]interface{}) {
+	scope := sp.scope
+	sp.scope = sp.subSchemas(schema)
+	sp.processSchema(schema, currentDoc)
+	sp.addMinProperties(currentDoc)
+	sp.scope = scope
+}
+
+// propertyLimits returns the highest minProperties and the lowest maxProperties of the schemas
+// applied to the object, maximum is -1 without maxProperties
+func (sp *SchemaParser) propertyLimits() (minimum, maximum int) {
+	maximum = -1
+	for _, schema := range sp.scope {
+		if schema.MinProperties != nil {
+			minimum = max(minimum, *schema.MinProperties)
+		}
+		if schema.MaxProperties != nil && (maximum < 0 || *schema.MaxProperties < maximum) {
+			maximum = *schema.MaxProperties
+		}
+	}
+	return minimum, maximum
+}
+
+// addMinProperties adds properties until the object has minProperties, declared properties first,
+// then additional properties named property1, property2 and so on if the schemas allow them
+func (sp *SchemaParser) addMinProperties(currentDoc map[string]interface{}) {
+	minimum, _ := sp.propertyLimits()
+	for _, schema := range sp.scope {
+		for _, name := range slices.Sorted(maps.Keys(schema.Properties)) {
+			if len(currentDoc) >= minimum {
+				return
+			}
+			sp.addRequiredField(schema, currentDoc, name)
+		}
+	}
+
+	var additional *jsonschema.Schema
+	for _, schema := range sp.scope {
+		switch value := schema.AdditionalProperties.(type) {
+		case bool:
+			if !value {
+				return
+			}
+		case *jsonschema.Schema:
+			additional = value
+		}
+	}
+	for i := 1; len(currentDoc) < minimum && i <= minimum+maxAttempts; i++ {
+		name := fmt.Sprintf("property%d", i)
+		if _, exists := currentDoc[name]; exists || !sp.allowedName(name) {
 			continue
 		}
+		sp.logger.Debug("Adding property for minProperties", "field", name)
+		if additional != nil {
+			currentDoc[name] = sp.emptyValue(additional, name)
+		} else {
+			currentDoc[name] = nil
+		}
+	}
+}
+
+// allowedName reports whether the name satisfies propertyNames of the schemas applied to the object
+func (sp *SchemaParser) allowedName(name string) bool {
+	for _, schema := range sp.scope {
+		if schema.PropertyNames != nil && schema.PropertyNames.Validate(name) != nil {
+			return false
+		}
+	}
+	return true
+}
 
-		if propSchema, ok := schema.Properties[reqField]; ok {
-			sp.logger.Debug("Adding empty field", "field", reqField)
-			sp.addEmptyField(propSchema, currentDoc, reqField)
+// subSchemas returns the schema and all schemas applied to the same value by $ref, allOf and the
+// selected oneOf and anyOf branches
+func (sp *SchemaParser) subSchemas(schema *jsonschema.Schema) []*jsonschema.Schema {
+	subSchemas := make([]*jsonschema.Schema, 0)
+	seen := make(map[*jsonschema.Schema]bool)
+	var collect func(schema *jsonschema.Schema)
+	collect = func(schema *jsonschema.Schema) {
+		if schema == nil || seen[schema] {
+			return
+		}
+		seen[schema] = true
+		subSchemas = append(subSchemas, schema)
+		collect(schema.Ref)
+		collect(sp.branch(schema, "oneOf", schema.OneOf))
+		collect(sp.branch(schema, "anyOf", schema.AnyOf))
+		for _, subSchema := range schema.AllOf {
+			collect(subSchema)
 		}
 	}
+	collect(schema)
+	return subSchemas
+}
+
+// processSchema recursively processes schema nodes to identify required fields. References, the
+// selected oneOf and anyOf branches, then or else depending on if and dependent properties are followed.
+func (sp *SchemaParser) processSchema(schema *jsonschema.Schema, currentDoc map[string]interface{}) {
+	if sp.visiting[schema] {
+		sp.logger.Debug("Stop at recursive schema", "location", schema.Location)
+		return
+	}
+	sp.visiting[schema] = true
+	defer delete(sp.visiting, schema)
+	sp.scope = append(sp.scope, schema)
+
+	for _, reqField := range schema.Required {
+		sp.addRequiredField(schema, currentDoc, reqField)
+	}
 
-	for propName, propSchema := range schema.Properties {
-		types := propSchema.Types.ToStrings()
+	// Random documents contain either all or only the required properties, optional ones up to maxProperties
+	for _, propName := range slices.Sorted(maps.Keys(schema.Properties)) {
+		propSchema := schema.Properties[propName]
+		if _, maximum := sp.propertyLimits(); maximum >= 0 && len(currentDoc) >= maximum {
+			break
+		}
+		if sp.random != nil {
+			if sp.optional {
+				sp.addRequiredField(schema, currentDoc, propName)
+			}
+			continue
+		}
+		types := schemaTypes(propSchema)
 		if slices.Contains(types, "object") {
 			sp.logger.Debug("Recurse into 'properties' for nested required fields", "field", propName)
 			if _, exists := currentDoc[propName]; !exists {
 				currentDoc[propName] = make(map[string]interface{})
 			}
 			if nestedDoc, isNestedMap := currentDoc[propName].(map[string]interface{}); isNestedMap {
-				sp.processSchema(propSchema, nestedDoc)
+				sp.processObject(propSchema, nestedDoc)
 			}
 		}
 	}
@@ -73,77 +387,543 @@ func (sp *SchemaParser) processSchema(schema *jsonschema.Schema, currentDoc map[
 		sp.logger.Debug("Recurse into 'allOf' for nested required fields", "field", subSchema.ID)
 		sp.processSchema(subSchema, currentDoc)
 	}
+
+	if schema.Ref != nil {
+		sp.logger.Debug("Follow '$ref' for nested required fields", "ref", schema.Ref.Location)
+		sp.processSchema(schema.Ref, currentDoc)
+	}
+
+	if branch := sp.branch(schema, "oneOf", schema.OneOf); branch != nil {
+		sp.logger.Debug("Recurse into selected 'oneOf' branch", "location", branch.Location)
+		sp.processSchema(branch, currentDoc)
+	}
+	if branch := sp.branch(schema, "anyOf", schema.AnyOf); branch != nil {
+		sp.logger.Debug("Recurse into selected 'anyOf' branch", "location", branch.Location)
+		sp.processSchema(branch, currentDoc)
+	}
+
+	// The condition is evaluated against the fields added so far
+	if schema.If != nil {
+		if err := schema.If.Validate(currentDoc); err == nil {
+			if schema.Then != nil {
+				sp.logger.Debug("Recurse into 'then' as 'if' is satisfied", "location", schema.Then.Location)
+				sp.processSchema(schema.Then, currentDoc)
+			}
+		} else if schema.Else != nil {
+			sp.logger.Debug("Recurse into 'else' as 'if' is not satisfied", "location", schema.Else.Location)
+			sp.processSchema(schema.Else, currentDoc)
+		}
+	}
+
+	sp.processDependencies(schema, currentDoc)
 }
 
-// addEmptyField adds an empty field to the document based on the schema type
-func (sp *SchemaParser) addEmptyField(fieldSchema *jsonschema.Schema, currentDoc map[string]interface{}, fieldName string) {
-	types := fieldSchema.Types.ToStrings()
-	if slices.Contains(types, "object") {
-		nestedObject := make(map[string]interface{})
-		currentDoc[fieldName] = nestedObject
-		sp.processSchema(fieldSchema, nestedObject)
-	} else if slices.Contains(fieldSchema.Types.ToStrings(), "array") {
-		emptyArray := []interface{}{}
-		if fieldSchema.Items2020 != nil {
-			sp.logger.Debug("Adding empty array item (2020)", "field", fieldName)
-			types = fieldSchema.Items2020.Types.ToStrings()
-			sp.logger.Debug("Adding array item (2020)", "field", fieldName, "types", types)
-			if slices.Contains(types, "object") {
-				tempNestedObject := make(map[string]interface{})
-				sp.processSchema(fieldSchema.Items2020, tempNestedObject)
-				if len(tempNestedObject) > 0 {
-					emptyArray = []interface{}{tempNestedObject}
-				}
-			} else {
-				if slices.Contains(types, "string") {
-					emptyArray = append(emptyArray, "")
-				} else if slices.Contains(types, "number") || slices.Contains(types, "integer") {
-					emptyArray = append(emptyArray, 0)
-				} else if slices.Contains(types, "boolean") {
-					emptyArray = append(emptyArray, false)
-				} else {
-					emptyArray = append(emptyArray, nil)
-				}
+// addRequiredField adds a required field missing in the document
+func (sp *SchemaParser) addRequiredField(schema *jsonschema.Schema, currentDoc map[string]interface{}, reqField string) {
+	if _, exists := currentDoc[reqField]; exists {
+		return
+	}
+
+	if propSchema, ok := sp.propertySchema(schema, reqField); ok {
+		sp.logger.Debug("Adding empty field", "field", reqField)
+		currentDoc[reqField] = sp.emptyValue(propSchema, reqField)
+	} else if additional, ok := schema.AdditionalProperties.(*jsonschema.Schema); ok {
+		sp.logger.Debug("Adding empty additional field", "field", reqField)
+		currentDoc[reqField] = sp.emptyValue(additional, reqField)
+	} else {
+		// Required without a schema, any value is valid
+		currentDoc[reqField] = nil
+	}
+}
+
+// propertySchema returns the schema of the property, declared by the schema or another schema of the object
+func (sp *SchemaParser) propertySchema(schema *jsonschema.Schema, name string) (*jsonschema.Schema, bool) {
+	if propSchema, ok := schema.Properties[name]; ok {
+		return propSchema, true
+	}
+	for _, scope := range sp.scope {
+		if propSchema, ok := scope.Properties[name]; ok {
+			return propSchema, true
+		}
+	}
+	return nil, false
+}
+
+// processDependencies adds the fields required by fields present in the document, using dependentRequired,
+// dependentSchemas and dependencies of older drafts, until no more fields are added
+func (sp *SchemaParser) processDependencies(schema *jsonschema.Schema, currentDoc map[string]interface{}) {
+	required := make(map[string][]string, len(schema.DependentRequired))
+	schemas := make(map[string]*jsonschema.Schema, len(schema.DependentSchemas))
+	for field, dependents := range schema.DependentRequired {
+		required[field] = dependents
+	}
+	for field, dependent := range schema.DependentSchemas {
+		schemas[field] = dependent
+	}
+	for field, dependency := range schema.Dependencies {
+		switch dependency := dependency.(type) {
+		case []string:
+			required[field] = append(required[field], dependency...)
+		case *jsonschema.Schema:
+			schemas[field] = dependency
+		}
+	}
+
+	processed := make(map[string]bool)
+	for added := true; added; {
+		added = false
+		for _, field := range slices.Sorted(maps.Keys(required)) {
+			dependents := required[field]
+			if _, exists := currentDoc[field]; !exists {
+				continue
 			}
-		} else if fieldSchema.Items != nil {
-			sp.logger.Debug("Adding empty array item (Draft7 & older)", "field", fieldName)
-			switch fieldSchema.Items.(type) {
-			case *jsonschema.Schema:
-				s := fieldSchema.Items.(*jsonschema.Schema)
-				types = s.Types.ToStrings()
-				sp.logger.Debug("Adding array item (Draft7 & older)", "field", fieldName, "types", types)
-				if slices.Contains(types, "object") {
-					tempNestedObject := make(map[string]interface{})
-					sp.processSchema(s, tempNestedObject)
-					if len(tempNestedObject) > 0 {
-						emptyArray = []interface{}{tempNestedObject}
-					}
-				} else {
-					if slices.Contains(types, "string") {
-						emptyArray = append(emptyArray, "")
-					} else if slices.Contains(types, "number") || slices.Contains(types, "integer") {
-						emptyArray = append(emptyArray, 0)
-					} else if slices.Contains(types, "boolean") {
-						emptyArray = append(emptyArray, false)
-					} else {
-						emptyArray = append(emptyArray, nil)
-					}
+			for _, dependent := range dependents {
+				if _, exists := currentDoc[dependent]; !exists {
+					sp.logger.Debug("Adding dependent field", "field", dependent, "dependency", field)
+					sp.addRequiredField(schema, currentDoc, dependent)
+					added = true
 				}
-			default:
-				sp.logger.Warn("nil or []*jsonschema.Schema in Draft7 & older", "field", fieldName)
 			}
 		}
-		currentDoc[fieldName] = emptyArray
+		for _, field := range slices.Sorted(maps.Keys(schemas)) {
+			dependent := schemas[field]
+			if _, exists := currentDoc[field]; !exists || processed[field] {
+				continue
+			}
+			processed[field] = true
+			length := len(currentDoc)
+			sp.processSchema(dependent, currentDoc)
+			added = added || len(currentDoc) != length
+		}
+	}
+}
+
+// emptyValue returns the value added for a field based on the annotations and type of its schema
+func (sp *SchemaParser) emptyValue(fieldSchema *jsonschema.Schema, fieldName string) interface{} {
+	if value, ok := sp.annotatedValue(fieldSchema); ok {
+		return value
+	}
+	if sp.expanding[fieldSchema] {
+		sp.logger.Debug("Stop at recursive schema", "field", fieldName, "location", fieldSchema.Location)
+		return sp.recursiveValue(fieldSchema)
+	}
+	sp.expanding[fieldSchema] = true
+	defer delete(sp.expanding, fieldSchema)
+
+	// Without a type keyword, the type is declared by a referenced schema or a subschema
+	typed := sp.typedSchema(fieldSchema)
+	if typed == nil {
+		typed = fieldSchema
+	}
+	types := schemaTypes(typed)
+	if typed != fieldSchema && !slices.Contains(types, "object") {
+		return sp.emptyValue(typed, fieldName)
+	}
+	if slices.Contains(types, "object") {
+		nestedObject := make(map[string]interface{})
+		sp.processObject(fieldSchema, nestedObject)
+		return nestedObject
+	} else if slices.Contains(types, "array") {
+		return sp.emptyArray(fieldSchema, fieldName)
 	} else if slices.Contains(types, "string") {
-		currentDoc[fieldName] = ""
-	} else if slices.Contains(types, "number") || slices.Contains(types, "integer") {
-		currentDoc[fieldName] = 0
+		if sp.random != nil {
+			return sp.randomString(fieldSchema)
+		}
+		return sp.emptyString(fieldSchema)
+	} else if slices.Contains(types, "integer") {
+		if sp.random != nil {
+			return sp.randomNumber(fieldSchema, true)
+		}
+		return sp.emptyNumber(fieldSchema, true)
+	} else if slices.Contains(types, "number") {
+		if sp.random != nil {
+			return sp.randomNumber(fieldSchema, false)
+		}
+		return sp.emptyNumber(fieldSchema, false)
 	} else if slices.Contains(types, "boolean") {
-		currentDoc[fieldName] = false
+		return sp.random != nil && sp.random.IntN(2) == 0
 	} else if slices.Contains(types, "null") {
-		currentDoc[fieldName] = nil
-	} else {
-		sp.logger.Warn("Unknown field type", "field", fieldName, "types", types)
-		currentDoc[fieldName] = nil
+		return nil
+	}
+	sp.logger.Warn("Unknown field type", "field", fieldName, "types", types)
+	return nil
+}
+
+// recursiveValue returns the value of a schema referencing itself, an empty array or object if it
+// is one of those and null otherwise
+func (sp *SchemaParser) recursiveValue(fieldSchema *jsonschema.Schema) interface{} {
+	typed := sp.typedSchema(fieldSchema)
+	if typed == nil {
+		return nil
+	}
+	types := schemaTypes(typed)
+	switch {
+	case slices.Contains(types, "array"):
+		return make([]interface{}, 0)
+	case slices.Contains(types, "object"):
+		return make(map[string]interface{})
+	}
+	return nil
+}
+
+// typedSchema returns the schema declaring the type or the values of the schema, following $ref,
+// allOf and the selected oneOf and anyOf branches. It returns nil if no such schema is found.
+func (sp *SchemaParser) typedSchema(schema *jsonschema.Schema) *jsonschema.Schema {
+	seen := make(map[*jsonschema.Schema]bool)
+	var find func(schema *jsonschema.Schema) *jsonschema.Schema
+	find = func(schema *jsonschema.Schema) *jsonschema.Schema {
+		if schema == nil || seen[schema] {
+			return nil
+		}
+		seen[schema] = true
+		if len(schemaTypes(schema)) > 0 || schema.Const != nil || schema.Enum != nil {
+			return schema
+		}
+		candidates := []*jsonschema.Schema{
+			schema.Ref,
+			sp.branch(schema, "oneOf", schema.OneOf),
+			sp.branch(schema, "anyOf", schema.AnyOf),
+		}
+		for _, candidate := range append(candidates, schema.AllOf...) {
+			if typed := find(candidate); typed != nil {
+				return typed
+			}
+		}
+		return nil
+	}
+	return find(schema)
+}
+
+// annotatedValue returns the first of const, default, examples and enum values satisfying the schema.
+// Random documents use a random one of these values.
+func (sp *SchemaParser) annotatedValue(schema *jsonschema.Schema) (interface{}, bool) {
+	candidates := make([]any, 0)
+	if schema.Const != nil {
+		candidates = append(candidates, *schema.Const)
+	}
+	if schema.Default != nil {
+		candidates = append(candidates, *schema.Default)
+	}
+	candidates = append(candidates, schema.Examples...)
+	if schema.Enum != nil {
+		candidates = append(candidates, schema.Enum.Values...)
+	}
+	if sp.random != nil {
+		sp.random.Shuffle(len(candidates), func(i, j int) {
+			candidates[i], candidates[j] = candidates[j], candidates[i]
+		})
+	}
+	for _, candidate := range candidates {
+		if err := schema.Validate(candidate); err == nil {
+			return clone(candidate), true
+		}
+		sp.logger.Debug("Skipping value not satisfying the schema", "value", candidate)
+	}
+	return nil, false
+}
+
+// emptyArray returns an array with minItems elements, but at least one if maxItems allows.
+// Elements that are objects without required fields are only added to reach minItems, elements
+// equal to another one are replaced by random ones for uniqueItems. Random arrays have a random
+// length within minItems and maxItems. Items satisfying contains are added up to minContains.
+func (sp *SchemaParser) emptyArray(fieldSchema *jsonschema.Schema, fieldName string) []interface{} {
+	count := 1
+	if fieldSchema.MinItems != nil && *fieldSchema.MinItems > count {
+		count = *fieldSchema.MinItems
+	}
+	if fieldSchema.MaxItems != nil && *fieldSchema.MaxItems < count {
+		count = *fieldSchema.MaxItems
+	}
+	if sp.random != nil {
+		count = sp.randomArrayLength(fieldSchema)
+	}
+
+	emptyArray := make([]interface{}, 0, count)
+	for i := 0; i < count; i++ {
+		itemSchema := itemSchema(fieldSchema, i)
+		if itemSchema == nil {
+			if fieldSchema.MinItems == nil || i >= *fieldSchema.MinItems {
+				break
+			}
+			emptyArray = append(emptyArray, nil)
+			continue
+		}
+		sp.logger.Debug("Adding array item", "field", fieldName, "index", i, "types", schemaTypes(itemSchema))
+		if sp.random != nil {
+			item, ok := sp.randomItem(fieldSchema, itemSchema, emptyArray, fieldName)
+			if !ok {
+				break
+			}
+			emptyArray = append(emptyArray, item)
+			continue
+		}
+		item := sp.emptyValue(itemSchema, fieldName)
+		if object, ok := item.(map[string]interface{}); ok && len(object) == 0 && (fieldSchema.MinItems == nil || i >= *fieldSchema.MinItems) {
+			break
+		}
+		item, ok := sp.distinctItem(fieldSchema, itemSchema, item, emptyArray, fieldName)
+		if !ok {
+			break
+		}
+		emptyArray = append(emptyArray, item)
+	}
+	return sp.addContained(fieldSchema, emptyArray, fieldName)
+}
+
+// distinctItem returns the item unless the array requires unique items and already holds it,
+// then a random item is generated instead. It returns false if no distinct item is found.
+func (sp *SchemaParser) distinctItem(fieldSchema, itemSchema *jsonschema.Schema, item interface{}, items []interface{}, fieldName string) (interface{}, bool) {
+	if !fieldSchema.UniqueItems || !slicesContainsEqual(items, item) {
+		return item, true
+	}
+	random := sp.random
+	sp.random = sp.generator()
+	defer func() { sp.random = random }()
+	return sp.randomItem(fieldSchema, itemSchema, items, fieldName)
+}
+
+// addContained adds items satisfying contains until there are minContains of them, one by default.
+// At maxItems the last items not satisfying contains are replaced. Added items are generated for
+// contains and, if they do not satisfy the schema of the item, randomly for the item until they do.
+func (sp *SchemaParser) addContained(fieldSchema *jsonschema.Schema, items []interface{}, fieldName string) []interface{} {
+	if fieldSchema.Contains == nil {
+		return items
+	}
+	minimum := 1
+	if fieldSchema.MinContains != nil {
+		minimum = *fieldSchema.MinContains
+	}
+	contained := 0
+	for _, item := range items {
+		if fieldSchema.Contains.Validate(item) == nil {
+			contained++
+		}
+	}
+
+	for ; contained < minimum; contained++ {
+		index := len(items)
+		if fieldSchema.MaxItems != nil && index >= *fieldSchema.MaxItems {
+			for index = len(items) - 1; index >= 0 && fieldSchema.Contains.Validate(items[index]) == nil; index-- {
+			}
+			if index < 0 {
+				break
+			}
+		}
+		others := slices.Delete(slices.Clone(items), min(index, len(items)), min(index+1, len(items)))
+		item, ok := sp.containedItem(fieldSchema, itemSchema(fieldSchema, index), others, fieldName)
+		if !ok {
+			sp.logger.Debug("No item satisfying contains found", "field", fieldName)
+			break
+		}
+		if index == len(items) {
+			items = append(items, item)
+		} else {
+			items[index] = item
+		}
+	}
+	return items
+}
+
+// containedItem returns an item satisfying contains and the schema of the item that is distinct
+// from the other items if the array requires unique items
+func (sp *SchemaParser) containedItem(fieldSchema, itemSchema *jsonschema.Schema, items []interface{}, fieldName string) (interface{}, bool) {
+	satisfies := func(item interface{}) bool {
+		return fieldSchema.Contains.Validate(item) == nil && (itemSchema == nil || itemSchema.Validate(item) == nil) &&
+			(!fieldSchema.UniqueItems || !slicesContainsEqual(items, item))
+	}
+	// Without a type contains only constrains the items, which are generated for their own schema
+	schemas := []*jsonschema.Schema{fieldSchema.Contains}
+	if itemSchema != nil {
+		schemas = append(schemas, itemSchema)
+		if sp.typedSchema(fieldSchema.Contains) == nil {
+			schemas[0], schemas[1] = itemSchema, fieldSchema.Contains
+		}
+	}
+	if item := sp.emptyValue(schemas[0], fieldName); satisfies(item) {
+		return item, true
+	}
+
+	random := sp.random
+	sp.random = sp.generator()
+	defer func() { sp.random = random }()
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if item := sp.emptyValue(schemas[attempt%len(schemas)], fieldName); satisfies(item) {
+			return item, true
+		}
+	}
+	return nil, false
+}
+
+// itemSchema returns the schema of the array element at index, nil if the schema does not describe it
+func itemSchema(schema *jsonschema.Schema, index int) *jsonschema.Schema {
+	if index < len(schema.PrefixItems) {
+		return schema.PrefixItems[index]
+	}
+	if schema.Items2020 != nil {
+		return schema.Items2020
+	}
+	switch items := schema.Items.(type) {
+	case *jsonschema.Schema:
+		return items
+	case []*jsonschema.Schema:
+		if index < len(items) {
+			return items[index]
+		}
+		if additional, ok := schema.AdditionalItems.(*jsonschema.Schema); ok {
+			return additional
+		}
+	}
+	return nil
+}
+
+// emptyString returns the empty string, padded to minLength. If it does not match pattern, a
+// string generated for the pattern is used.
+func (sp *SchemaParser) emptyString(fieldSchema *jsonschema.Schema) string {
+	value := ""
+	if fieldSchema.MinLength != nil {
+		value = strings.Repeat("a", *fieldSchema.MinLength)
+	}
+	if fieldSchema.Pattern == nil || fieldSchema.Pattern.MatchString(value) {
+		return value
+	}
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		generated, err := patternString(sp.generator(), fieldSchema.Pattern.String())
+		if err != nil {
+			sp.logger.Warn("Pattern is not supported", "pattern", fieldSchema.Pattern.String(), "err", err)
+			break
+		}
+		if stringSatisfies(fieldSchema, generated) {
+			return generated
+		}
+	}
+	return value
+}
+
+// emptyNumber returns the number closest to 0 within minimum, maximum and multipleOf. Candidates
+// are 0, the bounds and the middle between them, the first one satisfying all constraints is used.
+// The number is computed exactly and returned as literal.
+func (sp *SchemaParser) emptyNumber(fieldSchema *jsonschema.Schema, integer bool) stdJson.Number {
+	one := big.NewRat(1, 1)
+	lower, upper := fieldSchema.Minimum, fieldSchema.Maximum
+	candidates := []*big.Rat{new(big.Rat)}
+	if fieldSchema.Minimum != nil {
+		candidates = append(candidates, fieldSchema.Minimum)
+	}
+	if fieldSchema.ExclusiveMinimum != nil {
+		candidates = append(candidates, new(big.Rat).Add(fieldSchema.ExclusiveMinimum, one))
+		if lower == nil || fieldSchema.ExclusiveMinimum.Cmp(lower) > 0 {
+			lower = fieldSchema.ExclusiveMinimum
+		}
+	}
+	if fieldSchema.Maximum != nil {
+		candidates = append(candidates, fieldSchema.Maximum)
+	}
+	if fieldSchema.ExclusiveMaximum != nil {
+		candidates = append(candidates, new(big.Rat).Sub(fieldSchema.ExclusiveMaximum, one))
+		if upper == nil || fieldSchema.ExclusiveMaximum.Cmp(upper) < 0 {
+			upper = fieldSchema.ExclusiveMaximum
+		}
+	}
+	if lower != nil && upper != nil {
+		middle := new(big.Rat).Add(lower, upper)
+		candidates = append(candidates, middle.Mul(middle, big.NewRat(1, 2)))
+	}
+
+	for i, candidate := range candidates {
+		candidate = roundUp(candidate, integer, fieldSchema.MultipleOf)
+		candidates[i] = candidate
+		if numberSatisfies(fieldSchema, candidate) {
+			return numberLiteral(candidate)
+		}
+	}
+	return numberLiteral(candidates[0])
+}
+
+// numberLiteral returns the number as JSON number literal, exact unless it has no finite decimal
+// representation, like 1/3, which is then written with 16 more decimals
+func numberLiteral(number *big.Rat) stdJson.Number {
+	if number.IsInt() {
+		return stdJson.Number(number.Num().String())
+	}
+	precision, exact := number.FloatPrec()
+	if !exact {
+		precision += 16
+	}
+	return stdJson.Number(number.FloatString(precision))
+}
+
+// roundUp rounds the number up to the next integer and multiple of multipleOf if given
+func roundUp(number *big.Rat, integer bool, multipleOf *big.Rat) *big.Rat {
+	if integer && !number.IsInt() {
+		number = ceil(number)
+	}
+	if multipleOf != nil && multipleOf.Sign() != 0 {
+		quotient := new(big.Rat).Quo(number, multipleOf)
+		if !quotient.IsInt() {
+			number = new(big.Rat).Mul(ceil(quotient), multipleOf)
+		}
+	}
+	return number
+}
+
+// ceil returns the smallest integer not less than the number
+func ceil(number *big.Rat) *big.Rat {
+	quotient, remainder := new(big.Int).QuoRem(number.Num(), number.Denom(), new(big.Int))
+	if remainder.Sign() > 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+	return new(big.Rat).SetInt(quotient)
+}
+
+// floor returns the greatest integer not greater than the number
+func floor(number *big.Rat) *big.Rat {
+	return new(big.Rat).Neg(ceil(new(big.Rat).Neg(number)))
+}
+
+// numberSatisfies reports whether the number is within the bounds of the schema and a multiple of multipleOf
+func numberSatisfies(schema *jsonschema.Schema, number *big.Rat) bool {
+	if schema.Minimum != nil && number.Cmp(schema.Minimum) < 0 {
+		return false
+	}
+	if schema.ExclusiveMinimum != nil && number.Cmp(schema.ExclusiveMinimum) <= 0 {
+		return false
+	}
+	if schema.Maximum != nil && number.Cmp(schema.Maximum) > 0 {
+		return false
+	}
+	if schema.ExclusiveMaximum != nil && number.Cmp(schema.ExclusiveMaximum) >= 0 {
+		return false
+	}
+	if schema.MultipleOf != nil && schema.MultipleOf.Sign() != 0 {
+		return new(big.Rat).Quo(number, schema.MultipleOf).IsInt()
+	}
+	return true
+}
+
+// clone returns a deep copy of a value of the schema, so the generated document never shares maps
+// or slices with the compiled schema
+func clone(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		c := make(map[string]any, len(v))
+		for key, item := range v {
+			c[key] = clone(item)
+		}
+		return c
+	case []any:
+		c := make([]any, len(v))
+		for i, item := range v {
+			c[i] = clone(item)
+		}
+		return c
+	}
+	return value
+}
+
+// schemaTypes returns the types the schema allows, empty if it has no type keyword
+func schemaTypes(schema *jsonschema.Schema) []string {
+	if schema.Types == nil {
+		return nil
 	}
+	return schema.Types.ToStrings()
 }
@@ -5,7 +5,11 @@ import (
 	"log/slog"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
+
+	jsonedit "github.com/sascha-andres/jsonedit/json"
+	"github.com/sascha-andres/jsonedit/json/validate"
 )
 
 func TestNewSchemaParser(t *testing.T) {
@@ -274,3 +278,391 @@ func TestCreateEmptyJSONDocument(t *testing.T) {
 		})
 	}
 }
+
+func TestNewSchemaParserFromBundle(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	bundle := jsonedit.NewSchemaBundle()
+	bundle.Add("main.json", []byte(`{"type": "object", "required": ["name"], "properties": {"name": {"$ref": "defs/name.json"}}}`))
+	bundle.Add("defs/name.json", []byte(`{"type": "string"}`))
+
+	parser, err := NewSchemaParserFromBundle(logger, bundle)
+	if err != nil {
+		t.Fatalf("NewSchemaParserFromBundle() error = %v", err)
+	}
+	if parser.Schema().Properties["name"].Ref == nil {
+		t.Errorf("NewSchemaParserFromBundle() did not resolve the reference to defs/name.json")
+	}
+
+	bundle.Main = "missing.json"
+	if _, err := NewSchemaParserFromBundle(logger, bundle); err == nil {
+		t.Errorf("NewSchemaParserFromBundle() expected error for a main schema missing in the bundle")
+	}
+}
+
+func TestCreateEmptyJSONDocumentValidates(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	tests := []struct {
+		name       string
+		jsonSchema string
+		expected   string
+	}{
+		{
+			name: "Const, default, examples and enum",
+			jsonSchema: `{
+				"type": "object",
+				"properties": {
+					"version": { "const": 2 },
+					"level": { "type": "string", "enum": ["info", "warn"], "default": "warn" },
+					"color": { "type": "string", "enum": ["red", "green"] },
+					"name": { "type": "string", "examples": ["Jane"] },
+					"retries": { "type": "integer", "default": 3 }
+				},
+				"required": ["version", "level", "color", "name", "retries"]
+			}`,
+			expected: `{"version": 2, "level": "warn", "color": "red", "name": "Jane", "retries": 3}`,
+		},
+		{
+			name: "Default not satisfying the schema is skipped",
+			jsonSchema: `{
+				"type": "object",
+				"properties": {
+					"size": { "type": "string", "enum": ["S", "M"], "default": "XL" }
+				},
+				"required": ["size"]
+			}`,
+			expected: `{"size": "S"}`,
+		},
+		{
+			name: "Length, items and numeric bounds",
+			jsonSchema: `{
+				"type": "object",
+				"properties": {
+					"code": { "type": "string", "minLength": 3 },
+					"tags": { "type": "array", "items": { "type": "string", "minLength": 1 }, "minItems": 2 },
+					"empty": { "type": "array", "items": { "type": "string" }, "maxItems": 0 },
+					"port": { "type": "integer", "minimum": 1024, "maximum": 65535 },
+					"ratio": { "type": "number", "exclusiveMinimum": 0, "exclusiveMaximum": 1 },
+					"step": { "type": "integer", "minimum": 1, "multipleOf": 5 },
+					"below": { "type": "number", "maximum": -2.5 }
+				},
+				"required": ["code", "tags", "empty", "port", "ratio", "step", "below"]
+			}`,
+			expected: `{"code": "aaa", "tags": ["a", "a"], "empty": [], "port": 1024, "ratio": 0.5, "step": 5, "below": -2.5}`,
+		},
+		{
+			name: "Pattern",
+			jsonSchema: `{
+				"type": "object",
+				"properties": { "code": { "type": "string", "pattern": "^[A-Z]{3}$" } },
+				"required": ["code"]
+			}`,
+			expected: `{"code": "FRI"}`,
+		},
+		{
+			name: "Unique items",
+			jsonSchema: `{
+				"type": "object",
+				"properties": { "ids": { "type": "array", "items": { "type": "integer" }, "minItems": 2, "uniqueItems": true } },
+				"required": ["ids"]
+			}`,
+			expected: `{"ids": [0, 222]}`,
+		},
+		{
+			name: "Contains",
+			jsonSchema: `{
+				"type": "object",
+				"properties": {
+					"list": { "type": "array", "contains": { "const": "x" } },
+					"numbers": { "type": "array", "items": { "type": "integer" }, "contains": { "minimum": 5 }, "minContains": 2, "maxItems": 2 }
+				},
+				"required": ["list", "numbers"]
+			}`,
+			expected: `{"list": ["x"], "numbers": [676, 222]}`,
+		},
+		{
+			name:       "Min properties",
+			jsonSchema: `{ "type": "object", "minProperties": 1 }`,
+			expected:   `{"property1": null}`,
+		},
+		{
+			name: "Min properties with declared and additional properties",
+			jsonSchema: `{
+				"type": "object",
+				"properties": { "name": { "type": "string" } },
+				"additionalProperties": { "type": "integer", "minimum": 1 },
+				"minProperties": 3
+			}`,
+			expected: `{"name": "", "property1": 1, "property2": 1}`,
+		},
+		{
+			name: "Max properties",
+			jsonSchema: `{
+				"type": "object",
+				"properties": { "a": { "type": "object" }, "b": { "type": "object" } },
+				"maxProperties": 1
+			}`,
+			expected: `{"a": {}}`,
+		},
+		{
+			name: "Tuples and objects within arrays",
+			jsonSchema: `{
+				"type": "object",
+				"properties": {
+					"point": { "type": "array", "prefixItems": [{ "type": "number" }, { "const": "y" }], "minItems": 2 },
+					"people": {
+						"type": "array",
+						"items": { "type": "object", "properties": { "name": { "type": "string" } } },
+						"minItems": 1
+					}
+				},
+				"required": ["point", "people"]
+			}`,
+			expected: `{"point": [0, "y"], "people": [{}]}`,
+		},
+		{
+			name: "Default object is completed",
+			jsonSchema: `{
+				"type": "object",
+				"properties": {
+					"server": {
+						"type": "object",
+						"properties": { "host": { "type": "string" }, "port": { "type": "integer", "minimum": 1 } },
+						"required": ["port"],
+						"default": { "port": 8080 }
+					}
+				},
+				"required": ["server"]
+			}`,
+			expected: `{"server": {"port": 8080}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser, err := NewSchemaParser(logger, []byte(tt.jsonSchema))
+			if err != nil {
+				t.Fatalf("Failed to create schema parser: %v", err)
+			}
+			doc, err := parser.CreateEmptyJSONDocument()
+			if err != nil {
+				t.Fatalf("CreateEmptyJSONDocument() error = %v", err)
+			}
+			docJSON, err := json.Marshal(doc)
+			if err != nil {
+				t.Fatalf("Failed to marshal result: %v", err)
+			}
+
+			var normalizedDoc, normalizedExpected interface{}
+			_ = json.Unmarshal(docJSON, &normalizedDoc)
+			if err := json.Unmarshal([]byte(tt.expected), &normalizedExpected); err != nil {
+				t.Fatalf("Failed to unmarshal expected: %v", err)
+			}
+			if !reflect.DeepEqual(normalizedDoc, normalizedExpected) {
+				t.Errorf("CreateEmptyJSONDocument() = %s, want %s", docJSON, tt.expected)
+			}
+
+			validator, err := validate.NewJSONValidator(validate.WithJSONSchema([]byte(tt.jsonSchema)))
+			if err != nil {
+				t.Fatalf("Failed to create validator: %v", err)
+			}
+			if err := validator.ValidateDocument(docJSON); err != nil {
+				t.Errorf("generated document %s does not validate: %v", docJSON, err)
+			}
+		})
+	}
+}
+
+func TestCreateEmptyJSONDocumentSubschemas(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	tests := []struct {
+		name       string
+		jsonSchema string
+		choices    map[string]int
+		expected   string
+	}{
+		{
+			name: "References to $defs",
+			jsonSchema: `{
+				"type": "object",
+				"properties": {
+					"billing": { "$ref": "#/$defs/address" },
+					"code": { "$ref": "#/$defs/code" }
+				},
+				"required": ["billing", "code"],
+				"$defs": {
+					"address": { "type": "object", "properties": { "city": { "type": "string", "minLength": 2 } }, "required": ["city"] },
+					"code": { "type": "string", "minLength": 4 }
+				}
+			}`,
+			expected: `{"billing": {"city": "aa"}, "code": "aaaa"}`,
+		},
+		{
+			name: "Recursive reference",
+			jsonSchema: `{
+				"$ref": "#/$defs/node",
+				"$defs": {
+					"node": {
+						"type": "object",
+						"properties": { "name": { "type": "string" }, "children": { "type": "array", "items": { "$ref": "#/$defs/node" } } },
+						"required": ["name", "children"]
+					}
+				}
+			}`,
+			expected: `{"name": "", "children": []}`,
+		},
+		{
+			name: "Recursive array reference",
+			jsonSchema: `{
+				"$defs": { "list": { "type": "array", "items": { "$ref": "#/$defs/list" } } },
+				"type": "object",
+				"properties": { "x": { "$ref": "#/$defs/list" } },
+				"required": ["x"]
+			}`,
+			expected: `{"x": [[]]}`,
+		},
+		{
+			name: "First oneOf branch by default",
+			jsonSchema: `{
+				"type": "object",
+				"properties": { "card": { "type": "string" }, "iban": { "type": "string" } },
+				"oneOf": [{ "required": ["card"] }, { "required": ["iban"] }]
+			}`,
+			expected: `{"card": ""}`,
+		},
+		{
+			name: "Selected anyOf branch",
+			jsonSchema: `{
+				"type": "object",
+				"properties": {
+					"contact": { "anyOf": [{ "type": "string", "format": "email" }, { "type": "integer", "minimum": 1 }] }
+				},
+				"required": ["contact"]
+			}`,
+			choices:  map[string]int{"bundle:///schema.json#/properties/contact/anyOf": 1},
+			expected: `{"contact": 1}`,
+		},
+		{
+			name: "If, then and else",
+			jsonSchema: `{
+				"type": "object",
+				"properties": { "country": { "const": "DE" }, "zip": { "type": "string", "minLength": 5 }, "state": { "type": "string" } },
+				"required": ["country"],
+				"if": { "properties": { "country": { "const": "DE" } }, "required": ["country"] },
+				"then": { "required": ["zip"] },
+				"else": { "required": ["state"] }
+			}`,
+			expected: `{"country": "DE", "zip": "aaaaa"}`,
+		},
+		{
+			name: "Dependent required",
+			jsonSchema: `{
+				"type": "object",
+				"properties": { "card": { "type": "string" }, "cvc": { "type": "string", "minLength": 3 }, "name": { "type": "string" } },
+				"required": ["card"],
+				"dependentRequired": { "card": ["cvc"], "cvc": ["name"] }
+			}`,
+			expected: `{"card": "", "cvc": "aaa", "name": ""}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bundle := jsonedit.NewSchemaBundle()
+			bundle.Add("schema.json", []byte(tt.jsonSchema))
+			parser, err := NewSchemaParserFromBundle(logger, bundle)
+			if err != nil {
+				t.Fatalf("Failed to create schema parser: %v", err)
+			}
+			for location, branch := range tt.choices {
+				parser.Choose(location, branch)
+			}
+			doc, err := parser.CreateEmptyJSONDocument()
+			if err != nil {
+				t.Fatalf("CreateEmptyJSONDocument() error = %v", err)
+			}
+			docJSON, err := json.Marshal(doc)
+			if err != nil {
+				t.Fatalf("Failed to marshal result: %v", err)
+			}
+
+			var normalizedDoc, normalizedExpected interface{}
+			_ = json.Unmarshal(docJSON, &normalizedDoc)
+			if err := json.Unmarshal([]byte(tt.expected), &normalizedExpected); err != nil {
+				t.Fatalf("Failed to unmarshal expected: %v", err)
+			}
+			if !reflect.DeepEqual(normalizedDoc, normalizedExpected) {
+				t.Errorf("CreateEmptyJSONDocument() = %s, want %s", docJSON, tt.expected)
+			}
+
+			validator, err := validate.NewJSONValidator(validate.WithJSONSchema([]byte(tt.jsonSchema)))
+			if err != nil {
+				t.Fatalf("Failed to create validator: %v", err)
+			}
+			if err := validator.ValidateDocument(docJSON); err != nil {
+				t.Errorf("generated document %s does not validate: %v", docJSON, err)
+			}
+		})
+	}
+}
+
+func TestCreateEmptyJSONDocumentRecursiveMinItems(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	// No finite document satisfies the schema, generating stops at the recursion
+	parser, err := NewSchemaParser(logger, []byte(`{
+		"$defs": { "a": { "type": "array", "minItems": 1, "items": { "$ref": "#/$defs/a" } } },
+		"type": "object",
+		"properties": { "x": { "$ref": "#/$defs/a" } },
+		"required": ["x"]
+	}`))
+	if err != nil {
+		t.Fatalf("Failed to create schema parser: %v", err)
+	}
+	doc, err := parser.CreateEmptyJSONDocument()
+	if err != nil {
+		t.Fatalf("CreateEmptyJSONDocument() error = %v", err)
+	}
+	docJSON, _ := json.Marshal(doc)
+	if string(docJSON) != `{"x":[[]]}` {
+		t.Errorf("CreateEmptyJSONDocument() = %s, want {\"x\":[[]]}", docJSON)
+	}
+	if _, err := parser.GenerateDocuments(1, WithSeed(1)); err == nil {
+		t.Errorf("GenerateDocuments() expected error for an unsatisfiable schema")
+	}
+}
+
+func TestChoices(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	parser, err := NewSchemaParser(logger, []byte(`{
+		"type": "object",
+		"properties": {
+			"payment": { "oneOf": [{ "title": "Card", "type": "object" }, { "$ref": "#/$defs/transfer" }] },
+			"id": { "anyOf": [{ "type": "string" }, { "type": ["integer", "null"] }] }
+		},
+		"$defs": { "transfer": { "title": "Transfer", "type": "object" } }
+	}`))
+	if err != nil {
+		t.Fatalf("Failed to create schema parser: %v", err)
+	}
+
+	choices := parser.Choices()
+	if len(choices) != 2 {
+		t.Fatalf("Choices() = %+v, want 2 choices", choices)
+	}
+	parser.Choose(choices[1].Location, 1)
+
+	choices = parser.Choices()
+	if choices[0].Keyword != "anyOf" || !strings.HasSuffix(choices[0].Location, "#/properties/id/anyOf") || choices[0].Selected != 0 {
+		t.Errorf("Choices()[0] = %+v, want anyOf of id", choices[0])
+	}
+	if !reflect.DeepEqual(choices[0].Branches, []string{"1: string", "2: null, integer"}) {
+		t.Errorf("Choices()[0].Branches = %v", choices[0].Branches)
+	}
+	if choices[1].Keyword != "oneOf" || choices[1].Selected != 1 || !reflect.DeepEqual(choices[1].Branches, []string{"1: Card", "2: Transfer"}) {
+		t.Errorf("Choices()[1] = %+v, want oneOf of payment with the second branch selected", choices[1])
+	}
+}
@@ -0,0 +1,80 @@
+package fromschema
+
+import (
+	"math/rand/v2"
+	"regexp/syntax"
+	"strings"
+)
+
+// maxRepeat is the number of repetitions added to the minimum of unbounded quantifiers like * and +
+const maxRepeat = 3
+
+// patternString returns a random string matching the regular expression. Character classes prefer
+// printable ASCII characters, unbounded quantifiers repeat at most maxRepeat times more than required.
+func patternString(random *rand.Rand, pattern string) (string, error) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", err
+	}
+	var builder strings.Builder
+	writePattern(random, &builder, re.Simplify())
+	return builder.String(), nil
+}
+
+// writePattern writes a random string matching the parsed regular expression
+func writePattern(random *rand.Rand, builder *strings.Builder, re *syntax.Regexp) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		builder.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		builder.WriteRune(classRune(random, re.Rune))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		builder.WriteRune(rune('a' + random.IntN(26)))
+	case syntax.OpCapture:
+		writePattern(random, builder, re.Sub[0])
+	case syntax.OpStar:
+		writeRepeat(random, builder, re.Sub[0], 0, maxRepeat)
+	case syntax.OpPlus:
+		writeRepeat(random, builder, re.Sub[0], 1, 1+maxRepeat)
+	case syntax.OpQuest:
+		writeRepeat(random, builder, re.Sub[0], 0, 1)
+	case syntax.OpRepeat:
+		maximum := re.Max
+		if maximum < 0 {
+			maximum = re.Min + maxRepeat
+		}
+		writeRepeat(random, builder, re.Sub[0], re.Min, maximum)
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			writePattern(random, builder, sub)
+		}
+	case syntax.OpAlternate:
+		writePattern(random, builder, re.Sub[random.IntN(len(re.Sub))])
+	}
+	// Anchors, word boundaries and empty matches do not add characters
+}
+
+// writeRepeat writes the expression between minimum and maximum times
+func writeRepeat(random *rand.Rand, builder *strings.Builder, re *syntax.Regexp, minimum, maximum int) {
+	count := minimum + random.IntN(maximum-minimum+1)
+	for i := 0; i < count; i++ {
+		writePattern(random, builder, re)
+	}
+}
+
+// classRune returns a random rune of the character class given as pairs of inclusive ranges
+func classRune(random *rand.Rand, ranges []rune) rune {
+	printable := make([]rune, 0)
+	for i := 0; i+1 < len(ranges); i += 2 {
+		for r := max(ranges[i], ' '); r <= min(ranges[i+1], '~'); r++ {
+			printable = append(printable, r)
+		}
+	}
+	if len(printable) > 0 {
+		return printable[random.IntN(len(printable))]
+	}
+	if len(ranges) < 2 {
+		return 'a'
+	}
+	return ranges[0] + rune(random.IntN(int(ranges[1]-ranges[0])+1))
+}
@@ -0,0 +1,318 @@
+package fromschema
+
+import (
+	stdJson "encoding/json"
+	"fmt"
+	"math/big"
+	"math/rand/v2"
+	"net"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// maxAttempts is the number of documents generated until one satisfies the schema
+const maxAttempts = 20
+
+// words are used to build strings without pattern or format
+var words = []string{
+	"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit", "sed", "do",
+	"eiusmod", "tempor", "incididunt", "ut", "labore", "et", "dolore", "magna", "aliqua", "enim",
+}
+
+// formats generate random strings of a format
+var formats = map[string]func(random *rand.Rand) string{
+	"date-time": func(random *rand.Rand) string { return randomTime(random).Format(time.RFC3339) },
+	"date":      func(random *rand.Rand) string { return randomTime(random).Format(time.DateOnly) },
+	"time":      func(random *rand.Rand) string { return randomTime(random).Format("15:04:05Z") },
+	"email": func(random *rand.Rand) string {
+		return words[random.IntN(len(words))] + "." + words[random.IntN(len(words))] + "@example.com"
+	},
+	"idn-email": func(random *rand.Rand) string { return words[random.IntN(len(words))] + "@example.com" },
+	"hostname":  func(random *rand.Rand) string { return words[random.IntN(len(words))] + ".example.com" },
+	"idn-hostname": func(random *rand.Rand) string {
+		return words[random.IntN(len(words))] + ".example.com"
+	},
+	"uri": func(random *rand.Rand) string {
+		return "https://example.com/" + words[random.IntN(len(words))]
+	},
+	"uri-reference": func(random *rand.Rand) string { return "/" + words[random.IntN(len(words))] },
+	"iri":           func(random *rand.Rand) string { return "https://example.com/" + words[random.IntN(len(words))] },
+	"ipv4": func(random *rand.Rand) string {
+		return fmt.Sprintf("%d.%d.%d.%d", 1+random.IntN(254), random.IntN(256), random.IntN(256), 1+random.IntN(254))
+	},
+	"ipv6": func(random *rand.Rand) string {
+		ip := make(net.IP, net.IPv6len)
+		for i := range ip {
+			ip[i] = byte(random.IntN(256))
+		}
+		return ip.String()
+	},
+	"uuid": func(random *rand.Rand) string {
+		b := make([]byte, 16)
+		for i := range b {
+			b[i] = byte(random.IntN(256))
+		}
+		b[6] = b[6]&0x0f | 0x40
+		b[8] = b[8]&0x3f | 0x80
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+	},
+	"duration": func(random *rand.Rand) string { return fmt.Sprintf("P%dDT%dH", random.IntN(30), random.IntN(24)) },
+}
+
+type (
+	// generateConfig holds the configuration of GenerateDocuments
+	generateConfig struct {
+
+		// seed initializes the random numbers, the same seed generates the same documents
+		seed uint64
+
+		// optional indicates whether properties that are not required are added
+		optional bool
+	}
+
+	// GenerateOption configures GenerateDocuments
+	GenerateOption func(*generateConfig)
+)
+
+// WithSeed sets the seed of the random numbers, the same seed and schema generate the same documents.
+// Without a seed the current time is used.
+func WithSeed(seed uint64) GenerateOption {
+	return func(c *generateConfig) {
+		c.seed = seed
+	}
+}
+
+// WithOptionalProperties sets whether properties that are not required are added, disabled by default
+func WithOptionalProperties(optional bool) GenerateOption {
+	return func(c *generateConfig) {
+		c.optional = optional
+	}
+}
+
+// GenerateDocuments generates count random documents satisfying the schema. Strings match pattern or
+// format, numbers are within their bounds and arrays within minItems and maxItems. Values of const,
+// enum, examples and default are picked randomly. A document not satisfying the schema is generated
+// again, an error is returned if no valid document is found after several attempts.
+func (sp *SchemaParser) GenerateDocuments(count int, options ...GenerateOption) ([]interface{}, error) {
+	config := &generateConfig{seed: uint64(time.Now().UnixNano())}
+	for _, option := range options {
+		option(config)
+	}
+
+	sp.random = rand.New(rand.NewPCG(config.seed, config.seed))
+	sp.optional = config.optional
+	defer func() {
+		sp.random = nil
+		sp.optional = false
+	}()
+
+	documents := make([]interface{}, 0, count)
+	for len(documents) < count {
+		var err error
+		for attempt := 0; attempt < maxAttempts; attempt++ {
+			document := sp.document()
+			if err = sp.schema.Validate(document); err == nil {
+				documents = append(documents, document)
+				break
+			}
+			sp.logger.Debug("Generated document does not satisfy the schema", "attempt", attempt, "err", err)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate document %d satisfying the schema: %w", len(documents)+1, err)
+		}
+	}
+	return documents, nil
+}
+
+// randomArrayLength returns a random number of elements within minItems and maxItems, at least one
+// if allowed and at most maxRepeat more than required
+func (sp *SchemaParser) randomArrayLength(fieldSchema *jsonschema.Schema) int {
+	minimum := 1
+	if fieldSchema.MinItems != nil {
+		minimum = max(*fieldSchema.MinItems, 1)
+	}
+	maximum := minimum + maxRepeat - 1
+	if fieldSchema.MaxItems != nil {
+		maximum = min(maximum, *fieldSchema.MaxItems)
+		minimum = min(minimum, maximum)
+	}
+	return minimum + sp.random.IntN(maximum-minimum+1)
+}
+
+// randomItem returns a random element for the array, elements of arrays with uniqueItems are
+// generated again while they equal an existing element
+func (sp *SchemaParser) randomItem(fieldSchema, itemSchema *jsonschema.Schema, items []interface{}, fieldName string) (interface{}, bool) {
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		item := sp.emptyValue(itemSchema, fieldName)
+		if !fieldSchema.UniqueItems || !slicesContainsEqual(items, item) {
+			return item, true
+		}
+	}
+	return nil, false
+}
+
+// slicesContainsEqual reports whether the items contain a value deeply equal to value
+func slicesContainsEqual(items []interface{}, value interface{}) bool {
+	for _, item := range items {
+		if reflect.DeepEqual(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// randomString returns a random string matching pattern or format and within minLength and maxLength
+func (sp *SchemaParser) randomString(fieldSchema *jsonschema.Schema) string {
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		var value string
+		if fieldSchema.Pattern != nil {
+			generated, err := patternString(sp.random, fieldSchema.Pattern.String())
+			if err != nil {
+				sp.logger.Warn("Pattern is not supported", "pattern", fieldSchema.Pattern.String(), "err", err)
+				break
+			}
+			value = generated
+		} else if format, ok := sp.format(fieldSchema); ok {
+			value = format(sp.random)
+		} else {
+			value = sp.randomWords(fieldSchema)
+		}
+		if stringSatisfies(fieldSchema, value) {
+			return value
+		}
+	}
+	return sp.emptyString(fieldSchema)
+}
+
+// format returns the generator of the format of the string, false if there is none
+func (sp *SchemaParser) format(fieldSchema *jsonschema.Schema) (func(*rand.Rand) string, bool) {
+	if fieldSchema.Format == nil {
+		return nil, false
+	}
+	format, ok := formats[fieldSchema.Format.Name]
+	if !ok {
+		sp.logger.Debug("Format is not supported", "format", fieldSchema.Format.Name)
+	}
+	return format, ok
+}
+
+// randomWords returns random words within minLength and maxLength
+func (sp *SchemaParser) randomWords(fieldSchema *jsonschema.Schema) string {
+	minimum, maximum := 1, 30
+	if fieldSchema.MinLength != nil {
+		minimum = max(*fieldSchema.MinLength, 1)
+	}
+	if fieldSchema.MaxLength != nil {
+		maximum = *fieldSchema.MaxLength
+	}
+	maximum = max(maximum, minimum)
+	length := minimum + sp.random.IntN(min(maximum, minimum+20)-minimum+1)
+
+	var builder strings.Builder
+	for builder.Len() < length {
+		if builder.Len() > 0 {
+			builder.WriteString(" ")
+		}
+		builder.WriteString(words[sp.random.IntN(len(words))])
+	}
+	return strings.TrimSpace(builder.String()[:length])
+}
+
+// stringSatisfies reports whether the string is within minLength and maxLength and matches pattern
+func stringSatisfies(schema *jsonschema.Schema, value string) bool {
+	length := len([]rune(value))
+	if schema.MinLength != nil && length < *schema.MinLength {
+		return false
+	}
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		return false
+	}
+	return schema.Pattern == nil || schema.Pattern.MatchString(value)
+}
+
+// randomNumber returns a random number within minimum and maximum and a multiple of multipleOf.
+// Missing bounds default to 0 and 1000 or a range of 1000, non-integers have two decimals. The
+// number is computed exactly, so bounds beyond the precision of float64 are kept.
+func (sp *SchemaParser) randomNumber(fieldSchema *jsonschema.Schema, integer bool) stdJson.Number {
+	lower, upper := big.NewRat(0, 1), big.NewRat(1000, 1)
+	lowerRat, upperRat := lowerBound(fieldSchema), upperBound(fieldSchema)
+	switch {
+	case lowerRat != nil && upperRat != nil:
+		lower, upper = lowerRat, upperRat
+	case lowerRat != nil:
+		lower, upper = lowerRat, new(big.Rat).Add(lowerRat, big.NewRat(1000, 1))
+	case upperRat != nil:
+		upper = upperRat
+		if upper.Sign() <= 0 {
+			lower = new(big.Rat).Sub(upperRat, big.NewRat(1000, 1))
+		}
+	}
+
+	step := big.NewRat(1, 100)
+	switch {
+	case fieldSchema.MultipleOf != nil && fieldSchema.MultipleOf.Sign() > 0:
+		step = fieldSchema.MultipleOf
+	case integer:
+		step = big.NewRat(1, 1)
+	}
+	minimum := ceil(new(big.Rat).Quo(lower, step))
+	maximum := floor(new(big.Rat).Quo(upper, step))
+	value := new(big.Rat).Mul(new(big.Rat).SetInt(sp.randomInt(minimum.Num(), maximum.Num())), step)
+
+	if !numberSatisfies(fieldSchema, value) || (integer && !value.IsInt()) {
+		return sp.emptyNumber(fieldSchema, integer)
+	}
+	return numberLiteral(value)
+}
+
+// randomInt returns a random integer between lower and upper, lower if upper is not greater
+func (sp *SchemaParser) randomInt(lower, upper *big.Int) *big.Int {
+	if upper.Cmp(lower) <= 0 {
+		return new(big.Int).Set(lower)
+	}
+	span := new(big.Int).Sub(upper, lower)
+	span.Add(span, big.NewInt(1))
+	if span.IsInt64() {
+		return new(big.Int).Add(lower, big.NewInt(sp.random.Int64N(span.Int64())))
+	}
+
+	// Draw random bits until the number is within the span, which succeeds at least every second time
+	words := (span.BitLen() + 63) / 64
+	for {
+		value := new(big.Int)
+		for i := 0; i < words; i++ {
+			value.Lsh(value, 64).Or(value, new(big.Int).SetUint64(sp.random.Uint64()))
+		}
+		value.Rsh(value, uint(words*64-span.BitLen()))
+		if value.Cmp(span) < 0 {
+			return value.Add(value, lower)
+		}
+	}
+}
+
+// lowerBound returns the greater of minimum and exclusiveMinimum, nil if there is neither
+func lowerBound(schema *jsonschema.Schema) *big.Rat {
+	bound := schema.Minimum
+	if schema.ExclusiveMinimum != nil && (bound == nil || schema.ExclusiveMinimum.Cmp(bound) > 0) {
+		bound = schema.ExclusiveMinimum
+	}
+	return bound
+}
+
+// upperBound returns the lower of maximum and exclusiveMaximum, nil if there is neither
+func upperBound(schema *jsonschema.Schema) *big.Rat {
+	bound := schema.Maximum
+	if schema.ExclusiveMaximum != nil && (bound == nil || schema.ExclusiveMaximum.Cmp(bound) < 0) {
+		bound = schema.ExclusiveMaximum
+	}
+	return bound
+}
+
+// randomTime returns a random time in the years 2000 to 2029, truncated to seconds
+func randomTime(random *rand.Rand) time.Time {
+	start := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	return start.Add(time.Duration(random.Int64N(30*365*24*3600)) * time.Second)
+}
@@ -0,0 +1,156 @@
+package fromschema
+
+import (
+	"encoding/json"
+	"log/slog"
+	"math/rand/v2"
+	"os"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/sascha-andres/jsonedit/json/validate"
+)
+
+const randomSchema = `{
+	"type": "object",
+	"properties": {
+		"id": { "type": "string", "format": "uuid" },
+		"email": { "type": "string", "format": "email" },
+		"created": { "type": "string", "format": "date-time" },
+		"sku": { "type": "string", "pattern": "^[A-Z]{3}-[0-9]{4}$" },
+		"name": { "type": "string", "minLength": 3, "maxLength": 12 },
+		"quantity": { "type": "integer", "minimum": 1, "maximum": 9 },
+		"price": { "type": "number", "exclusiveMinimum": 0, "maximum": 100 },
+		"step": { "type": "integer", "multipleOf": 25, "maximum": 100 },
+		"status": { "enum": ["new", "paid", "shipped"] },
+		"active": { "type": "boolean" },
+		"tags": { "type": "array", "items": { "type": "string", "pattern": "^[a-z]+$" }, "minItems": 2, "maxItems": 4, "uniqueItems": true },
+		"note": { "type": "string" }
+	},
+	"required": ["id", "email", "created", "sku", "name", "quantity", "price", "step", "status", "active", "tags"]
+}`
+
+func TestGenerateDocuments(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	parser, err := NewSchemaParser(logger, []byte(randomSchema))
+	if err != nil {
+		t.Fatalf("Failed to create schema parser: %v", err)
+	}
+	validator, err := validate.NewJSONValidator(validate.WithJSONSchema([]byte(randomSchema)), validate.WithFormatAssertion(true))
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+
+	documents, err := parser.GenerateDocuments(25, WithSeed(42))
+	if err != nil {
+		t.Fatalf("GenerateDocuments() error = %v", err)
+	}
+	if len(documents) != 25 {
+		t.Fatalf("GenerateDocuments() = %d documents, want 25", len(documents))
+	}
+	for _, document := range documents {
+		content, _ := json.Marshal(document)
+		if err := validator.ValidateDocument(content); err != nil {
+			t.Errorf("generated document %s does not validate: %v", content, err)
+		}
+		if _, ok := document.(map[string]interface{})["note"]; ok {
+			t.Errorf("generated document %s contains optional property", content)
+		}
+	}
+	if reflect.DeepEqual(documents[0], documents[1]) {
+		t.Errorf("GenerateDocuments() generated equal documents %v", documents[0])
+	}
+
+	again, err := parser.GenerateDocuments(25, WithSeed(42))
+	if err != nil {
+		t.Fatalf("GenerateDocuments() error = %v", err)
+	}
+	if !reflect.DeepEqual(documents, again) {
+		t.Errorf("GenerateDocuments() with the same seed generated different documents")
+	}
+
+	withOptional, err := parser.GenerateDocuments(1, WithSeed(7), WithOptionalProperties(true))
+	if err != nil {
+		t.Fatalf("GenerateDocuments() error = %v", err)
+	}
+	if _, ok := withOptional[0].(map[string]interface{})["note"]; !ok {
+		t.Errorf("GenerateDocuments() = %v, want optional property note", withOptional[0])
+	}
+
+	// Generating random documents does not change empty documents
+	doc, _ := parser.CreateEmptyJSONDocument()
+	if doc.(map[string]interface{})["active"] != false {
+		t.Errorf("CreateEmptyJSONDocument() = %v after generating random documents", doc)
+	}
+}
+
+func TestGenerateDocumentsExactNumbers(t *testing.T) {
+	schema := `{
+		"type": "object",
+		"properties": {
+			"id": { "type": "integer", "minimum": 12345678901234567890 },
+			"range": { "type": "integer", "minimum": -98765432109876543210, "maximum": 98765432109876543210 },
+			"price": { "type": "number", "minimum": 9007199254740993.5, "exclusiveMaximum": 9007199254740995 },
+			"step": { "type": "number", "multipleOf": 0.1, "minimum": 0.3, "maximum": 0.7 },
+			"tags": { "type": "array", "items": { "type": "string" }, "contains": { "const": "x" }, "maxItems": 2 },
+			"extra": { "type": "object", "additionalProperties": { "type": "boolean" }, "minProperties": 2 }
+		},
+		"required": ["id", "range", "price", "step", "tags", "extra"]
+	}`
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	parser, err := NewSchemaParser(logger, []byte(schema))
+	if err != nil {
+		t.Fatalf("Failed to create schema parser: %v", err)
+	}
+	validator, err := validate.NewJSONValidator(validate.WithJSONSchema([]byte(schema)))
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+
+	documents, err := parser.GenerateDocuments(25, WithSeed(3))
+	if err != nil {
+		t.Fatalf("GenerateDocuments() error = %v", err)
+	}
+	empty, err := parser.CreateEmptyJSONDocument()
+	if err != nil {
+		t.Fatalf("CreateEmptyJSONDocument() error = %v", err)
+	}
+	for _, document := range append(documents, empty) {
+		content, _ := json.Marshal(document)
+		if err := validator.ValidateDocument(content); err != nil {
+			t.Errorf("generated document %s does not validate: %v", content, err)
+		}
+	}
+}
+
+func TestGenerateDocumentsUnsatisfiable(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	parser, err := NewSchemaParser(logger, []byte(`{"type": "object", "properties": {"a": {"type": "string"}}, "required": ["a"], "not": {"required": ["a"]}}`))
+	if err != nil {
+		t.Fatalf("Failed to create schema parser: %v", err)
+	}
+	if _, err := parser.GenerateDocuments(1, WithSeed(1)); err == nil {
+		t.Errorf("GenerateDocuments() expected error for an unsatisfiable schema")
+	}
+}
+
+func TestPatternString(t *testing.T) {
+	random := rand.New(rand.NewPCG(1, 1))
+	patterns := []string{`^[A-Z]{2,4}-\d+$`, `^(foo|bar)?baz[^"]*$`, `\w+@\w+\.com`, `^.{5}$`}
+	for _, pattern := range patterns {
+		re := regexp.MustCompile(pattern)
+		for i := 0; i < 20; i++ {
+			value, err := patternString(random, pattern)
+			if err != nil {
+				t.Fatalf("patternString(%q) error = %v", pattern, err)
+			}
+			if !re.MatchString(value) {
+				t.Errorf("patternString(%q) = %q does not match", pattern, value)
+			}
+		}
+	}
+	if _, err := patternString(random, `(`); err == nil {
+		t.Errorf("patternString() expected error for invalid pattern")
+	}
+}
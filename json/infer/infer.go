@@ -0,0 +1,235 @@
+package infer
+
+import (
+	"errors"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sascha-andres/jsonedit/json"
+)
+
+// SchemaDraft is the meta schema of the inferred schemas
+const SchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// uuidPattern matches UUIDs in their canonical textual representation
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// formats lists the detected string formats, the first one all values match is used
+var formats = []struct {
+	name  string
+	match func(string) bool
+}{
+	{"date-time", func(s string) bool { _, err := time.Parse(time.RFC3339, s); return err == nil }},
+	{"date", func(s string) bool { _, err := time.Parse(time.DateOnly, s); return err == nil }},
+	{"uuid", uuidPattern.MatchString},
+	{"email", func(s string) bool {
+		address, err := mail.ParseAddress(s)
+		return err == nil && address.Address == s && address.Name == ""
+	}},
+}
+
+type (
+	// shape accumulates all values found at one location of the samples
+	shape struct {
+
+		// kinds counts the values of every JSON type
+		kinds map[json.Kind]int
+
+		// fractions counts the numbers that are not integers
+		fractions int
+
+		// keys holds the property names of objects in the order they were first seen
+		keys []string
+
+		// properties holds the shape of every property of objects
+		properties map[string]*shape
+
+		// presence counts the objects containing a property
+		presence map[string]int
+
+		// items is the shape of all array elements, nil if no array had elements
+		items *shape
+
+		// values counts the occurrences of every string
+		values map[string]int
+
+		// order holds the strings in the order they were first seen
+		order []string
+	}
+
+	// inferrer holds the configuration of an inference
+	inferrer struct {
+
+		// enumLimit is the maximum number of distinct strings turned into an enum, 0 disables enums
+		enumLimit int
+
+		// detectFormats indicates whether formats of strings are detected
+		detectFormats bool
+	}
+
+	// Option configures an inference
+	Option func(*inferrer)
+)
+
+// WithEnumLimit sets the maximum number of distinct values of a string turned into an enum, 0 disables
+// enum detection. Strings become an enum only if at least one value occurs more than once, the default is 5.
+func WithEnumLimit(limit int) Option {
+	return func(i *inferrer) {
+		i.enumLimit = limit
+	}
+}
+
+// WithFormats sets whether the formats date-time, date, uuid and email are detected, enabled by default
+func WithFormats(detect bool) Option {
+	return func(i *inferrer) {
+		i.detectFormats = detect
+	}
+}
+
+// Infer returns a draft 2020-12 schema all samples satisfy. Properties present in every sample are
+// required, array elements share one item schema, strings of a detected format get the format
+// keyword and other low-cardinality strings become enums.
+func Infer(samples []*json.Node, options ...Option) (*json.Node, error) {
+	if len(samples) == 0 {
+		return nil, errors.New("no sample documents")
+	}
+	i := &inferrer{enumLimit: 5, detectFormats: true}
+	for _, option := range options {
+		option(i)
+	}
+
+	root := newShape()
+	for _, sample := range samples {
+		root.add(sample)
+	}
+
+	schema := i.schema(root)
+	schema.Members = append([]json.Member{{Key: "$schema", Value: json.NewString(SchemaDraft)}}, schema.Members...)
+	return schema, nil
+}
+
+// newShape returns a shape without values
+func newShape() *shape {
+	return &shape{
+		kinds:      make(map[json.Kind]int),
+		properties: make(map[string]*shape),
+		presence:   make(map[string]int),
+		values:     make(map[string]int),
+	}
+}
+
+// add merges the value into the shape
+func (s *shape) add(value *json.Node) {
+	s.kinds[value.Kind]++
+	switch value.Kind {
+	case json.NumberKind:
+		if strings.ContainsAny(string(value.Number), ".eE") {
+			s.fractions++
+		}
+	case json.StringKind:
+		if _, seen := s.values[value.Text]; !seen {
+			s.order = append(s.order, value.Text)
+		}
+		s.values[value.Text]++
+	case json.ObjectKind:
+		for _, member := range value.Members {
+			property, ok := s.properties[member.Key]
+			if !ok {
+				property = newShape()
+				s.properties[member.Key] = property
+				s.keys = append(s.keys, member.Key)
+			}
+			s.presence[member.Key]++
+			property.add(member.Value)
+		}
+	case json.ArrayKind:
+		for _, item := range value.Items {
+			if s.items == nil {
+				s.items = newShape()
+			}
+			s.items.add(item)
+		}
+	}
+}
+
+// schema returns the schema describing all values of the shape
+func (i *inferrer) schema(s *shape) *json.Node {
+	schema := json.NewObject()
+
+	types := make([]*json.Node, 0)
+	for _, kind := range []json.Kind{json.NullKind, json.BoolKind, json.NumberKind, json.StringKind, json.ArrayKind, json.ObjectKind} {
+		if s.kinds[kind] == 0 {
+			continue
+		}
+		name := kind.String()
+		if kind == json.NumberKind && s.fractions == 0 {
+			name = "integer"
+		}
+		types = append(types, json.NewString(name))
+	}
+	switch len(types) {
+	case 0:
+		// No values, e.g. the items of arrays that were always empty, allow anything
+		return schema
+	case 1:
+		schema.Set("type", types[0])
+	default:
+		schema.Set("type", json.NewArray(types...))
+	}
+
+	if objects := s.kinds[json.ObjectKind]; objects > 0 {
+		properties := json.NewObject()
+		required := make([]*json.Node, 0)
+		for _, key := range s.keys {
+			properties.Set(key, i.schema(s.properties[key]))
+			if s.presence[key] == objects {
+				required = append(required, json.NewString(key))
+			}
+		}
+		schema.Set("properties", properties)
+		if len(required) > 0 {
+			schema.Set("required", json.NewArray(required...))
+		}
+	}
+
+	if s.kinds[json.ArrayKind] > 0 && s.items != nil {
+		schema.Set("items", i.schema(s.items))
+	}
+
+	// Strings of a known format are not turned into an enum, e.g. repeated dates
+	if count := s.kinds[json.StringKind]; count > 0 {
+		if format := i.format(s); format != "" {
+			schema.Set("format", json.NewString(format))
+		} else if len(types) == 1 && i.enumLimit > 0 && len(s.order) <= i.enumLimit && len(s.order) < count {
+			values := make([]*json.Node, len(s.order))
+			for n, value := range s.order {
+				values[n] = json.NewString(value)
+			}
+			schema.Set("enum", json.NewArray(values...))
+		}
+	}
+
+	return schema
+}
+
+// format returns the format all strings of the shape match, empty if there is none
+func (i *inferrer) format(s *shape) string {
+	if !i.detectFormats {
+		return ""
+	}
+	for _, format := range formats {
+		matches := true
+		for _, value := range s.order {
+			if !format.match(value) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return format.name
+		}
+	}
+	return ""
+}
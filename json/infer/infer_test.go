@@ -0,0 +1,109 @@
+package infer
+
+import (
+	"testing"
+
+	"github.com/sascha-andres/jsonedit/json"
+)
+
+// mustParse parses doc or fails the test
+func mustParse(t *testing.T, doc string) *json.Node {
+	t.Helper()
+	n, err := json.Parse([]byte(doc))
+	if err != nil {
+		t.Fatalf("Parse(%s) error = %v", doc, err)
+	}
+	return n
+}
+
+func TestInfer(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples []string
+		options []Option
+		want    string
+	}{
+		{
+			name:    "Scalar types",
+			samples: []string{`{"s": "x", "i": 1, "n": 1.5, "b": true, "z": null}`},
+			want:    `{"$schema":"https://json-schema.org/draft/2020-12/schema","type":"object","properties":{"s":{"type":"string"},"i":{"type":"integer"},"n":{"type":"number"},"b":{"type":"boolean"},"z":{"type":"null"}},"required":["s","i","n","b","z"]}`,
+		},
+		{
+			name:    "Required only if present in all samples",
+			samples: []string{`{"a": 1, "b": 2}`, `{"a": 3, "c": 4}`},
+			want:    `{"$schema":"https://json-schema.org/draft/2020-12/schema","type":"object","properties":{"a":{"type":"integer"},"b":{"type":"integer"},"c":{"type":"integer"}},"required":["a"]}`,
+		},
+		{
+			name:    "Mixed types",
+			samples: []string{`{"a": 1}`, `{"a": "x"}`, `{"a": null}`, `{"a": 2.5}`},
+			want:    `{"$schema":"https://json-schema.org/draft/2020-12/schema","type":"object","properties":{"a":{"type":["null","number","string"]}},"required":["a"]}`,
+		},
+		{
+			name:    "Array items",
+			samples: []string{`[{"id": 1}, {"id": 2, "tag": "x"}]`, `[]`},
+			want:    `{"$schema":"https://json-schema.org/draft/2020-12/schema","type":"array","items":{"type":"object","properties":{"id":{"type":"integer"},"tag":{"type":"string"}},"required":["id"]}}`,
+		},
+		{
+			name:    "Empty arrays allow any item",
+			samples: []string{`{"a": []}`},
+			want:    `{"$schema":"https://json-schema.org/draft/2020-12/schema","type":"object","properties":{"a":{"type":"array"}},"required":["a"]}`,
+		},
+		{
+			name:    "Enum",
+			samples: []string{`{"role": "admin"}`, `{"role": "user"}`, `{"role": "admin"}`},
+			want:    `{"$schema":"https://json-schema.org/draft/2020-12/schema","type":"object","properties":{"role":{"type":"string","enum":["admin","user"]}},"required":["role"]}`,
+		},
+		{
+			name:    "No enum for distinct values",
+			samples: []string{`{"name": "a"}`, `{"name": "b"}`},
+			want:    `{"$schema":"https://json-schema.org/draft/2020-12/schema","type":"object","properties":{"name":{"type":"string"}},"required":["name"]}`,
+		},
+		{
+			name:    "Enum limit",
+			samples: []string{`["a", "b", "a"]`},
+			options: []Option{WithEnumLimit(1)},
+			want:    `{"$schema":"https://json-schema.org/draft/2020-12/schema","type":"array","items":{"type":"string"}}`,
+		},
+		{
+			name:    "Formats",
+			samples: []string{`{"at": "2024-01-02T03:04:05Z", "on": "2024-01-02", "id": "123e4567-e89b-12d3-a456-426614174000", "mail": "a@example.com"}`, `{"at": "2024-01-02T03:04:05+01:00", "on": "2024-01-02", "id": "123E4567-E89B-12D3-A456-426614174000", "mail": "b@example.org"}`},
+			want:    `{"$schema":"https://json-schema.org/draft/2020-12/schema","type":"object","properties":{"at":{"type":"string","format":"date-time"},"on":{"type":"string","format":"date"},"id":{"type":"string","format":"uuid"},"mail":{"type":"string","format":"email"}},"required":["at","on","id","mail"]}`,
+		},
+		{
+			name:    "Format detection disabled",
+			samples: []string{`{"on": "2024-01-02"}`},
+			options: []Option{WithFormats(false)},
+			want:    `{"$schema":"https://json-schema.org/draft/2020-12/schema","type":"object","properties":{"on":{"type":"string"}},"required":["on"]}`,
+		},
+		{
+			name:    "No format if one value does not match",
+			samples: []string{`["2024-01-02", "tomorrow"]`},
+			want:    `{"$schema":"https://json-schema.org/draft/2020-12/schema","type":"array","items":{"type":"string"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			samples := make([]*json.Node, len(tt.samples))
+			for i, sample := range tt.samples {
+				samples[i] = mustParse(t, sample)
+			}
+			schema, err := Infer(samples, tt.options...)
+			if err != nil {
+				t.Fatalf("Infer() error = %v", err)
+			}
+			got, err := schema.Format("", "")
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Infer() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInferWithoutSamples(t *testing.T) {
+	if _, err := Infer(nil); err == nil {
+		t.Errorf("Infer() expected error without samples")
+	}
+}
@@ -0,0 +1,71 @@
+package json
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// Line is a single line of a formatted document
+type Line struct {
+
+	// Path is the JSON Pointer of the value the line belongs to, closing brackets belong to their container
+	Path string
+
+	// Text is the content of the line including its indentation
+	Text string
+}
+
+// FormatLines formats the node like Format with an empty prefix and returns the lines of the
+// result together with the path of the value each line belongs to. An empty indent is replaced
+// by two spaces as every element starts on a new line.
+func (n *Node) FormatLines(indent string) ([]Line, error) {
+	if indent == "" {
+		indent = "  "
+	}
+	lines := make([]Line, 0)
+	err := n.formatLines(&lines, "", "", indent, 0, false)
+	return lines, err
+}
+
+// formatLines appends the lines of the node to lines. label is written in front of the value,
+// comma indicates whether the value is followed by another element.
+func (n *Node) formatLines(lines *[]Line, path, label, indent string, depth int, comma bool) error {
+	padding := strings.Repeat(indent, depth)
+	suffix := ""
+	if comma {
+		suffix = ","
+	}
+
+	if n != nil && (n.Kind == ArrayKind && len(n.Items) > 0 || n.Kind == ObjectKind && len(n.Members) > 0) {
+		open, closing := "[", "]"
+		if n.Kind == ObjectKind {
+			open, closing = "{", "}"
+		}
+		*lines = append(*lines, Line{Path: path, Text: padding + label + open})
+		for i, item := range n.Items {
+			if err := item.formatLines(lines, AppendPointer(path, strconv.Itoa(i)), "", indent, depth+1, i < len(n.Items)-1); err != nil {
+				return err
+			}
+		}
+		for i, member := range n.Members {
+			var key bytes.Buffer
+			if err := writeString(&key, member.Key); err != nil {
+				return err
+			}
+			key.WriteString(": ")
+			if err := member.Value.formatLines(lines, AppendPointer(path, member.Key), key.String(), indent, depth+1, i < len(n.Members)-1); err != nil {
+				return err
+			}
+		}
+		*lines = append(*lines, Line{Path: path, Text: padding + closing + suffix})
+		return nil
+	}
+
+	value, err := n.Format("", "")
+	if err != nil {
+		return err
+	}
+	*lines = append(*lines, Line{Path: path, Text: padding + label + string(value) + suffix})
+	return nil
+}
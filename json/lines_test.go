@@ -0,0 +1,33 @@
+package json
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFormatLines(t *testing.T) {
+	n, err := Parse([]byte(`{"b": [1, {"c/d": null}], "a": {}, "e": "x"}`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	lines, err := n.FormatLines("  ")
+	if err != nil {
+		t.Fatalf("FormatLines() error = %v", err)
+	}
+
+	texts := make([]string, len(lines))
+	paths := make([]string, len(lines))
+	for i, line := range lines {
+		texts[i] = line.Text
+		paths[i] = line.Path
+	}
+	formatted, _ := n.Format("", "  ")
+	if strings.Join(texts, "\n") != string(formatted) {
+		t.Errorf("FormatLines() = %s, want %s", strings.Join(texts, "\n"), formatted)
+	}
+	want := []string{"", "/b", "/b/0", "/b/1", "/b/1/c~1d", "/b/1", "/b", "/a", "/e", ""}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("FormatLines() paths = %v, want %v", paths, want)
+	}
+}
@@ -0,0 +1,137 @@
+package merge
+
+import (
+	"strconv"
+
+	"github.com/sascha-andres/jsonedit/json"
+)
+
+// Side names one of the two edited documents of a three-way merge
+type Side string
+
+const (
+	// Ours is the document that is merged into
+	Ours Side = "ours"
+
+	// Theirs is the document that is merged from
+	Theirs Side = "theirs"
+)
+
+type (
+	// Conflict is a value changed differently in both edited documents
+	Conflict struct {
+
+		// Path is the JSON Pointer of the conflicting value
+		Path string `json:"path"`
+
+		// Base is the value in the common ancestor, nil if it did not exist
+		Base *json.Node `json:"base,omitempty"`
+
+		// Ours is the value in our document, nil if it was removed
+		Ours *json.Node `json:"ours,omitempty"`
+
+		// Theirs is the value in their document, nil if it was removed
+		Theirs *json.Node `json:"theirs,omitempty"`
+	}
+
+	// Result is the outcome of a three-way merge
+	Result struct {
+
+		// Document is the merged document, unresolved conflicts take the value of our document
+		Document *json.Node `json:"document"`
+
+		// Conflicts lists the conflicts that were not resolved
+		Conflicts []Conflict `json:"conflicts"`
+	}
+
+	// merger holds the configuration and the conflicts of a merge
+	merger struct {
+
+		// resolutions maps the path of a conflict to the side that is taken
+		resolutions map[string]Side
+
+		// conflicts collects the unresolved conflicts
+		conflicts []Conflict
+	}
+
+	// Option configures a merge
+	Option func(*merger)
+)
+
+// WithResolution takes the value of side for the conflict at path
+func WithResolution(path string, side Side) Option {
+	return func(m *merger) {
+		m.resolutions[path] = side
+	}
+}
+
+// Merge combines the changes made in ours and theirs to their common ancestor base.
+// Changes made on one side only are taken over, object members are merged individually
+// and arrays of equal length element by element. Values changed differently on both sides
+// are reported as conflicts unless resolved with WithResolution. None of the documents is modified.
+func Merge(base, ours, theirs *json.Node, options ...Option) *Result {
+	m := &merger{resolutions: make(map[string]Side), conflicts: []Conflict{}}
+	for _, option := range options {
+		option(m)
+	}
+	document := m.merge("", base, ours, theirs)
+	return &Result{Document: document, Conflicts: m.conflicts}
+}
+
+// merge returns the merged value at path, nil if the value is removed
+func (m *merger) merge(path string, base, ours, theirs *json.Node) *json.Node {
+	switch {
+	case ours.Equal(theirs):
+		return ours.Clone()
+	case base.Equal(ours):
+		return theirs.Clone()
+	case base.Equal(theirs):
+		return ours.Clone()
+	case sameKind(json.ObjectKind, base, ours, theirs):
+		return m.mergeObject(path, base, ours, theirs)
+	case sameKind(json.ArrayKind, base, ours, theirs) && len(base.Items) == len(ours.Items) && len(base.Items) == len(theirs.Items):
+		merged := json.NewArray()
+		for i := range base.Items {
+			merged.Items = append(merged.Items, m.merge(json.AppendPointer(path, strconv.Itoa(i)), base.Items[i], ours.Items[i], theirs.Items[i]))
+		}
+		return merged
+	}
+
+	switch m.resolutions[path] {
+	case Theirs:
+		return theirs.Clone()
+	case Ours:
+		return ours.Clone()
+	}
+	m.conflicts = append(m.conflicts, Conflict{Path: path, Base: base, Ours: ours, Theirs: theirs})
+	return ours.Clone()
+}
+
+// mergeObject merges the members of three objects, keeping the member order of ours
+// followed by members only added in theirs
+func (m *merger) mergeObject(path string, base, ours, theirs *json.Node) *json.Node {
+	merged := json.NewObject()
+	keys := ours.Keys()
+	for _, key := range theirs.Keys() {
+		if ours.Get(key) == nil {
+			keys = append(keys, key)
+		}
+	}
+	for _, key := range keys {
+		value := m.merge(json.AppendPointer(path, key), base.Get(key), ours.Get(key), theirs.Get(key))
+		if value != nil {
+			merged.Set(key, value)
+		}
+	}
+	return merged
+}
+
+// sameKind reports whether all nodes exist and are of kind
+func sameKind(kind json.Kind, nodes ...*json.Node) bool {
+	for _, n := range nodes {
+		if n == nil || n.Kind != kind {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,126 @@
+package merge
+
+import (
+	"testing"
+
+	"github.com/sascha-andres/jsonedit/json"
+)
+
+// mustParse parses doc or fails the test
+func mustParse(t *testing.T, doc string) *json.Node {
+	t.Helper()
+	n, err := json.Parse([]byte(doc))
+	if err != nil {
+		t.Fatalf("Parse(%s) error = %v", doc, err)
+	}
+	return n
+}
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name      string
+		base      string
+		ours      string
+		theirs    string
+		options   []Option
+		want      string
+		conflicts []string
+	}{
+		{
+			name:   "Changes on different members",
+			base:   `{"a": 1, "b": 1, "c": 1}`,
+			ours:   `{"a": 2, "b": 1, "c": 1}`,
+			theirs: `{"a": 1, "b": 2}`,
+			want:   `{"a":2,"b":2}`,
+		},
+		{
+			name:   "Members added on both sides",
+			base:   `{"a": 1}`,
+			ours:   `{"a": 1, "b": 1}`,
+			theirs: `{"c": 1, "a": 1}`,
+			want:   `{"a":1,"b":1,"c":1}`,
+		},
+		{
+			name:   "Same change on both sides",
+			base:   `{"a": 1}`,
+			ours:   `{"a": 2}`,
+			theirs: `{"a": 2}`,
+			want:   `{"a":2}`,
+		},
+		{
+			name:   "Nested objects",
+			base:   `{"db": {"host": "a", "port": 1}}`,
+			ours:   `{"db": {"host": "b", "port": 1}}`,
+			theirs: `{"db": {"host": "a", "port": 2}}`,
+			want:   `{"db":{"host":"b","port":2}}`,
+		},
+		{
+			name:   "Arrays of equal length",
+			base:   `[1, 2, 3]`,
+			ours:   `[0, 2, 3]`,
+			theirs: `[1, 2, 4]`,
+			want:   `[0,2,4]`,
+		},
+		{
+			name:      "Conflicting scalar",
+			base:      `{"a": 1, "b": 1}`,
+			ours:      `{"a": 2, "b": 1}`,
+			theirs:    `{"a": 3, "b": 2}`,
+			want:      `{"a":2,"b":2}`,
+			conflicts: []string{"/a"},
+		},
+		{
+			name:      "Modified and removed",
+			base:      `{"a": 1}`,
+			ours:      `{}`,
+			theirs:    `{"a": 2}`,
+			want:      `{}`,
+			conflicts: []string{"/a"},
+		},
+		{
+			name:      "Arrays of different length",
+			base:      `{"l": [1]}`,
+			ours:      `{"l": [1, 2]}`,
+			theirs:    `{"l": [1, 3]}`,
+			want:      `{"l":[1,2]}`,
+			conflicts: []string{"/l"},
+		},
+		{
+			name:    "Resolved with theirs",
+			base:    `{"a": 1, "b": 1}`,
+			ours:    `{"a": 2}`,
+			theirs:  `{"a": 3, "b": 2}`,
+			options: []Option{WithResolution("/a", Theirs), WithResolution("/b", Theirs)},
+			want:    `{"a":3,"b":2}`,
+		},
+		{
+			name:    "Resolved with ours",
+			base:    `{"a": 1, "b": 1}`,
+			ours:    `{"a": 2}`,
+			theirs:  `{"a": 3, "b": 2}`,
+			options: []Option{WithResolution("/a", Ours), WithResolution("/b", Ours)},
+			want:    `{"a":2}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, ours, theirs := mustParse(t, tt.base), mustParse(t, tt.ours), mustParse(t, tt.theirs)
+			result := Merge(base, ours, theirs, tt.options...)
+			got, _ := result.Document.Format("", "")
+			if string(got) != tt.want {
+				t.Errorf("Merge() = %s, want %s", got, tt.want)
+			}
+			if len(result.Conflicts) != len(tt.conflicts) {
+				t.Fatalf("Merge() conflicts = %v, want %v", result.Conflicts, tt.conflicts)
+			}
+			for i, conflict := range result.Conflicts {
+				if conflict.Path != tt.conflicts[i] {
+					t.Errorf("Merge() conflict %d at %q, want %q", i, conflict.Path, tt.conflicts[i])
+				}
+			}
+			if !mustParse(t, tt.ours).Equal(ours) || !mustParse(t, tt.theirs).Equal(theirs) {
+				t.Errorf("Merge() modified the documents")
+			}
+		})
+	}
+}
@@ -0,0 +1,581 @@
+package json
+
+import (
+	"bytes"
+	stdJson "encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Kind identifies the JSON type of a Node
+type Kind int
+
+const (
+	// NullKind is the kind of the JSON literal null
+	NullKind Kind = iota
+
+	// BoolKind is the kind of the JSON literals true and false
+	BoolKind
+
+	// NumberKind is the kind of JSON numbers
+	NumberKind
+
+	// StringKind is the kind of JSON strings
+	StringKind
+
+	// ArrayKind is the kind of JSON arrays
+	ArrayKind
+
+	// ObjectKind is the kind of JSON objects
+	ObjectKind
+)
+
+// String returns the JSON name of the kind
+func (k Kind) String() string {
+	switch k {
+	case NullKind:
+		return "null"
+	case BoolKind:
+		return "boolean"
+	case NumberKind:
+		return "number"
+	case StringKind:
+		return "string"
+	case ArrayKind:
+		return "array"
+	case ObjectKind:
+		return "object"
+	default:
+		return "unknown"
+	}
+}
+
+type (
+	// Member is a single key/value pair of a JSON object
+	Member struct {
+
+		// Key is the name of the member
+		Key string
+
+		// Value is the value of the member
+		Value *Node
+	}
+
+	// Node is an order-preserving representation of a JSON value.
+	// Object members keep the order of the source document and numbers are kept
+	// as json.Number so they are written back exactly as they were read.
+	Node struct {
+
+		// Kind is the JSON type of the node, it determines which of the other fields is used
+		Kind Kind
+
+		// Boolean holds the value of a BoolKind node
+		Boolean bool
+
+		// Number holds the literal of a NumberKind node
+		Number stdJson.Number
+
+		// Text holds the value of a StringKind node
+		Text string
+
+		// Items holds the elements of an ArrayKind node
+		Items []*Node
+
+		// Members holds the members of an ObjectKind node in document order
+		Members []Member
+	}
+)
+
+// NewNull creates a node representing null
+func NewNull() *Node {
+	return &Node{Kind: NullKind}
+}
+
+// NewBool creates a node holding a boolean
+func NewBool(b bool) *Node {
+	return &Node{Kind: BoolKind, Boolean: b}
+}
+
+// NewNumber creates a node holding a number literal
+func NewNumber(n stdJson.Number) *Node {
+	return &Node{Kind: NumberKind, Number: n}
+}
+
+// NewString creates a node holding a string
+func NewString(s string) *Node {
+	return &Node{Kind: StringKind, Text: s}
+}
+
+// NewArray creates an array node holding items
+func NewArray(items ...*Node) *Node {
+	if items == nil {
+		items = []*Node{}
+	}
+	return &Node{Kind: ArrayKind, Items: items}
+}
+
+// NewObject creates an object node holding members in the given order
+func NewObject(members ...Member) *Node {
+	if members == nil {
+		members = []Member{}
+	}
+	return &Node{Kind: ObjectKind, Members: members}
+}
+
+// Parse reads a JSON document and returns its order-preserving representation
+func Parse(data []byte) (*Node, error) {
+	dec := stdJson.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	n, err := parseValue(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("invalid character after top-level value")
+	}
+	return n, nil
+}
+
+// parseValue reads the next complete value from dec
+func parseValue(dec *stdJson.Decoder) (*Node, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case stdJson.Delim:
+		switch t {
+		case '{':
+			n := NewObject()
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key, ok := keyTok.(string)
+				if !ok {
+					return nil, fmt.Errorf("invalid object key %v", keyTok)
+				}
+				value, err := parseValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				n.Set(key, value)
+			}
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			return n, nil
+		case '[':
+			n := NewArray()
+			for dec.More() {
+				value, err := parseValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				n.Items = append(n.Items, value)
+			}
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			return n, nil
+		default:
+			return nil, fmt.Errorf("unexpected delimiter %v", t)
+		}
+	case string:
+		return NewString(t), nil
+	case stdJson.Number:
+		return NewNumber(t), nil
+	case bool:
+		return NewBool(t), nil
+	case nil:
+		return NewNull(), nil
+	default:
+		return nil, fmt.Errorf("unexpected token %v", tok)
+	}
+}
+
+// FromInterface converts a value as produced by encoding/json (maps, slices, strings,
+// numbers, booleans and nil) into a Node. Map keys are sorted as there is no order
+// to preserve. Other values are converted by marshalling them to JSON first.
+func FromInterface(v interface{}) (*Node, error) {
+	switch t := v.(type) {
+	case *Node:
+		return t, nil
+	case nil:
+		return NewNull(), nil
+	case bool:
+		return NewBool(t), nil
+	case string:
+		return NewString(t), nil
+	case stdJson.Number:
+		return NewNumber(t), nil
+	case float64:
+		if math.IsInf(t, 0) || math.IsNaN(t) {
+			return nil, fmt.Errorf("unsupported number %v", t)
+		}
+		return NewNumber(stdJson.Number(strconv.FormatFloat(t, 'f', -1, 64))), nil
+	case float32:
+		return FromInterface(float64(t))
+	case int:
+		return NewNumber(stdJson.Number(strconv.Itoa(t))), nil
+	case int64:
+		return NewNumber(stdJson.Number(strconv.FormatInt(t, 10))), nil
+	case []interface{}:
+		n := NewArray()
+		for _, item := range t {
+			child, err := FromInterface(item)
+			if err != nil {
+				return nil, err
+			}
+			n.Items = append(n.Items, child)
+		}
+		return n, nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for key := range t {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		n := NewObject()
+		for _, key := range keys {
+			child, err := FromInterface(t[key])
+			if err != nil {
+				return nil, err
+			}
+			n.Members = append(n.Members, Member{Key: key, Value: child})
+		}
+		return n, nil
+	default:
+		data, err := stdJson.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		return Parse(data)
+	}
+}
+
+// Interface converts the node into the representation used by encoding/json when
+// decoding with UseNumber: map[string]interface{}, []interface{}, string, json.Number, bool or nil.
+func (n *Node) Interface() interface{} {
+	if n == nil {
+		return nil
+	}
+	switch n.Kind {
+	case BoolKind:
+		return n.Boolean
+	case NumberKind:
+		return n.Number
+	case StringKind:
+		return n.Text
+	case ArrayKind:
+		items := make([]interface{}, len(n.Items))
+		for i, item := range n.Items {
+			items[i] = item.Interface()
+		}
+		return items
+	case ObjectKind:
+		members := make(map[string]interface{}, len(n.Members))
+		for _, member := range n.Members {
+			members[member.Key] = member.Value.Interface()
+		}
+		return members
+	default:
+		return nil
+	}
+}
+
+// Get returns the value of the member key of an object node, nil if it does not exist
+func (n *Node) Get(key string) *Node {
+	if i := n.index(key); i >= 0 {
+		return n.Members[i].Value
+	}
+	return nil
+}
+
+// Set replaces the value of the member key of an object node or appends a new member
+func (n *Node) Set(key string, value *Node) {
+	if i := n.index(key); i >= 0 {
+		n.Members[i].Value = value
+		return
+	}
+	n.Members = append(n.Members, Member{Key: key, Value: value})
+}
+
+// Delete removes the member key of an object node and reports whether it existed
+func (n *Node) Delete(key string) bool {
+	i := n.index(key)
+	if i < 0 {
+		return false
+	}
+	n.Members = append(n.Members[:i], n.Members[i+1:]...)
+	return true
+}
+
+// Keys returns the member names of an object node in document order
+func (n *Node) Keys() []string {
+	keys := make([]string, len(n.Members))
+	for i, member := range n.Members {
+		keys[i] = member.Key
+	}
+	return keys
+}
+
+// index returns the position of member key, -1 if it does not exist
+func (n *Node) index(key string) int {
+	if n == nil || n.Kind != ObjectKind {
+		return -1
+	}
+	for i, member := range n.Members {
+		if member.Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// Clone returns a deep copy of the node
+func (n *Node) Clone() *Node {
+	if n == nil {
+		return nil
+	}
+	c := &Node{Kind: n.Kind, Boolean: n.Boolean, Number: n.Number, Text: n.Text}
+	if n.Items != nil {
+		c.Items = make([]*Node, len(n.Items))
+		for i, item := range n.Items {
+			c.Items[i] = item.Clone()
+		}
+	}
+	if n.Members != nil {
+		c.Members = make([]Member, len(n.Members))
+		for i, member := range n.Members {
+			c.Members[i] = Member{Key: member.Key, Value: member.Value.Clone()}
+		}
+	}
+	return c
+}
+
+// Equal reports whether both nodes represent the same JSON value. Object members are
+// compared regardless of their order and numbers are compared by value, so 1.0 equals 1.
+func (n *Node) Equal(o *Node) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	if n.Kind != o.Kind {
+		return false
+	}
+	switch n.Kind {
+	case BoolKind:
+		return n.Boolean == o.Boolean
+	case NumberKind:
+		return NumbersEqual(n.Number, o.Number)
+	case StringKind:
+		return n.Text == o.Text
+	case ArrayKind:
+		if len(n.Items) != len(o.Items) {
+			return false
+		}
+		for i := range n.Items {
+			if !n.Items[i].Equal(o.Items[i]) {
+				return false
+			}
+		}
+		return true
+	case ObjectKind:
+		if len(n.Members) != len(o.Members) {
+			return false
+		}
+		for _, member := range n.Members {
+			other := o.Get(member.Key)
+			if other == nil || !member.Value.Equal(other) {
+				return false
+			}
+		}
+		return true
+	default:
+		return true
+	}
+}
+
+// NumbersEqual reports whether two number literals represent the same value
+func NumbersEqual(a, b stdJson.Number) bool {
+	if a == b {
+		return true
+	}
+	x, ok := new(big.Rat).SetString(string(a))
+	if !ok {
+		return false
+	}
+	y, ok := new(big.Rat).SetString(string(b))
+	if !ok {
+		return false
+	}
+	return x.Cmp(y) == 0
+}
+
+// MarshalJSON writes the node as compact JSON
+func (n *Node) MarshalJSON() ([]byte, error) {
+	return n.Format("", "")
+}
+
+// UnmarshalJSON reads the node from JSON
+func (n *Node) UnmarshalJSON(data []byte) error {
+	parsed, err := Parse(data)
+	if err != nil {
+		return err
+	}
+	*n = *parsed
+	return nil
+}
+
+// Format writes the node as JSON. With an empty indent the output is compact, otherwise each
+// element begins on a new line starting with prefix followed by copies of indent, like json.MarshalIndent.
+// Unlike encoding/json, the characters <, > and & are not escaped.
+func (n *Node) Format(prefix, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := n.write(&buf, prefix, indent, 0); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// write appends the JSON representation of the node at the given nesting depth to buf
+func (n *Node) write(buf *bytes.Buffer, prefix, indent string, depth int) error {
+	if n == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	switch n.Kind {
+	case NullKind:
+		buf.WriteString("null")
+	case BoolKind:
+		buf.WriteString(strconv.FormatBool(n.Boolean))
+	case NumberKind:
+		if n.Number == "" {
+			buf.WriteString("0")
+			return nil
+		}
+		if !isValidNumber(string(n.Number)) {
+			return fmt.Errorf("invalid number literal %q", n.Number)
+		}
+		buf.WriteString(string(n.Number))
+	case StringKind:
+		return writeString(buf, n.Text)
+	case ArrayKind:
+		if len(n.Items) == 0 {
+			buf.WriteString("[]")
+			return nil
+		}
+		buf.WriteByte('[')
+		for i, item := range n.Items {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			newline(buf, prefix, indent, depth+1)
+			if err := item.write(buf, prefix, indent, depth+1); err != nil {
+				return err
+			}
+		}
+		newline(buf, prefix, indent, depth)
+		buf.WriteByte(']')
+	case ObjectKind:
+		if len(n.Members) == 0 {
+			buf.WriteString("{}")
+			return nil
+		}
+		buf.WriteByte('{')
+		for i, member := range n.Members {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			newline(buf, prefix, indent, depth+1)
+			if err := writeString(buf, member.Key); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			if indent != "" {
+				buf.WriteByte(' ')
+			}
+			if err := member.Value.write(buf, prefix, indent, depth+1); err != nil {
+				return err
+			}
+		}
+		newline(buf, prefix, indent, depth)
+		buf.WriteByte('}')
+	default:
+		return fmt.Errorf("unknown node kind %d", n.Kind)
+	}
+	return nil
+}
+
+// newline starts a new indented line if an indent is configured
+func newline(buf *bytes.Buffer, prefix, indent string, depth int) {
+	if indent == "" {
+		return
+	}
+	buf.WriteByte('\n')
+	buf.WriteString(prefix)
+	for i := 0; i < depth; i++ {
+		buf.WriteString(indent)
+	}
+}
+
+// writeString appends s as quoted JSON string, escaping like encoding/json but without HTML escaping
+func writeString(buf *bytes.Buffer, s string) error {
+	const hex = "0123456789abcdef"
+	buf.WriteByte('"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			switch {
+			case c == '"' || c == '\\':
+				buf.WriteByte('\\')
+				buf.WriteByte(c)
+			case c == '\n':
+				buf.WriteString(`\n`)
+			case c == '\r':
+				buf.WriteString(`\r`)
+			case c == '\t':
+				buf.WriteString(`\t`)
+			case c < 0x20:
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hex[c>>4])
+				buf.WriteByte(hex[c&0xF])
+			default:
+				buf.WriteByte(c)
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			buf.WriteString(`\ufffd`)
+		case r == '\u2028' || r == '\u2029':
+			buf.WriteString(`\u202`)
+			buf.WriteByte(hex[r&0xF])
+		default:
+			buf.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	buf.WriteByte('"')
+	return nil
+}
+
+// isValidNumber reports whether s is a valid JSON number literal
+func isValidNumber(s string) bool {
+	if s == "" || (s[0] != '-' && (s[0] < '0' || s[0] > '9')) {
+		return false
+	}
+	return stdJson.Valid([]byte(s))
+}
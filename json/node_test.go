@@ -0,0 +1,125 @@
+package json
+
+import (
+	stdJson "encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParseAndFormatRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+	}{
+		{name: "Key order is kept", doc: "{\n  \"zeta\": 1,\n  \"alpha\": 2,\n  \"mid\": {\n    \"b\": true,\n    \"a\": null\n  }\n}"},
+		{name: "Big numbers are kept", doc: "{\n  \"id\": 12345678901234567890,\n  \"float\": 1.50,\n  \"exp\": 1e+06\n}"},
+		{name: "HTML characters are not escaped", doc: "{\n  \"html\": \"<a href=\\\"x\\\">&</a>\"\n}"},
+		{name: "Control characters are escaped", doc: "{\n  \"text\": \"line\\nnext\\ttab\\u0001\"\n}"},
+		{name: "Empty containers", doc: "{\n  \"object\": {},\n  \"array\": []\n}"},
+		{name: "Root array", doc: "[\n  1,\n  \"two\",\n  [\n    3\n  ]\n]"},
+		{name: "Root scalar", doc: "\"value\""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := Parse([]byte(tt.doc))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			got, err := n.Format("", "  ")
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if string(got) != tt.doc {
+				t.Errorf("Format() = %s, want %s", got, tt.doc)
+			}
+		})
+	}
+}
+
+func TestFormatCompact(t *testing.T) {
+	n, err := Parse([]byte(`{ "b" : [1, 2], "a" : {"c": "d"} }`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	got, err := stdJson.Marshal(n)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if string(got) != `{"b":[1,2],"a":{"c":"d"}}` {
+		t.Errorf("Marshal() = %s", got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, doc := range []string{``, `{`, `{"a": }`, `[1,]`, `{} {}`, `{"a": 1`} {
+		if _, err := Parse([]byte(doc)); err == nil {
+			t.Errorf("Parse(%q) expected error", doc)
+		}
+	}
+}
+
+func TestParseDuplicateKeys(t *testing.T) {
+	n, err := Parse([]byte(`{"a": 1, "b": 2, "a": 3}`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if !reflect.DeepEqual(n.Keys(), []string{"a", "b"}) {
+		t.Errorf("Keys() = %v", n.Keys())
+	}
+	if n.Get("a").Number != "3" {
+		t.Errorf("Get(a) = %v, want last value", n.Get("a").Number)
+	}
+}
+
+func TestNodeMembers(t *testing.T) {
+	n := NewObject()
+	n.Set("b", NewNumber("1"))
+	n.Set("a", NewString("x"))
+	n.Set("b", NewBool(true))
+	if !reflect.DeepEqual(n.Keys(), []string{"b", "a"}) {
+		t.Errorf("Keys() = %v", n.Keys())
+	}
+	if !n.Get("b").Boolean {
+		t.Errorf("Get(b) not replaced")
+	}
+	if !n.Delete("b") || n.Delete("missing") {
+		t.Errorf("Delete() returned wrong result")
+	}
+	if n.Get("b") != nil {
+		t.Errorf("Get(b) after delete = %v", n.Get("b"))
+	}
+}
+
+func TestFromInterfaceAndInterface(t *testing.T) {
+	in := map[string]interface{}{
+		"name": "John",
+		"age":  30,
+		"tags": []interface{}{"a", 1.5, nil, false},
+	}
+	n, err := FromInterface(in)
+	if err != nil {
+		t.Fatalf("FromInterface() error = %v", err)
+	}
+	got, _ := n.Format("", "")
+	if string(got) != `{"age":30,"name":"John","tags":["a",1.5,null,false]}` {
+		t.Errorf("FromInterface() = %s", got)
+	}
+
+	want := map[string]interface{}{
+		"age":  stdJson.Number("30"),
+		"name": "John",
+		"tags": []interface{}{"a", stdJson.Number("1.5"), nil, false},
+	}
+	if !reflect.DeepEqual(n.Interface(), want) {
+		t.Errorf("Interface() = %#v", n.Interface())
+	}
+}
+
+func TestClone(t *testing.T) {
+	n, _ := Parse([]byte(`{"a": [1, {"b": 2}]}`))
+	c := n.Clone()
+	c.Get("a").Items[1].Set("b", NewNumber("3"))
+	if n.Get("a").Items[1].Get("b").Number != "2" {
+		t.Errorf("Clone() shares nested nodes")
+	}
+}
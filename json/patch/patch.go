@@ -0,0 +1,335 @@
+package patch
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sascha-andres/jsonedit/json"
+)
+
+// ErrTestFailed is returned when the value of a test operation does not match the document
+var ErrTestFailed = errors.New("test failed")
+
+// OperationError reports the JSON Patch operation that could not be applied
+type OperationError struct {
+
+	// Index is the zero based position of the operation in the patch document
+	Index int
+
+	// Op is the name of the operation, empty if the operation has no valid name
+	Op string
+
+	// Path is the JSON Pointer the operation refers to
+	Path string
+
+	// Err is the reason the operation failed
+	Err error
+}
+
+// Error returns a description of the failed operation
+func (e *OperationError) Error() string {
+	return fmt.Sprintf("operation %d (%s %q) failed: %v", e.Index, e.Op, e.Path, e.Err)
+}
+
+// Unwrap returns the reason the operation failed
+func (e *OperationError) Unwrap() error {
+	return e.Err
+}
+
+// ApplyJSONPatch applies an RFC 6902 JSON Patch document to doc and returns the result.
+// doc is not modified. Operations are applied in order and the first failing operation
+// is reported as *OperationError.
+func ApplyJSONPatch(doc, patch *json.Node) (*json.Node, error) {
+	if patch.Kind != json.ArrayKind {
+		return nil, fmt.Errorf("a JSON Patch must be an array of operations, got %s", patch.Kind)
+	}
+	result := doc.Clone()
+	for i, operation := range patch.Items {
+		var err error
+		result, err = applyOperation(result, operation)
+		if err != nil {
+			opErr := &OperationError{Index: i, Err: err}
+			if operation.Kind == json.ObjectKind {
+				opErr.Op = stringMember(operation, "op")
+				opErr.Path = stringMember(operation, "path")
+			}
+			return nil, opErr
+		}
+	}
+	return result, nil
+}
+
+// ApplyMergePatch applies an RFC 7386 Merge Patch to doc and returns the result. doc is not modified.
+func ApplyMergePatch(doc, patch *json.Node) *json.Node {
+	return mergePatch(doc.Clone(), patch)
+}
+
+// mergePatch merges patch into target
+func mergePatch(target, patch *json.Node) *json.Node {
+	if patch.Kind != json.ObjectKind {
+		return patch.Clone()
+	}
+	if target == nil || target.Kind != json.ObjectKind {
+		target = json.NewObject()
+	}
+	for _, member := range patch.Members {
+		if member.Value.Kind == json.NullKind {
+			target.Delete(member.Key)
+			continue
+		}
+		target.Set(member.Key, mergePatch(target.Get(member.Key), member.Value))
+	}
+	return target
+}
+
+// applyOperation applies a single JSON Patch operation to doc and returns the new document
+func applyOperation(doc, operation *json.Node) (*json.Node, error) {
+	if operation.Kind != json.ObjectKind {
+		return nil, errors.New("operation must be an object")
+	}
+	op, err := requiredString(operation, "op")
+	if err != nil {
+		return nil, err
+	}
+	path, err := requiredString(operation, "path")
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := json.ParsePointer(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case "add":
+		value, err := requiredValue(operation)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, tokens, value.Clone())
+	case "remove":
+		result, _, err := remove(doc, tokens)
+		return result, err
+	case "replace":
+		value, err := requiredValue(operation)
+		if err != nil {
+			return nil, err
+		}
+		return replace(doc, tokens, value.Clone())
+	case "move":
+		fromTokens, from, err := fromPointer(operation)
+		if err != nil {
+			return nil, err
+		}
+		if path != from && strings.HasPrefix(path, from+"/") {
+			return nil, fmt.Errorf("cannot move %q into one of its children", from)
+		}
+		result, value, err := remove(doc, fromTokens)
+		if err != nil {
+			return nil, err
+		}
+		return add(result, tokens, value)
+	case "copy":
+		fromTokens, _, err := fromPointer(operation)
+		if err != nil {
+			return nil, err
+		}
+		value, err := get(doc, fromTokens)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, tokens, value.Clone())
+	case "test":
+		value, err := requiredValue(operation)
+		if err != nil {
+			return nil, err
+		}
+		current, err := get(doc, tokens)
+		if err != nil {
+			return nil, err
+		}
+		if !current.Equal(value) {
+			return nil, ErrTestFailed
+		}
+		return doc, nil
+	default:
+		return nil, fmt.Errorf("unknown operation %q", op)
+	}
+}
+
+// get returns the value addressed by tokens
+func get(doc *json.Node, tokens []string) (*json.Node, error) {
+	current := doc
+	for i, token := range tokens {
+		switch current.Kind {
+		case json.ObjectKind:
+			next := current.Get(token)
+			if next == nil {
+				return nil, fmt.Errorf("member %q not found at %q", token, pointer(tokens[:i]))
+			}
+			current = next
+		case json.ArrayKind:
+			index, err := arrayIndex(token, len(current.Items)-1)
+			if err != nil {
+				return nil, fmt.Errorf("%w at %q", err, pointer(tokens[:i]))
+			}
+			current = current.Items[index]
+		default:
+			return nil, fmt.Errorf("cannot address %q in %s at %q", token, current.Kind, pointer(tokens[:i]))
+		}
+	}
+	return current, nil
+}
+
+// add inserts value at the location addressed by tokens and returns the new document
+func add(doc *json.Node, tokens []string, value *json.Node) (*json.Node, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	parent, err := get(doc, tokens[:len(tokens)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := tokens[len(tokens)-1]
+	switch parent.Kind {
+	case json.ObjectKind:
+		parent.Set(last, value)
+	case json.ArrayKind:
+		index := len(parent.Items)
+		if last != "-" {
+			index, err = arrayIndex(last, len(parent.Items))
+			if err != nil {
+				return nil, err
+			}
+		}
+		parent.Items = append(parent.Items, nil)
+		copy(parent.Items[index+1:], parent.Items[index:])
+		parent.Items[index] = value
+	default:
+		return nil, fmt.Errorf("cannot add %q to %s", last, parent.Kind)
+	}
+	return doc, nil
+}
+
+// replace overwrites the existing value addressed by tokens and returns the new document.
+// Object members keep their position.
+func replace(doc *json.Node, tokens []string, value *json.Node) (*json.Node, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	parent, err := get(doc, tokens[:len(tokens)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := tokens[len(tokens)-1]
+	switch parent.Kind {
+	case json.ObjectKind:
+		if parent.Get(last) == nil {
+			return nil, fmt.Errorf("member %q not found", last)
+		}
+		parent.Set(last, value)
+	case json.ArrayKind:
+		index, err := arrayIndex(last, len(parent.Items)-1)
+		if err != nil {
+			return nil, err
+		}
+		parent.Items[index] = value
+	default:
+		return nil, fmt.Errorf("cannot replace %q in %s", last, parent.Kind)
+	}
+	return doc, nil
+}
+
+// remove deletes the value addressed by tokens and returns the new document and the removed value
+func remove(doc *json.Node, tokens []string) (*json.Node, *json.Node, error) {
+	if len(tokens) == 0 {
+		return json.NewNull(), doc, nil
+	}
+	parent, err := get(doc, tokens[:len(tokens)-1])
+	if err != nil {
+		return nil, nil, err
+	}
+	last := tokens[len(tokens)-1]
+	switch parent.Kind {
+	case json.ObjectKind:
+		value := parent.Get(last)
+		if value == nil {
+			return nil, nil, fmt.Errorf("member %q not found", last)
+		}
+		parent.Delete(last)
+		return doc, value, nil
+	case json.ArrayKind:
+		index, err := arrayIndex(last, len(parent.Items)-1)
+		if err != nil {
+			return nil, nil, err
+		}
+		value := parent.Items[index]
+		parent.Items = append(parent.Items[:index], parent.Items[index+1:]...)
+		return doc, value, nil
+	default:
+		return nil, nil, fmt.Errorf("cannot remove %q from %s", last, parent.Kind)
+	}
+}
+
+// arrayIndex parses an array index token and checks that it is not greater than maximum
+func arrayIndex(token string, maximum int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') || strings.TrimLeft(token, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index > maximum {
+		return 0, fmt.Errorf("array index %s out of bounds", token)
+	}
+	return index, nil
+}
+
+// pointer joins tokens to a JSON Pointer
+func pointer(tokens []string) string {
+	result := ""
+	for _, token := range tokens {
+		result = json.AppendPointer(result, token)
+	}
+	return result
+}
+
+// requiredString returns the string member name of operation
+func requiredString(operation *json.Node, name string) (string, error) {
+	value := operation.Get(name)
+	if value == nil || value.Kind != json.StringKind {
+		return "", fmt.Errorf("member %q must be a string", name)
+	}
+	return value.Text, nil
+}
+
+// requiredValue returns the value member of operation
+func requiredValue(operation *json.Node) (*json.Node, error) {
+	value := operation.Get("value")
+	if value == nil {
+		return nil, errors.New("member \"value\" is missing")
+	}
+	return value, nil
+}
+
+// fromPointer returns the parsed and the raw from member of operation
+func fromPointer(operation *json.Node) ([]string, string, error) {
+	from, err := requiredString(operation, "from")
+	if err != nil {
+		return nil, "", err
+	}
+	tokens, err := json.ParsePointer(from)
+	if err != nil {
+		return nil, "", err
+	}
+	return tokens, from, nil
+}
+
+// stringMember returns the string member name of n, empty if it does not exist
+func stringMember(n *json.Node, name string) string {
+	value := n.Get(name)
+	if value == nil || value.Kind != json.StringKind {
+		return ""
+	}
+	return value.Text
+}
@@ -0,0 +1,133 @@
+package patch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sascha-andres/jsonedit/json"
+)
+
+// mustParse parses doc or fails the test
+func mustParse(t *testing.T, doc string) *json.Node {
+	t.Helper()
+	n, err := json.Parse([]byte(doc))
+	if err != nil {
+		t.Fatalf("Parse(%s) error = %v", doc, err)
+	}
+	return n
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{name: "Add member", doc: `{"foo": "bar"}`, patch: `[{"op": "add", "path": "/baz", "value": "qux"}]`, want: `{"foo":"bar","baz":"qux"}`},
+		{name: "Add array element", doc: `{"foo": ["bar", "baz"]}`, patch: `[{"op": "add", "path": "/foo/1", "value": "qux"}]`, want: `{"foo":["bar","qux","baz"]}`},
+		{name: "Append array element", doc: `{"foo": [1]}`, patch: `[{"op": "add", "path": "/foo/-", "value": [2]}]`, want: `{"foo":[1,[2]]}`},
+		{name: "Remove member", doc: `{"baz": "qux", "foo": "bar"}`, patch: `[{"op": "remove", "path": "/baz"}]`, want: `{"foo":"bar"}`},
+		{name: "Remove array element", doc: `{"foo": ["bar", "qux", "baz"]}`, patch: `[{"op": "remove", "path": "/foo/1"}]`, want: `{"foo":["bar","baz"]}`},
+		{name: "Replace value", doc: `{"baz": "qux", "foo": "bar"}`, patch: `[{"op": "replace", "path": "/baz", "value": "boo"}]`, want: `{"baz":"boo","foo":"bar"}`},
+		{name: "Move value", doc: `{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": "grault"}}`, patch: `[{"op": "move", "from": "/foo/waldo", "path": "/qux/thud"}]`, want: `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{name: "Move array element", doc: `{"foo": ["all", "grass", "cows", "eat"]}`, patch: `[{"op": "move", "from": "/foo/1", "path": "/foo/3"}]`, want: `{"foo":["all","cows","eat","grass"]}`},
+		{name: "Copy value", doc: `{"a": {"b": 1}}`, patch: `[{"op": "copy", "from": "/a", "path": "/c"}, {"op": "replace", "path": "/c/b", "value": 2}]`, want: `{"a":{"b":1},"c":{"b":2}}`},
+		{name: "Successful test", doc: `{"baz": "qux", "foo": ["a", 2, "c"]}`, patch: `[{"op": "test", "path": "/baz", "value": "qux"}, {"op": "test", "path": "/foo/1", "value": 2.0}]`, want: `{"baz":"qux","foo":["a",2,"c"]}`},
+		{name: "Escaped keys", doc: `{"/": 9, "~1": 10}`, patch: `[{"op": "test", "path": "/~01", "value": 10}, {"op": "remove", "path": "/~1"}]`, want: `{"~1":10}`},
+		{name: "Replace document", doc: `{"a": 1}`, patch: `[{"op": "replace", "path": "", "value": [1]}]`, want: `[1]`},
+		{name: "Empty patch", doc: `{"a": 1}`, patch: `[]`, want: `{"a":1}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := mustParse(t, tt.doc)
+			result, err := ApplyJSONPatch(doc, mustParse(t, tt.patch))
+			if err != nil {
+				t.Fatalf("ApplyJSONPatch() error = %v", err)
+			}
+			got, _ := result.Format("", "")
+			if string(got) != tt.want {
+				t.Errorf("ApplyJSONPatch() = %s, want %s", got, tt.want)
+			}
+			original, _ := doc.Format("", "")
+			if !mustParse(t, tt.doc).Equal(doc) {
+				t.Errorf("ApplyJSONPatch() modified the document to %s", original)
+			}
+		})
+	}
+}
+
+func TestApplyJSONPatchErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		index int
+		path  string
+	}{
+		{name: "Failed test", doc: `{"baz": "qux"}`, patch: `[{"op": "add", "path": "/a", "value": 1}, {"op": "test", "path": "/baz", "value": "bar"}]`, index: 1, path: "/baz"},
+		{name: "Missing member", doc: `{"foo": "bar"}`, patch: `[{"op": "remove", "path": "/baz"}]`, index: 0, path: "/baz"},
+		{name: "Missing parent", doc: `{"foo": "bar"}`, patch: `[{"op": "add", "path": "/baz/bat", "value": "qux"}]`, index: 0, path: "/baz/bat"},
+		{name: "Index out of bounds", doc: `[1, 2]`, patch: `[{"op": "add", "path": "/3", "value": 3}]`, index: 0, path: "/3"},
+		{name: "Leading zero index", doc: `[1, 2]`, patch: `[{"op": "replace", "path": "/01", "value": 3}]`, index: 0, path: "/01"},
+		{name: "Missing value", doc: `{}`, patch: `[{"op": "add", "path": "/a"}]`, index: 0, path: "/a"},
+		{name: "Unknown operation", doc: `{}`, patch: `[{"op": "invert", "path": "/a"}]`, index: 0, path: "/a"},
+		{name: "Move into child", doc: `{"a": {"b": 1}}`, patch: `[{"op": "move", "from": "/a", "path": "/a/b/c"}]`, index: 0, path: "/a/b/c"},
+		{name: "Invalid operation", doc: `{}`, patch: `[1]`, index: 0, path: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ApplyJSONPatch(mustParse(t, tt.doc), mustParse(t, tt.patch))
+			var opErr *OperationError
+			if !errors.As(err, &opErr) {
+				t.Fatalf("ApplyJSONPatch() error = %v, want OperationError", err)
+			}
+			if opErr.Index != tt.index || opErr.Path != tt.path {
+				t.Errorf("ApplyJSONPatch() error at %d %q, want %d %q", opErr.Index, opErr.Path, tt.index, tt.path)
+			}
+		})
+	}
+
+	_, err := ApplyJSONPatch(mustParse(t, `{"a": 1}`), mustParse(t, `[{"op": "test", "path": "/a", "value": 2}]`))
+	if !errors.Is(err, ErrTestFailed) {
+		t.Errorf("ApplyJSONPatch() error = %v, want ErrTestFailed", err)
+	}
+	if _, err := ApplyJSONPatch(mustParse(t, `{}`), mustParse(t, `{}`)); err == nil {
+		t.Errorf("ApplyJSONPatch() expected error for a patch that is not an array")
+	}
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	// Test cases from RFC 7386 appendix A
+	tests := []struct {
+		doc   string
+		patch string
+		want  string
+	}{
+		{doc: `{"a":"b"}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{doc: `{"a":"b"}`, patch: `{"b":"c"}`, want: `{"a":"b","b":"c"}`},
+		{doc: `{"a":"b"}`, patch: `{"a":null}`, want: `{}`},
+		{doc: `{"a":"b","b":"c"}`, patch: `{"a":null}`, want: `{"b":"c"}`},
+		{doc: `{"a":["b"]}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{doc: `{"a":"c"}`, patch: `{"a":["b"]}`, want: `{"a":["b"]}`},
+		{doc: `{"a":{"b":"c"}}`, patch: `{"a":{"b":"d","c":null}}`, want: `{"a":{"b":"d"}}`},
+		{doc: `{"a":[{"b":"c"}]}`, patch: `{"a":[1]}`, want: `{"a":[1]}`},
+		{doc: `["a","b"]`, patch: `["c","d"]`, want: `["c","d"]`},
+		{doc: `{"a":"b"}`, patch: `["c"]`, want: `["c"]`},
+		{doc: `{"a":"foo"}`, patch: `null`, want: `null`},
+		{doc: `{"a":"foo"}`, patch: `"bar"`, want: `"bar"`},
+		{doc: `{"e":null}`, patch: `{"a":1}`, want: `{"e":null,"a":1}`},
+		{doc: `[1,2]`, patch: `{"a":"b","c":null}`, want: `{"a":"b"}`},
+		{doc: `{}`, patch: `{"a":{"bb":{"ccc":null}}}`, want: `{"a":{"bb":{}}}`},
+	}
+	for _, tt := range tests {
+		doc := mustParse(t, tt.doc)
+		got, _ := ApplyMergePatch(doc, mustParse(t, tt.patch)).Format("", "")
+		if string(got) != tt.want {
+			t.Errorf("ApplyMergePatch(%s, %s) = %s, want %s", tt.doc, tt.patch, got, tt.want)
+		}
+		if !mustParse(t, tt.doc).Equal(doc) {
+			t.Errorf("ApplyMergePatch(%s, %s) modified the document", tt.doc, tt.patch)
+		}
+	}
+}
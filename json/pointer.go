@@ -0,0 +1,37 @@
+package json
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EscapePointerToken escapes a single reference token for use in a JSON Pointer (RFC 6901)
+func EscapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// UnescapePointerToken reverts EscapePointerToken
+func UnescapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
+
+// AppendPointer returns the JSON Pointer addressing the child token of pointer
+func AppendPointer(pointer, token string) string {
+	return pointer + "/" + EscapePointerToken(token)
+}
+
+// ParsePointer splits a JSON Pointer into its unescaped reference tokens.
+// The empty pointer refers to the whole document and returns no tokens.
+func ParsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q: must start with /", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = UnescapePointerToken(token)
+	}
+	return tokens, nil
+}
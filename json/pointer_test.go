@@ -0,0 +1,64 @@
+package json
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPointer(t *testing.T) {
+	tests := []struct {
+		name    string
+		pointer string
+		tokens  []string
+		wantErr bool
+	}{
+		{name: "Whole document", pointer: "", tokens: []string{}},
+		{name: "Simple path", pointer: "/a/0/b", tokens: []string{"a", "0", "b"}},
+		{name: "Escaped tokens", pointer: "/a~1b/c~0d/~01", tokens: []string{"a/b", "c~d", "~1"}},
+		{name: "Empty token", pointer: "/", tokens: []string{""}},
+		{name: "Missing leading slash", pointer: "a/b", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, err := ParsePointer(tt.pointer)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePointer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(tokens, tt.tokens) {
+				t.Errorf("ParsePointer() = %v, want %v", tokens, tt.tokens)
+			}
+			pointer := ""
+			for _, token := range tokens {
+				pointer = AppendPointer(pointer, token)
+			}
+			if pointer != tt.pointer {
+				t.Errorf("AppendPointer() = %q, want %q", pointer, tt.pointer)
+			}
+		})
+	}
+}
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{`{"a": 1, "b": [true, null]}`, `{"b": [true, null], "a": 1}`, true},
+		{`1.0`, `1`, true},
+		{`1e2`, `100`, true},
+		{`12345678901234567890`, `12345678901234567891`, false},
+		{`[1, 2]`, `[2, 1]`, false},
+		{`{"a": 1}`, `{"a": 1, "b": 2}`, false},
+		{`"1"`, `1`, false},
+	}
+	for _, tt := range tests {
+		a, _ := Parse([]byte(tt.a))
+		b, _ := Parse([]byte(tt.b))
+		if got := a.Equal(b); got != tt.want {
+			t.Errorf("Equal(%s, %s) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
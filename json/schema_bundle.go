@@ -0,0 +1,190 @@
+package json
+
+import (
+	"bytes"
+	stdJson "encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// bundleScheme is the URL scheme of the files of a SchemaBundle, relative references are resolved against it
+const bundleScheme = "bundle"
+
+// SchemaBundle is a set of JSON schema files referencing each other with $ref. It implements
+// jsonschema.URLLoader and never accesses the network.
+//
+// A reference is resolved to a bundle file by, in this order,
+//   - its path relative to the referencing file, e.g. common.json#/definitions/address
+//   - the $id declared by a file, e.g. https://example.com/schemas/common.json
+//   - the longest file name the path of an absolute URL ends with
+type SchemaBundle struct {
+
+	// Main is the name of the schema documents are validated against, the first file added if empty
+	Main string
+
+	// files maps slash separated file names to their content
+	files map[string][]byte
+
+	// names holds the file names in the order they were added
+	names []string
+}
+
+// NewSchemaBundle returns an empty bundle
+func NewSchemaBundle() *SchemaBundle {
+	return &SchemaBundle{files: make(map[string][]byte)}
+}
+
+// Add adds a schema file to the bundle, name is a slash separated path like defs/common.json.
+// A file added with the same name replaces the previous one.
+func (b *SchemaBundle) Add(name string, content []byte) {
+	name = cleanName(name)
+	if _, exists := b.files[name]; !exists {
+		b.names = append(b.names, name)
+	}
+	b.files[name] = content
+}
+
+// AddZip adds all .json files of the zip archive to the bundle, named by their path within the archive.
+// Archives expanding beyond MaxZipFileSize per file or MaxZipSize in total are rejected.
+func (b *SchemaBundle) AddZip(data []byte) error {
+	files, err := ReadZipFiles(data, isSchemaFile)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		b.Add(file.Name, file.Content)
+	}
+	return nil
+}
+
+// AddFS adds all regular .json files below dir of fsys to the bundle, named by their path relative to dir
+func (b *SchemaBundle) AddFS(fsys fs.FS, dir string) error {
+	return fs.WalkDir(fsys, dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != dir && strings.HasPrefix(d.Name(), ".") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || !isSchemaFile(p) {
+			return nil
+		}
+		content, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		name := p
+		if dir != "." {
+			name = strings.TrimPrefix(p, dir+"/")
+		}
+		b.Add(name, content)
+		return nil
+	})
+}
+
+// Names returns the names of the files in the order they were added
+func (b *SchemaBundle) Names() []string {
+	return append([]string(nil), b.names...)
+}
+
+// MainName returns the name of the main schema, empty if Main is not set and the bundle is empty
+func (b *SchemaBundle) MainName() string {
+	if b.Main != "" {
+		return cleanName(b.Main)
+	}
+	if len(b.names) == 0 {
+		return ""
+	}
+	return b.names[0]
+}
+
+// Compile compiles the main schema of the bundle using compiler, which is configured to load
+// references from the bundle
+func (b *SchemaBundle) Compile(compiler *jsonschema.Compiler) (*jsonschema.Schema, error) {
+	main := b.MainName()
+	if main == "" {
+		return nil, errors.New("schema bundle is empty")
+	}
+	if _, ok := b.files[main]; !ok {
+		return nil, fmt.Errorf("main schema %s is not part of the bundle", b.Main)
+	}
+
+	compiler.UseLoader(b)
+	return compiler.Compile(bundleURL(main))
+}
+
+// Load returns the bundle file the URL refers to
+func (b *SchemaBundle) Load(location string) (any, error) {
+	name, ok := b.resolve(location)
+	if !ok {
+		return nil, fmt.Errorf("schema %s is not part of the bundle", location)
+	}
+	return jsonschema.UnmarshalJSON(bytes.NewReader(b.files[name]))
+}
+
+// resolve returns the name of the file the URL refers to
+func (b *SchemaBundle) resolve(location string) (string, bool) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return "", false
+	}
+	if u.Scheme == bundleScheme {
+		name := strings.TrimPrefix(u.Path, "/")
+		_, ok := b.files[name]
+		return name, ok
+	}
+
+	for _, name := range b.names {
+		if b.id(name) == location {
+			return name, true
+		}
+	}
+
+	found := ""
+	for _, name := range b.names {
+		if (u.Path == "/"+name || strings.HasSuffix(u.Path, "/"+name)) && len(name) > len(found) {
+			found = name
+		}
+	}
+	return found, found != ""
+}
+
+// id returns the $id, or id of older drafts, declared by the file, empty if it declares none
+func (b *SchemaBundle) id(name string) string {
+	var doc struct {
+		ID       string `json:"$id"`
+		LegacyID string `json:"id"`
+	}
+	if err := stdJson.Unmarshal(b.files[name], &doc); err != nil {
+		return ""
+	}
+	id := doc.ID
+	if id == "" {
+		id = doc.LegacyID
+	}
+	return strings.TrimSuffix(id, "#")
+}
+
+// bundleURL returns the URL of a bundle file
+func bundleURL(name string) string {
+	return (&url.URL{Scheme: bundleScheme, Path: "/" + name}).String()
+}
+
+// cleanName normalizes a file name to a slash separated path without leading slash
+func cleanName(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+strings.ReplaceAll(name, "\\", "/")), "/")
+}
+
+// isSchemaFile reports whether the file name has the .json extension
+func isSchemaFile(name string) bool {
+	return strings.EqualFold(path.Ext(name), ".json")
+}
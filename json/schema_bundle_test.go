@@ -0,0 +1,144 @@
+package json
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+const (
+	bundleMain = `{
+		"type": "object",
+		"properties": {
+			"home": { "$ref": "defs/common.json#/definitions/address" },
+			"work": { "$ref": "https://example.com/schemas/work.json" }
+		}
+	}`
+	bundleCommon = `{
+		"definitions": {
+			"address": { "type": "object", "required": ["city"], "properties": { "zip": { "$ref": "../zip.json" } } }
+		}
+	}`
+	bundleZip  = `{ "type": "string", "pattern": "^[0-9]{5}$" }`
+	bundleWork = `{ "$id": "https://example.com/schemas/work.json", "type": "object", "required": ["company"] }`
+)
+
+func TestSchemaBundle(t *testing.T) {
+	bundle := NewSchemaBundle()
+	bundle.Add("main.json", []byte(bundleMain))
+	bundle.Add("defs/common.json", []byte(bundleCommon))
+	bundle.Add("zip.json", []byte(bundleZip))
+	bundle.Add("/work.json", []byte(bundleWork))
+
+	if !reflect.DeepEqual(bundle.Names(), []string{"main.json", "defs/common.json", "zip.json", "work.json"}) {
+		t.Errorf("Names() = %v", bundle.Names())
+	}
+
+	schema, err := bundle.Compile(jsonschema.NewCompiler())
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		doc   string
+		valid bool
+	}{
+		{name: "Valid", doc: `{"home": {"city": "x", "zip": "12345"}, "work": {"company": "y"}}`, valid: true},
+		{name: "Relative reference", doc: `{"home": {}}`},
+		{name: "Reference to parent directory", doc: `{"home": {"city": "x", "zip": "1"}}`},
+		{name: "Reference by $id", doc: `{"work": {}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := jsonschema.UnmarshalJSON(bytes.NewReader([]byte(tt.doc)))
+			if err != nil {
+				t.Fatalf("UnmarshalJSON() error = %v", err)
+			}
+			if err := schema.Validate(doc); (err == nil) != tt.valid {
+				t.Errorf("Validate() error = %v, valid %v", err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestSchemaBundleMissingReference(t *testing.T) {
+	bundle := NewSchemaBundle()
+	bundle.Add("main.json", []byte(bundleMain))
+	if _, err := bundle.Compile(jsonschema.NewCompiler()); err == nil {
+		t.Errorf("Compile() expected error for a reference missing in the bundle")
+	}
+
+	bundle.Main = "other.json"
+	if _, err := bundle.Compile(jsonschema.NewCompiler()); err == nil {
+		t.Errorf("Compile() expected error for a main schema missing in the bundle")
+	}
+
+	if _, err := NewSchemaBundle().Compile(jsonschema.NewCompiler()); err == nil {
+		t.Errorf("Compile() expected error for an empty bundle")
+	}
+}
+
+func TestSchemaBundleAddZip(t *testing.T) {
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	files := []struct{ name, content string }{
+		{"schemas/main.json", bundleMain},
+		{"schemas/README.md", "not a schema"},
+		{"schemas/defs/common.json", bundleCommon},
+		{"schemas/zip.json", bundleZip},
+		{"schemas/work.json", bundleWork},
+	}
+	for _, file := range files {
+		w, err := writer.Create(file.name)
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		_, _ = w.Write([]byte(file.content))
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	bundle := NewSchemaBundle()
+	if err := bundle.AddZip(buf.Bytes()); err != nil {
+		t.Fatalf("AddZip() error = %v", err)
+	}
+	if !reflect.DeepEqual(bundle.Names(), []string{"schemas/main.json", "schemas/defs/common.json", "schemas/zip.json", "schemas/work.json"}) {
+		t.Errorf("Names() = %v", bundle.Names())
+	}
+	if _, err := bundle.Compile(jsonschema.NewCompiler()); err != nil {
+		t.Errorf("Compile() error = %v", err)
+	}
+
+	if err := NewSchemaBundle().AddZip([]byte("no zip")); err == nil {
+		t.Errorf("AddZip() expected error for invalid archive")
+	}
+}
+
+func TestSchemaBundleAddFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"root/main.json":        {Data: []byte(bundleMain)},
+		"root/defs/common.json": {Data: []byte(bundleCommon)},
+		"root/zip.json":         {Data: []byte(bundleZip)},
+		"root/work.json":        {Data: []byte(bundleWork)},
+		"root/.git/config.json": {Data: []byte(`{}`)},
+		"other.json":            {Data: []byte(`{}`)},
+	}
+
+	bundle := NewSchemaBundle()
+	if err := bundle.AddFS(fsys, "root"); err != nil {
+		t.Fatalf("AddFS() error = %v", err)
+	}
+	if len(bundle.Names()) != 4 {
+		t.Errorf("Names() = %v, want the 4 schema files below root", bundle.Names())
+	}
+	bundle.Main = "main.json"
+	if _, err := bundle.Compile(jsonschema.NewCompiler()); err != nil {
+		t.Errorf("Compile() error = %v", err)
+	}
+}
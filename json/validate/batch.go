@@ -0,0 +1,204 @@
+package validate
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/sascha-andres/jsonedit/json"
+)
+
+type (
+	// Document is a named JSON document validated as part of a batch
+	Document struct {
+
+		// Name identifies the document in the result, e.g. its file name
+		Name string
+
+		// Content is the JSON document
+		Content []byte
+	}
+
+	// DocumentResult is the validation result of a single document of a batch
+	DocumentResult struct {
+
+		// Name identifies the document
+		Name string `json:"name"`
+
+		// Valid is true when the document satisfies the schema
+		Valid bool `json:"valid"`
+
+		// Error is the message of a failure other than schema violations, e.g. an invalid document
+		Error string `json:"error,omitempty"`
+
+		// Violations lists the failed schema keywords, empty if the document is valid
+		Violations []Violation `json:"violations"`
+	}
+
+	// BatchResult summarizes the validation of many documents against one schema
+	BatchResult struct {
+
+		// Total is the number of validated documents
+		Total int `json:"total"`
+
+		// Passed is the number of documents satisfying the schema
+		Passed int `json:"passed"`
+
+		// Failed is the number of documents not satisfying the schema or failing to be validated
+		Failed int `json:"failed"`
+
+		// Documents lists the result of every document in the order they were given
+		Documents []DocumentResult `json:"documents"`
+	}
+)
+
+// ValidateBatch validates every document against the schema of the validator
+func (v *JSONValidator) ValidateBatch(documents []Document) *BatchResult {
+	result := &BatchResult{Total: len(documents), Documents: make([]DocumentResult, 0, len(documents))}
+	for _, document := range documents {
+		documentResult := DocumentResult{Name: document.Name, Valid: true, Violations: []Violation{}}
+		if err := v.ValidateDocument(document.Content); err != nil {
+			documentResult.Valid = false
+			var validationErr *ValidationError
+			if errors.As(err, &validationErr) {
+				documentResult.Violations = validationErr.Violations
+			} else {
+				documentResult.Error = err.Error()
+			}
+		}
+		if documentResult.Valid {
+			result.Passed++
+		} else {
+			result.Failed++
+		}
+		result.Documents = append(result.Documents, documentResult)
+	}
+	v.logger.Debug("validated batch", "total", result.Total, "passed", result.Passed, "failed", result.Failed)
+	return result
+}
+
+// ReadNDJSON splits newline delimited JSON into documents named name:line, blank lines are skipped
+func ReadNDJSON(name string, data []byte) ([]Document, error) {
+	documents := make([]Document, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	line := 0
+	for scanner.Scan() {
+		line++
+		content := bytes.TrimSpace(scanner.Bytes())
+		if len(content) == 0 {
+			continue
+		}
+		documents = append(documents, Document{
+			Name:    fmt.Sprintf("%s:%d", name, line),
+			Content: append([]byte(nil), content...),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return documents, nil
+}
+
+// ReadZip returns the documents of the zip archive named by their path within the archive.
+// .json files are a single document, .ndjson and .jsonl files are split by ReadNDJSON. Archives
+// decompressing beyond json.MaxZipFileSize per file or json.MaxZipSize in total are rejected.
+func ReadZip(data []byte) ([]Document, error) {
+	files, err := json.ReadZipFiles(data, func(name string) bool {
+		return strings.ToLower(path.Ext(name)) == ".json" || IsNDJSON(name)
+	})
+	if err != nil {
+		return nil, err
+	}
+	documents := make([]Document, 0, len(files))
+	for _, file := range files {
+		if IsNDJSON(file.Name) {
+			lines, err := ReadNDJSON(file.Name, file.Content)
+			if err != nil {
+				return nil, err
+			}
+			documents = append(documents, lines...)
+			continue
+		}
+		documents = append(documents, Document{Name: file.Name, Content: file.Content})
+	}
+	return documents, nil
+}
+
+// IsNDJSON reports whether the file name has the extension of newline delimited JSON, .ndjson or .jsonl
+func IsNDJSON(name string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	return ext == ".ndjson" || ext == ".jsonl"
+}
+
+type (
+	// junitTestSuites is the root element of a JUnit XML report
+	junitTestSuites struct {
+		XMLName  xml.Name         `xml:"testsuites"`
+		Tests    int              `xml:"tests,attr"`
+		Failures int              `xml:"failures,attr"`
+		Errors   int              `xml:"errors,attr"`
+		Suites   []junitTestSuite `xml:"testsuite"`
+	}
+
+	// junitTestSuite groups the test cases of a JUnit XML report
+	junitTestSuite struct {
+		Name     string          `xml:"name,attr"`
+		Tests    int             `xml:"tests,attr"`
+		Failures int             `xml:"failures,attr"`
+		Errors   int             `xml:"errors,attr"`
+		Cases    []junitTestCase `xml:"testcase"`
+	}
+
+	// junitTestCase is a single validated document
+	junitTestCase struct {
+		Name      string        `xml:"name,attr"`
+		ClassName string        `xml:"classname,attr"`
+		Failure   *junitMessage `xml:"failure,omitempty"`
+		Error     *junitMessage `xml:"error,omitempty"`
+	}
+
+	// junitMessage describes a failure or error of a test case
+	junitMessage struct {
+		Message string `xml:"message,attr"`
+		Type    string `xml:"type,attr"`
+		Text    string `xml:",chardata"`
+	}
+)
+
+// JUnit writes the result as JUnit XML report with one test case per document, suite names the test suite.
+// Documents violating the schema are reported as failures, documents that could not be validated as errors.
+func (r *BatchResult) JUnit(suite string) ([]byte, error) {
+	testSuite := junitTestSuite{Name: suite, Tests: r.Total, Cases: make([]junitTestCase, 0, len(r.Documents))}
+	for _, document := range r.Documents {
+		testCase := junitTestCase{Name: document.Name, ClassName: suite}
+		switch {
+		case document.Error != "":
+			testSuite.Errors++
+			testCase.Error = &junitMessage{Message: document.Error, Type: "error", Text: document.Error}
+		case !document.Valid:
+			testSuite.Failures++
+			testCase.Failure = &junitMessage{
+				Message: fmt.Sprintf("%d violation(s)", len(document.Violations)),
+				Type:    "schema",
+				Text:    (&ValidationError{Violations: document.Violations}).Error(),
+			}
+		}
+		testSuite.Cases = append(testSuite.Cases, testCase)
+	}
+
+	content, err := xml.MarshalIndent(junitTestSuites{
+		Tests:    testSuite.Tests,
+		Failures: testSuite.Failures,
+		Errors:   testSuite.Errors,
+		Suites:   []junitTestSuite{testSuite},
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(content, '\n')...), nil
+}
@@ -0,0 +1,124 @@
+package validate
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sascha-andres/jsonedit/json"
+)
+
+const batchSchema = `{
+	"type": "object",
+	"properties": { "name": { "type": "string" } },
+	"required": ["name"]
+}`
+
+func TestValidateBatch(t *testing.T) {
+	validator, err := NewJSONValidator(WithJSONSchema([]byte(batchSchema)))
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+
+	result := validator.ValidateBatch([]Document{
+		{Name: "valid.json", Content: []byte(`{"name": "a"}`)},
+		{Name: "missing.json", Content: []byte(`{}`)},
+		{Name: "broken.json", Content: []byte(`{`)},
+	})
+	if result.Total != 3 || result.Passed != 1 || result.Failed != 2 {
+		t.Errorf("ValidateBatch() = %d total, %d passed, %d failed, want 3, 1, 2", result.Total, result.Passed, result.Failed)
+	}
+	if !result.Documents[0].Valid || len(result.Documents[0].Violations) != 0 {
+		t.Errorf("Documents[0] = %+v, want valid", result.Documents[0])
+	}
+	if result.Documents[1].Valid || len(result.Documents[1].Violations) != 1 || result.Documents[1].Violations[0].Keyword != "required" {
+		t.Errorf("Documents[1] = %+v, want required violation", result.Documents[1])
+	}
+	if result.Documents[2].Valid || result.Documents[2].Error == "" {
+		t.Errorf("Documents[2] = %+v, want error for invalid JSON", result.Documents[2])
+	}
+
+	report, err := result.JUnit("schema.json")
+	if err != nil {
+		t.Fatalf("JUnit() error = %v", err)
+	}
+	var suites junitTestSuites
+	if err := xml.Unmarshal(report, &suites); err != nil {
+		t.Fatalf("JUnit() returned invalid XML: %v\n%s", err, report)
+	}
+	if suites.Tests != 3 || suites.Failures != 1 || suites.Errors != 1 || len(suites.Suites) != 1 || len(suites.Suites[0].Cases) != 3 {
+		t.Errorf("JUnit() = %+v, want 3 tests, 1 failure and 1 error", suites)
+	}
+	if failure := suites.Suites[0].Cases[1].Failure; failure == nil || !strings.Contains(failure.Text, "[required]") {
+		t.Errorf("JUnit() failure = %+v, want required violation", failure)
+	}
+}
+
+func TestReadNDJSON(t *testing.T) {
+	documents, err := ReadNDJSON("fixtures.ndjson", []byte("{\"name\": \"a\"}\n\n  {}  \r\n"))
+	if err != nil {
+		t.Fatalf("ReadNDJSON() error = %v", err)
+	}
+	if len(documents) != 2 {
+		t.Fatalf("ReadNDJSON() = %d documents, want 2", len(documents))
+	}
+	if documents[0].Name != "fixtures.ndjson:1" || documents[1].Name != "fixtures.ndjson:3" || string(documents[1].Content) != "{}" {
+		t.Errorf("ReadNDJSON() = %+v", documents)
+	}
+}
+
+func TestReadZip(t *testing.T) {
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	files := []struct{ name, content string }{
+		{"fixtures/a.json", `{"name": "a"}`},
+		{"fixtures/notes.txt", "skipped"},
+		{"fixtures/more.jsonl", "{}\n{}\n"},
+	}
+	for _, file := range files {
+		w, err := writer.Create(file.name)
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		_, _ = w.Write([]byte(file.content))
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	documents, err := ReadZip(buf.Bytes())
+	if err != nil {
+		t.Fatalf("ReadZip() error = %v", err)
+	}
+	names := make([]string, len(documents))
+	for i, document := range documents {
+		names[i] = document.Name
+	}
+	if strings.Join(names, ",") != "fixtures/a.json,fixtures/more.jsonl:1,fixtures/more.jsonl:2" {
+		t.Errorf("ReadZip() names = %v", names)
+	}
+
+	if _, err := ReadZip([]byte("no zip")); err == nil {
+		t.Errorf("ReadZip() expected error for invalid archive")
+	}
+}
+
+func TestReadZipTooLarge(t *testing.T) {
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	w, err := writer.Create("fixtures/big.json")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	_, _ = w.Write(make([]byte, json.MaxZipFileSize+1))
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if _, err := ReadZip(buf.Bytes()); !errors.Is(err, json.ErrZipTooLarge) {
+		t.Errorf("ReadZip() error = %v, want %v", err, json.ErrZipTooLarge)
+	}
+}
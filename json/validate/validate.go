@@ -1,7 +1,8 @@
 package validate
 
 import (
-	stdJson "encoding/json"
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -13,11 +14,69 @@ import (
 	"github.com/sascha-andres/jsonedit/json"
 )
 
+// Violation is a single failed schema keyword
+type Violation struct {
+
+	// InstanceLocation is the JSON Pointer of the failing value in the document
+	InstanceLocation string `json:"instanceLocation"`
+
+	// KeywordLocation is the JSON Pointer of the failing keyword in the schema, following references
+	KeywordLocation string `json:"keywordLocation"`
+
+	// AbsoluteKeywordLocation is the absolute URL of the failing keyword, only set if reached through a reference
+	AbsoluteKeywordLocation string `json:"absoluteKeywordLocation,omitempty"`
+
+	// Keyword is the name of the failing keyword, e.g. required or type
+	Keyword string `json:"keyword"`
+
+	// Message describes the violation
+	Message string `json:"message"`
+}
+
+// ValidationError is returned by Validate if the document does not satisfy the schema
+type ValidationError struct {
+
+	// Violations lists the failed keywords in the order reported by the schema
+	Violations []Violation
+}
+
+// Error lists all violations, one per line
+func (e *ValidationError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("jsonschema validation failed")
+	for _, violation := range e.Violations {
+		location := violation.InstanceLocation
+		if location == "" {
+			location = "/"
+		}
+		fmt.Fprintf(&sb, "\n- at '%s' [%s]: %s", location, violation.Keyword, violation.Message)
+	}
+	return sb.String()
+}
+
 type JSONValidator struct {
 	compiler *jsonschema.Compiler
 	schema   *jsonschema.Schema
 	document io.ReadCloser
 
+	// source compiles the schema set by WithJSONSchema or WithJSONSchemaBundle using the given compiler
+	source func(*jsonschema.Compiler) (*jsonschema.Schema, error)
+
+	// draft is the draft used for schemas without $schema, the library default if nil
+	draft *jsonschema.Draft
+
+	// assertFormat indicates whether format keywords are assertions instead of annotations
+	assertFormat bool
+
+	// assertContent indicates whether contentEncoding, contentMediaType and contentSchema are asserted
+	assertContent bool
+
+	// formats holds the custom format checkers
+	formats []*jsonschema.Format
+
+	// deferCompile indicates whether compiling the schema is left to NewJSONValidator
+	deferCompile bool
+
 	logger *slog.Logger
 }
 
@@ -45,46 +104,189 @@ func WithLogger(logger *slog.Logger) JsonValidatorOption {
 // Returns an error if schema compilation fails.
 func WithJSONSchema(jsonSchema []byte) JsonValidatorOption {
 	return func(v *JSONValidator) error {
-		// Initialize the compiler and add the schema file
-		compiler := jsonschema.NewCompiler()
-		compiler.UseLoader(json.InMemoryLoader{Doc: jsonSchema})
+		v.source = func(compiler *jsonschema.Compiler) (*jsonschema.Schema, error) {
+			compiler.UseLoader(json.InMemoryLoader{Doc: jsonSchema})
+			return compiler.Compile("//")
+		}
+		return v.compile()
+	}
+}
+
+// WithJSONSchemaBundle sets the main schema of a bundle of schema files for the JSONValidator.
+// References between the files of the bundle are resolved without network access.
+// Returns an error if schema compilation fails.
+func WithJSONSchemaBundle(bundle *json.SchemaBundle) JsonValidatorOption {
+	return func(v *JSONValidator) error {
+		v.source = bundle.Compile
+		return v.compile()
+	}
+}
 
-		schema, err := compiler.Compile("//")
+// WithDefaultDraft sets the draft used for schemas that do not declare one with $schema,
+// see ParseDraft for the accepted names. An empty name keeps the library default.
+func WithDefaultDraft(name string) JsonValidatorOption {
+	return func(v *JSONValidator) error {
+		draft, err := ParseDraft(name)
 		if err != nil {
-			return fmt.Errorf("failed to compile schema: %w", err)
+			return err
 		}
-		v.schema = schema
-		v.compiler = compiler
+		v.draft = draft
 		return nil
 	}
 }
 
-// NewJSONValidator creates a new instance to validate a JSON file against a document
+// WithFormatAssertion sets whether format keywords are validated. Without it formats are only
+// annotations for draft 2019-09 and later.
+func WithFormatAssertion(assert bool) JsonValidatorOption {
+	return func(v *JSONValidator) error {
+		v.assertFormat = assert
+		return nil
+	}
+}
+
+// WithContentAssertion sets whether the keywords contentEncoding, contentMediaType and contentSchema are validated
+func WithContentAssertion(assert bool) JsonValidatorOption {
+	return func(v *JSONValidator) error {
+		v.assertContent = assert
+		return nil
+	}
+}
+
+// WithFormat registers a custom format checker, validate returns an error if the value is not of the
+// format. Numbers are passed as encoding/json.Number, values of other types than the ones checked
+// should be accepted. The checker replaces a built-in format of the same name, except regex, and
+// only applies if formats are asserted.
+func WithFormat(name string, validate func(v any) error) JsonValidatorOption {
+	return func(v *JSONValidator) error {
+		v.formats = append(v.formats, &jsonschema.Format{Name: name, Validate: validate})
+		return nil
+	}
+}
+
+// ParseDraft returns the draft of the name, which is one of draft-04, draft-06, draft-07, 2019-09
+// or 2020-12. The short forms 4, 6, 7, 2019 and 2020 are accepted as well, an empty name returns nil.
+func ParseDraft(name string) (*jsonschema.Draft, error) {
+	switch strings.TrimPrefix(strings.ToLower(name), "draft-") {
+	case "":
+		return nil, nil
+	case "04", "4":
+		return jsonschema.Draft4, nil
+	case "06", "6":
+		return jsonschema.Draft6, nil
+	case "07", "7":
+		return jsonschema.Draft7, nil
+	case "2019-09", "2019":
+		return jsonschema.Draft2019, nil
+	case "2020-12", "2020":
+		return jsonschema.Draft2020, nil
+	}
+	return nil, fmt.Errorf("unknown JSON schema draft %q", name)
+}
+
+// compile compiles the schema with a compiler configured by the options applied so far.
+// Within NewJSONValidator compiling is deferred until all options are applied.
+func (v *JSONValidator) compile() error {
+	if v.deferCompile {
+		return nil
+	}
+	compiler := jsonschema.NewCompiler()
+	if v.draft != nil {
+		compiler.DefaultDraft(v.draft)
+	}
+	if v.assertFormat {
+		compiler.AssertFormat()
+	}
+	if v.assertContent {
+		compiler.AssertContent()
+	}
+	for _, format := range v.formats {
+		compiler.RegisterFormat(format)
+	}
+
+	schema, err := v.source(compiler)
+	if err != nil {
+		return fmt.Errorf("failed to compile schema: %w", err)
+	}
+	v.schema = schema
+	v.compiler = compiler
+	return nil
+}
+
+// NewJSONValidator creates a new instance to validate a JSON file against a document.
+// The schema is compiled with the draft, assertion and format options regardless of their order.
 func NewJSONValidator(opts ...JsonValidatorOption) (*JSONValidator, error) {
-	v := &JSONValidator{logger: slog.Default()}
+	v := &JSONValidator{logger: slog.Default(), deferCompile: true}
 	for i := range opts {
 		err := opts[i](v)
 		if err != nil {
 			return nil, err
 		}
 	}
+
+	// Compile once all options are known, so options given after the schema apply as well
+	v.deferCompile = false
+	if v.source != nil {
+		if err := v.compile(); err != nil {
+			return nil, err
+		}
+	}
 	return v, nil
 }
 
 // Validate checks the JSON document against the schema and returns an error if validation fails.
+// A document not satisfying the schema results in a *ValidationError listing the violations.
 func (v *JSONValidator) Validate() error {
 	// Read the document content
 	data, err := ioutil.ReadAll(v.document)
 	if err != nil {
 		return fmt.Errorf("failed to read document: %w", err)
 	}
+	return v.ValidateDocument(data)
+}
+
+// ValidateDocument checks data against the schema of the validator, ignoring the document set by
+// WithJSONDocument, so one compiled schema can validate many documents. A document not satisfying
+// the schema results in a *ValidationError listing the violations.
+func (v *JSONValidator) ValidateDocument(data []byte) error {
+	if v.schema == nil {
+		return errors.New("no JSON schema set")
+	}
 
-	// Parse the JSON document
-	var jsonData interface{}
-	if err := stdJson.Unmarshal(data, &jsonData); err != nil {
+	// Parse the JSON document, numbers are kept as json.Number so large and precise values are compared exactly
+	jsonData, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
 		return fmt.Errorf("invalid JSON document: %w", err)
 	}
 
 	// Validate the parsed JSON against the schema
-	return v.schema.Validate(jsonData)
+	err = v.schema.Validate(jsonData)
+	var schemaErr *jsonschema.ValidationError
+	if errors.As(err, &schemaErr) {
+		return &ValidationError{Violations: violations(schemaErr.DetailedOutput(), nil)}
+	}
+	return err
+}
+
+// violations collects the leaves of the detailed output, which are the keywords that failed
+func violations(unit *jsonschema.OutputUnit, result []Violation) []Violation {
+	if len(unit.Errors) == 0 {
+		if unit.Error == nil {
+			return result
+		}
+		keyword := ""
+		if tokens, err := json.ParsePointer(unit.KeywordLocation); err == nil && len(tokens) > 0 {
+			keyword = tokens[len(tokens)-1]
+		}
+		return append(result, Violation{
+			InstanceLocation:        unit.InstanceLocation,
+			KeywordLocation:         unit.KeywordLocation,
+			AbsoluteKeywordLocation: unit.AbsoluteKeywordLocation,
+			Keyword:                 keyword,
+			Message:                 unit.Error.String(),
+		})
+	}
+	for i := range unit.Errors {
+		result = violations(&unit.Errors[i], result)
+	}
+	return result
 }
@@ -1,11 +1,15 @@
 package validate
 
 import (
+	stdJson "encoding/json"
+	"errors"
 	"io"
 	"log/slog"
 	"os"
 	"strings"
 	"testing"
+
+	"github.com/sascha-andres/jsonedit/json"
 )
 
 func TestNewJSONValidator(t *testing.T) {
@@ -409,3 +413,125 @@ func TestWithJSONSchema(t *testing.T) {
 		})
 	}
 }
+
+func TestJSONValidator_Violations(t *testing.T) {
+	schema := `{
+		"type": "object",
+		"properties": {
+			"name": { "type": "string" },
+			"tags": { "type": "array", "items": { "$ref": "#/$defs/tag" } }
+		},
+		"required": ["name"],
+		"$defs": { "tag": { "type": "string", "maxLength": 3 } }
+	}`
+	validator, err := NewJSONValidator(
+		WithJSONSchema([]byte(schema)),
+		WithJSONDocument([]byte(`{"tags": ["ok", "toolong", 1]}`)),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+
+	err = validator.Validate()
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Validate() error = %v, want *ValidationError", err)
+	}
+	want := []Violation{
+		{InstanceLocation: "", KeywordLocation: "/required", Keyword: "required"},
+		{InstanceLocation: "/tags/1", KeywordLocation: "/properties/tags/items/$ref/maxLength", Keyword: "maxLength"},
+		{InstanceLocation: "/tags/2", KeywordLocation: "/properties/tags/items/$ref/type", Keyword: "type"},
+	}
+	if len(validationErr.Violations) != len(want) {
+		t.Fatalf("Violations = %+v, want %+v", validationErr.Violations, want)
+	}
+	for i, violation := range validationErr.Violations {
+		if violation.InstanceLocation != want[i].InstanceLocation || violation.KeywordLocation != want[i].KeywordLocation || violation.Keyword != want[i].Keyword {
+			t.Errorf("Violations[%d] = %+v, want %+v", i, violation, want[i])
+		}
+		if violation.Message == "" {
+			t.Errorf("Violations[%d] has no message", i)
+		}
+	}
+}
+
+func TestWithJSONSchemaBundle(t *testing.T) {
+	bundle := json.NewSchemaBundle()
+	bundle.Add("main.json", []byte(`{"type": "object", "properties": {"address": {"$ref": "common.json#/definitions/address"}}}`))
+	bundle.Add("common.json", []byte(`{"definitions": {"address": {"type": "object", "required": ["city"]}}}`))
+
+	validator, err := NewJSONValidator(
+		WithJSONSchemaBundle(bundle),
+		WithJSONDocument([]byte(`{"address": {}}`)),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	err = validator.Validate()
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Validate() error = %v, want *ValidationError", err)
+	}
+	if len(validationErr.Violations) != 1 || validationErr.Violations[0].InstanceLocation != "/address" || validationErr.Violations[0].Keyword != "required" {
+		t.Errorf("Violations = %+v, want required at /address", validationErr.Violations)
+	}
+	if !strings.HasPrefix(validationErr.Violations[0].AbsoluteKeywordLocation, "bundle:///common.json#") {
+		t.Errorf("AbsoluteKeywordLocation = %s, want location in common.json", validationErr.Violations[0].AbsoluteKeywordLocation)
+	}
+
+	missing := json.NewSchemaBundle()
+	missing.Add("main.json", []byte(`{"$ref": "common.json"}`))
+	if _, err := NewJSONValidator(WithJSONSchemaBundle(missing)); err == nil {
+		t.Errorf("WithJSONSchemaBundle() expected error for a reference missing in the bundle")
+	}
+}
+
+func TestCompilerOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		document string
+		opts     []JsonValidatorOption
+		valid    bool
+		wantErr  bool
+	}{
+		{name: "Format is annotation by default", schema: `{"format": "email"}`, document: `"x"`, valid: true},
+		{name: "Format assertion", schema: `{"format": "email"}`, document: `"x"`, opts: []JsonValidatorOption{WithFormatAssertion(true)}},
+		{name: "Content is annotation by default", schema: `{"contentEncoding": "base64"}`, document: `"!!"`, valid: true},
+		{name: "Content assertion", schema: `{"contentEncoding": "base64"}`, document: `"!!"`, opts: []JsonValidatorOption{WithContentAssertion(true)}},
+		{name: "Draft-04 keyword fails with default draft", schema: `{"minimum": 5, "exclusiveMinimum": true}`, document: `5`, wantErr: true},
+		{name: "Default draft", schema: `{"minimum": 5, "exclusiveMinimum": true}`, document: `5`, opts: []JsonValidatorOption{WithDefaultDraft("draft-04")}},
+		{name: "Declared draft wins", schema: `{"$schema": "http://json-schema.org/draft-04/schema#", "minimum": 5, "exclusiveMinimum": true}`, document: `6`, opts: []JsonValidatorOption{WithDefaultDraft("2020-12")}, valid: true},
+		{name: "Unknown draft", schema: `{}`, document: `1`, opts: []JsonValidatorOption{WithDefaultDraft("draft-99")}, wantErr: true},
+		{name: "Integer above 2^53", schema: `{"maximum": 9007199254740992}`, document: `9007199254740993`},
+		{name: "Large constant", schema: `{"const": 12345678901234567890}`, document: `12345678901234567891`},
+		{name: "Exact decimal multiple", schema: `{"multipleOf": 0.1}`, document: `0.3`, valid: true},
+		{
+			name:     "Custom format",
+			schema:   `{"format": "even"}`,
+			document: `3`,
+			opts: []JsonValidatorOption{WithFormatAssertion(true), WithFormat("even", func(v any) error {
+				if n, ok := v.(stdJson.Number); ok && strings.ContainsAny(string(n[len(n)-1:]), "13579") {
+					return errors.New("odd number")
+				}
+				return nil
+			})},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The schema is given first, options after it still apply
+			validator, err := NewJSONValidator(append([]JsonValidatorOption{WithJSONSchema([]byte(tt.schema))}, tt.opts...)...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewJSONValidator() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			err = validator.ValidateDocument([]byte(tt.document))
+			if (err == nil) != tt.valid {
+				t.Errorf("ValidateDocument() error = %v, valid %v", err, tt.valid)
+			}
+		})
+	}
+}
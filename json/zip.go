@@ -0,0 +1,72 @@
+package json
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Limits of the decompressed content read from zip archives, so a small archive cannot expand to
+// files exhausting the memory
+const (
+	// MaxZipFileSize is the largest decompressed size of a single file of a zip archive
+	MaxZipFileSize = 32 << 20
+
+	// MaxZipSize is the largest decompressed size of all files read from a zip archive
+	MaxZipSize = 128 << 20
+)
+
+// ErrZipTooLarge is returned when the files of a zip archive exceed MaxZipFileSize or MaxZipSize
+var ErrZipTooLarge = errors.New("zip archive is too large when decompressed")
+
+// ZipFile is a file read from a zip archive
+type ZipFile struct {
+
+	// Name is the slash separated path of the file within the archive
+	Name string
+
+	// Content is the decompressed content of the file
+	Content []byte
+}
+
+// ReadZipFiles returns the files of the zip archive that include accepts, in the order of the
+// archive. Directories and macOS resource forks are skipped. Files larger than MaxZipFileSize or
+// all files together larger than MaxZipSize result in ErrZipTooLarge.
+func ReadZipFiles(data []byte, include func(name string) bool) ([]ZipFile, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open zip archive: %w", err)
+	}
+	files := make([]ZipFile, 0)
+	total := int64(0)
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() || strings.HasPrefix(file.Name, "__MACOSX/") || !include(file.Name) {
+			continue
+		}
+		// The declared size is checked first, the read is limited as the header may be wrong
+		if file.UncompressedSize64 > MaxZipFileSize {
+			return nil, fmt.Errorf("%w: %s exceeds %d bytes", ErrZipTooLarge, file.Name, MaxZipFileSize)
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s in zip archive: %w", file.Name, err)
+		}
+		content, err := io.ReadAll(io.LimitReader(rc, MaxZipFileSize+1))
+		_ = rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s in zip archive: %w", file.Name, err)
+		}
+		if len(content) > MaxZipFileSize {
+			return nil, fmt.Errorf("%w: %s exceeds %d bytes", ErrZipTooLarge, file.Name, MaxZipFileSize)
+		}
+		total += int64(len(content))
+		if total > MaxZipSize {
+			return nil, fmt.Errorf("%w: files exceed %d bytes in total", ErrZipTooLarge, MaxZipSize)
+		}
+		files = append(files, ZipFile{Name: file.Name, Content: content})
+	}
+	return files, nil
+}
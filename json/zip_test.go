@@ -0,0 +1,54 @@
+package json
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// zipArchive returns a zip archive of the files given by name and content
+func zipArchive(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := writer.Create(name)
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		_, _ = w.Write(content)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadZipFiles(t *testing.T) {
+	data := zipArchive(t, map[string][]byte{
+		"a.json":          []byte(`{}`),
+		"notes.txt":       []byte("skipped"),
+		"__MACOSX/a.json": []byte("skipped"),
+	})
+	files, err := ReadZipFiles(data, func(name string) bool { return strings.HasSuffix(name, ".json") })
+	if err != nil {
+		t.Fatalf("ReadZipFiles() error = %v", err)
+	}
+	if len(files) != 1 || files[0].Name != "a.json" || string(files[0].Content) != `{}` {
+		t.Errorf("ReadZipFiles() = %+v", files)
+	}
+
+	// The zeros compress to a small archive
+	data = zipArchive(t, map[string][]byte{"big.json": make([]byte, MaxZipFileSize+1)})
+	if len(data) > 1<<20 {
+		t.Fatalf("archive of %d bytes is not small", len(data))
+	}
+	if _, err := ReadZipFiles(data, func(string) bool { return true }); !errors.Is(err, ErrZipTooLarge) {
+		t.Errorf("ReadZipFiles() error = %v, want ErrZipTooLarge", err)
+	}
+	if err := NewSchemaBundle().AddZip(data); !errors.Is(err, ErrZipTooLarge) {
+		t.Errorf("AddZip() error = %v, want ErrZipTooLarge", err)
+	}
+}
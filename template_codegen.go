@@ -0,0 +1,77 @@
+package jsonedit
+
+const codegenFormTemplate = `
+<form method="post" enctype="multipart/form-data" id="form_codegen" hx-encoding="multipart/form-data">
+	<h2>Generate Types</h2>
+	<div>
+		<label for="language">Language:</label>
+		<select name="language" id="language">
+			<option value="go" selected>Go structs</option>
+			<option value="typescript">TypeScript interfaces</option>
+		</select>
+	</div>
+	<div>
+		<label for="source">Generate from:</label>
+		<select name="source" id="source">
+			<option value="schema" selected>JSON schema</option>
+			<option value="sample">Sample documents</option>
+		</select>
+	</div>
+	<h3>JSON Schema</h3>
+	<div>
+		<label for="schemaFile">JSON Schema Files:</label>
+		<input type="file" name="schemaFile" accept=".json,.zip" multiple>
+	</div>
+	<div>
+		<label for="schemaText">or paste the schema:</label>
+		<textarea name="schemaText" id="schemaText" rows="5" placeholder='{"type": "object"}'></textarea>
+	</div>
+	<div>
+		<label for="schemaMain">Main Schema:</label>
+		<input type="text" name="schemaMain" placeholder="first file if empty, e.g. schemas/main.json">
+	</div>
+	{{if .Workspace}}
+	<div>
+		<label for="schemaPath">Or Schema in Workspace:</label>
+		<input type="text" name="schemaPath" placeholder="e.g. schemas/main.json, its directory is loaded">
+	</div>
+	{{end}}
+	<h3>Sample Documents</h3>
+	<div>
+		<label for="sampleFiles">Sample documents:</label>
+		<input type="file" name="sampleFiles" accept=".json,.zip,.ndjson,.jsonl" multiple>
+	</div>
+	<div>
+		<label for="sampleText">or paste samples, one document or one per line:</label>
+		<textarea name="sampleText" id="sampleText" rows="5" placeholder='{"name": "John", "age": 30}'></textarea>
+	</div>
+	<h3>Options</h3>
+	<div>
+		<label for="rootName">Name of the root type:</label>
+		<input type="text" name="rootName" id="rootName" placeholder="title of the schema or Root">
+	</div>
+	<div>
+		<label for="packageName">Go package:</label>
+		<input type="text" name="packageName" id="packageName" placeholder="models">
+	</div>
+	<button form="form_codegen" type="submit" hx-post="/codegen" hx-swap="innerHTML" hx-target="#main">Generate</button>
+</form>
+`
+
+// Define template for the code generation result page
+const codegenResultTemplate = `
+<h1>Generated Types</h1>
+<div class="flatten-result">
+	<pre>{{.Code}}</pre>
+</div>
+<form id="form_codegen_download" action="/codegen/download" method="post">
+	<textarea name="code" hidden>{{.Code}}</textarea>
+	<input type="hidden" name="language" value="{{.Language}}">
+</form>
+<div class="button-container">
+	<button form="form_codegen_download" type="submit">Download</button>
+</div>
+<div style="margin-top: 20px;">
+	<button onclick="window.location.href='/'">Return to Home</button>
+</div>
+`
@@ -5,11 +5,39 @@ const compareFormTemplate = `
 	<h2>Compare Two JSON Files</h2>
 	<div>
 		<label for="jsonFile1">First JSON File:</label>
-		<input type="file" name="jsonFile1" accept=".json" required>
+		<input type="file" name="jsonFile1" accept=".json">
+	</div>
+	<div>
+		<label for="jsonText1">or paste the first document:</label>
+		<textarea name="jsonText1" id="jsonText1" rows="5"></textarea>
 	</div>
 	<div>
 		<label for="jsonFile2">Second JSON File:</label>
-		<input type="file" name="jsonFile2" accept=".json" required>
+		<input type="file" name="jsonFile2" accept=".json">
+	</div>
+	<div>
+		<label for="jsonText2">or paste the second document:</label>
+		<textarea name="jsonText2" id="jsonText2" rows="5"></textarea>
+	</div>
+	<div>
+		<label for="arrayKey">Match array elements by key:</label>
+		<input type="text" name="arrayKey" id="arrayKey" placeholder="id">
+	</div>
+	<div>
+		<label for="unorderedArrays">Treat arrays as unordered sets:</label>
+		<input type="checkbox" name="unorderedArrays" id="unorderedArrays" value="true">
+	</div>
+	<div>
+		<label for="ignorePaths">Ignore paths (one per line, * matches any key or index):</label>
+		<textarea name="ignorePaths" id="ignorePaths" rows="3" placeholder="/items/*/updatedAt"></textarea>
+	</div>
+	<div>
+		<label for="absoluteTolerance">Absolute numeric tolerance:</label>
+		<input type="text" name="absoluteTolerance" id="absoluteTolerance" placeholder="0.001">
+	</div>
+	<div>
+		<label for="relativeTolerance">Relative numeric tolerance:</label>
+		<input type="text" name="relativeTolerance" id="relativeTolerance" placeholder="0.01">
 	</div>
 	<button form="form_compare" type="submit" hx-post="/compare" hx-swap="innerHTML" hx-target="#main">Compare</button>
 </form>
@@ -17,8 +45,77 @@ const compareFormTemplate = `
 
 // Define template for the comparison result page
 const compareResultTemplate = `
+{{define "changes"}}
+<ul class="diff-tree">
+	{{range .}}
+	<li>
+		{{if .Type}}
+		<span class="diff-{{.Type}}">{{.Name}}: {{.Type}}
+			{{if eq .Type "added"}}{{.New}}{{else if eq .Type "removed"}}{{.Old}}{{else}}{{.Old}} &rarr; {{.New}}{{end}}
+		</span>
+		{{else}}
+		<span>{{.Name}}</span>
+		{{end}}
+		{{if .Children}}{{template "changes" .Children}}{{end}}
+	</li>
+	{{end}}
+</ul>
+{{end}}
+{{define "pane"}}
+	{{range $index, $block := .}}
+	{{if .Collapsed}}
+	<details class="diff-collapsed" data-block="{{$index}}" ontoggle="syncCollapse(this)">
+		<summary>{{len .Lines}} unchanged lines</summary>
+		{{range .Lines}}<div class="diff-line">{{.Text}}</div>{{end}}
+	</details>
+	{{else}}
+	{{range .Lines}}<div class="diff-line {{.Class}}">{{.Text}}</div>{{end}}
+	{{end}}
+	{{end}}
+{{end}}
 <h1>JSON Comparison Result</h1>
 <div class="comparison-result">
-	{{.ComparisonResult}}
+	{{if .Equal}}
+	No changes
+	{{else}}
+	<p>
+		<span class="diff-added">{{.Added}} added</span>,
+		<span class="diff-removed">{{.Removed}} removed</span>,
+		<span class="diff-modified">{{.Modified}} modified</span>,
+		<span class="diff-type-changed">{{.TypeChanged}} type changed</span>
+	</p>
+	{{template "changes" .Tree}}
+	{{end}}
+	{{if .Suppressed}}
+	<p>{{.Suppressed}} differences suppressed by ignore paths or numeric tolerance</p>
+	{{end}}
+</div>
+<div class="diff-panes">
+	<div class="diff-pane" id="diffLeft" onscroll="syncScroll(this, 'diffRight')">
+		{{template "pane" .Left}}
+	</div>
+	<div class="diff-pane" id="diffRight" onscroll="syncScroll(this, 'diffLeft')">
+		{{template "pane" .Right}}
+	</div>
 </div>
+<form id="form_compare_export" action="/compare/export" method="post">
+	<textarea name="first" hidden>{{.First}}</textarea>
+	<textarea name="second" hidden>{{.Second}}</textarea>
+	<input type="hidden" name="arrayKey" value="{{.ArrayKey}}">
+	{{if .UnorderedArrays}}<input type="hidden" name="unorderedArrays" value="true">{{end}}
+	<textarea name="ignorePaths" hidden>{{.IgnorePaths}}</textarea>
+	<input type="hidden" name="absoluteTolerance" value="{{.AbsoluteTolerance}}">
+	<input type="hidden" name="relativeTolerance" value="{{.RelativeTolerance}}">
+	<div>
+		<label for="format">Export format:</label>
+		<select name="format" id="format">
+			<option value="changes">Changes (JSON)</option>
+			<option value="json-patch">JSON Patch (RFC 6902)</option>
+			<option value="merge-patch">Merge Patch (RFC 7386)</option>
+		</select>
+	</div>
+	<div class="button-container">
+		<button form="form_compare_export" type="submit">Export</button>
+	</div>
+</form>
 `
@@ -11,17 +11,55 @@ const editPageFormTemplate = `
 // Define template for the edit page
 const editPageTemplate = `
 <h1>Edit JSON</h1>
+{{if .Path}}
+<p class="file-path">{{.Path}}</p>
+{{end}}
 {{if .Error}}
 <p class="error">{{.Error}}</p>
 {{end}}
-<form id="editForm" action="/save" method="post" onsubmit="return updateJSONContent()">
-	<div id="jsonFields">
+{{if .Message}}
+<p class="message">{{.Message}}</p>
+{{end}}
+<form id="editForm" action="/save" method="post" onsubmit="return confirmSave()"{{if .BlockInvalidSave}} data-block-invalid-save="true"{{end}}>
+	<div id="jsonFields"{{if .Highlight}} data-highlight="{{.Highlight}}"{{end}}>
 		{{.FormContent}}
 	</div>
 	<textarea name="jsonContent" id="jsonContent" class="hidden">{{.Content}}</textarea>
+	<textarea name="edits" id="editsContent" class="hidden"></textarea>
+	<textarea name="schema" id="schemaContent" class="hidden">{{.Schema}}</textarea>
+	<div class="schema-attachment">
+		<label for="schemaFile">JSON Schema:</label>
+		<input type="file" id="schemaFile" accept=".json" onchange="attachSchema(this)">
+		<button type="button" onclick="detachSchema()">Detach schema</button>
+	</div>
+	<div id="validationStatus" hx-post="/edit/validate" hx-trigger="load, validate" hx-include="#schemaContent, #jsonContent" hx-vals="js:{edits: currentEdits()}" hx-swap="innerHTML"></div>
+	{{if .Path}}
+	<input type="hidden" name="filePath" value="{{.Path}}">
+	{{end}}
 	{{if not .ReadOnly}}
+	{{if .Path}}
+	<button type="button" onclick="updateJSONContent()" hx-post="/save-file" hx-swap="innerHTML" hx-target="#main">Save to File</button>
+	{{end}}
 	<button type="submit">Save and Download</button>
 	{{end}}
 	<button type="button" onclick="window.location.href='/'">Return to Home</button>
 </form>
 `
+
+// Define template for the validation status of the edit page
+const editValidationTemplate = `
+{{if .Error}}
+<p class="error" data-valid="false">{{.Error}}</p>
+{{else if .Violations}}
+<div class="error" data-valid="false">
+	<p>The document does not satisfy the schema:</p>
+	<ul class="violation-list">
+	{{range .Violations}}
+		<li{{if .Field}} data-field="{{.Field}}"{{end}} data-message="{{.Message}}">{{if .Pointer}}{{.Pointer}}{{else}}/{{end}}: {{.Message}}</li>
+	{{end}}
+	</ul>
+</div>
+{{else}}
+<p class="message" data-valid="true">The document satisfies the schema.</p>
+{{end}}
+`
@@ -4,10 +4,46 @@ const flattenFormTemplate = `
 <form method="post" enctype="multipart/form-data" id="form_flatten" hx-encoding="multipart/form-data">
 	<h2>Flatten JSON File</h2>
 	<div>
-		<label for="jsonFileFlat">JSON File:</label>
-		<input type="file" name="jsonFileFlat" accept=".json" required>
+		<label for="mode">Mode:</label>
+		<select name="mode" id="mode">
+			<option value="flatten">Flatten JSON</option>
+			<option value="unflatten">Unflatten to JSON</option>
+		</select>
 	</div>
-	<button form="form_compare" type="submit" hx-post="/flatten" hx-swap="innerHTML" hx-target="#main">Flatten</button>
+	<div>
+		<label for="jsonFileFlat">JSON or flattened file:</label>
+		<input type="file" name="jsonFileFlat" accept=".json,.txt,.csv,.properties">
+	</div>
+	<div>
+		<label for="flatText">or paste the content:</label>
+		<textarea name="flatText" id="flatText" rows="5" placeholder="address.city: Anytown"></textarea>
+	</div>
+	<div>
+		<label for="keyStyle">Key style:</label>
+		<select name="keyStyle" id="keyStyle">
+			<option value="dotted">Dotted (address.phones/0)</option>
+			<option value="pointer">JSON Pointer (/address/phones/0)</option>
+			<option value="bracket">Brackets (address.phones[0])</option>
+		</select>
+	</div>
+	<div>
+		<label for="escapeKeys">Escape separators in keys:</label>
+		<input type="checkbox" name="escapeKeys" id="escapeKeys" value="true">
+	</div>
+	<div>
+		<label for="quoteStrings">Quote strings to keep their type:</label>
+		<input type="checkbox" name="quoteStrings" id="quoteStrings" value="true">
+	</div>
+	<div>
+		<label for="format">Format:</label>
+		<select name="format" id="format">
+			<option value="lines">Property lines</option>
+			<option value="csv">CSV (path, type, value)</option>
+			<option value="properties">Java .properties</option>
+			<option value="env">Env file (KEY=VALUE)</option>
+		</select>
+	</div>
+	<button form="form_flatten" type="submit" hx-post="/flatten" hx-swap="innerHTML" hx-target="#main">Convert</button>
 </form>
 `
 
@@ -17,6 +53,16 @@ const flattenResultTemplate = `
 <div class="flatten-result">
 	<pre>{{.FlattenResult}}</pre>
 </div>
+<form id="form_flatten_download" action="/flatten/download" method="post">
+	<textarea name="document" hidden>{{.Document}}</textarea>
+	<input type="hidden" name="keyStyle" value="{{.KeyStyle}}">
+	{{if .EscapeKeys}}<input type="hidden" name="escapeKeys" value="true">{{end}}
+	{{if .QuoteStrings}}<input type="hidden" name="quoteStrings" value="true">{{end}}
+	<input type="hidden" name="format" value="{{.Format}}">
+	<div class="button-container">
+		<button form="form_flatten_download" type="submit">Download</button>
+	</div>
+</form>
 <div style="margin-top: 20px;">
 	<button onclick="window.location.href='/'">Return to Home</button>
 </div>
@@ -4,9 +4,68 @@ const fromSchemaFormTemplate = `
 <form action="/from-schema" method="post" enctype="multipart/form-data" id="form_from_schema" hx-encoding="multipart/form-data">
 	<h2>Generate JSON from Schema</h2>
 	<div>
-		<label for="schemaFile">JSON Schema File:</label>
-		<input type="file" name="schemaFile" accept=".json" required>
+		<label for="schemaFile">JSON Schema Files:</label>
+		<input type="file" name="schemaFile" accept=".json,.zip" multiple>
 	</div>
+	<div>
+		<label for="schemaText">or paste the schema:</label>
+		<textarea name="schemaText" id="schemaText" rows="5" placeholder='{"type": "object"}'></textarea>
+	</div>
+	<div>
+		<label for="schemaMain">Main Schema:</label>
+		<input type="text" name="schemaMain" placeholder="first file if empty, e.g. schemas/main.json">
+	</div>
+	{{if .Workspace}}
+	<div>
+		<label for="schemaPath">Or Schema in Workspace:</label>
+		<input type="text" name="schemaPath" placeholder="e.g. schemas/main.json, its directory is loaded">
+	</div>
+	{{end}}
+	<div>
+		<label for="mode">Generate:</label>
+		<select name="mode" id="mode">
+			<option value="empty" selected>One document with the required fields</option>
+			<option value="random">Random documents</option>
+		</select>
+	</div>
+	<div>
+		<label for="count">Number of random documents:</label>
+		<input type="number" name="count" id="count" value="10" min="1" max="{{.MaxDocuments}}">
+	</div>
+	<div>
+		<label for="seed">Seed, the same seed generates the same documents:</label>
+		<input type="number" name="seed" id="seed" min="0" placeholder="random if empty">
+	</div>
+	<div>
+		<label for="optional">Include optional properties:</label>
+		<input type="checkbox" name="optional" id="optional" value="true">
+	</div>
+	<div>
+		<label for="output">Download random documents as:</label>
+		<select name="output" id="output">
+			<option value="json" selected>JSON array</option>
+			<option value="ndjson">NDJSON</option>
+		</select>
+	</div>
+	<div id="schemaChoices"></div>
+	<button type="button" hx-post="/from-schema/choices" hx-swap="innerHTML" hx-target="#schemaChoices">Choose oneOf/anyOf branches</button>
 	<button form="form_from_schema" type="submit" hx-redirect="/from-schema">Generate</button>
 </form>
 `
+
+// Define template for the oneOf and anyOf branches selectable on the from-schema page
+const fromSchemaChoicesTemplate = `
+{{range .}}
+{{$choice := .}}
+<div>
+	<label title="{{.Location}}">{{.Keyword}} at {{.Location}}:</label>
+	<select name="branch">
+		{{range $index, $branch := .Branches}}
+		<option value="{{$choice.Location}}={{$index}}" {{if eq $index $choice.Selected}}selected{{end}}>{{$branch}}</option>
+		{{end}}
+	</select>
+</div>
+{{else}}
+<p>The schema has no oneOf or anyOf, there are no branches to choose.</p>
+{{end}}
+`
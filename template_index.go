@@ -39,14 +39,21 @@ const indexTemplate = `
 				<div hx-get="/upload" hx-swap="innerHTML" hx-target="#main">Edit existing JSON</div>
 				<div hx-get="/new" hx-swap="innerHTML" hx-target="#main">New JSON object</div>
 				<div hx-get="/new-array" hx-swap="innerHTML" hx-target="#main">New JSON array</div>
+				{{if .Workspace}}
+				<div hx-get="/files" hx-swap="innerHTML" hx-target="#main">Workspace files</div>
+				{{end}}
 				<h2>Functions</h2>
 				<h3>Functionality</h3>
 				<div hx-get="/compare" hx-swap="innerHTML" hx-target="#main">Compare documents</div>
+				<div hx-get="/patch" hx-swap="innerHTML" hx-target="#main">Apply patch</div>
+				<div hx-get="/merge" hx-swap="innerHTML" hx-target="#main">Merge documents</div>
 				<div hx-get="/flatten" hx-swap="innerHTML" hx-target="#main">Flatten document</div>
 				<div hx-get="/csv2json" hx-swap="innerHTML" hx-target="#main">CSV to JSON</div>
 				<h3>Schema</h3>
 				<div hx-get="/from-schema" hx-swap="innerHTML" hx-target="#main">JSON Document from schema</div>
 				<div hx-get="/validate" hx-swap="innerHTML" hx-target="#main">Validate JSON document</div>
+				<div hx-get="/infer-schema" hx-swap="innerHTML" hx-target="#main">Infer schema from samples</div>
+				<div hx-get="/codegen" hx-swap="innerHTML" hx-target="#main">Generate Go/TypeScript types</div>
             </div>
             <div class="column main" id="main">
                 <!-- Second column content -->
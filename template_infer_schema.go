@@ -0,0 +1,60 @@
+package jsonedit
+
+const inferSchemaFormTemplate = `
+<form method="post" enctype="multipart/form-data" id="form_infer_schema" hx-encoding="multipart/form-data">
+	<h2>Infer JSON Schema from Samples</h2>
+	<div>
+		<label for="sampleFiles">Sample documents:</label>
+		<input type="file" name="sampleFiles" accept=".json,.zip,.ndjson,.jsonl" multiple>
+	</div>
+	<div>
+		<label for="sampleText">or paste samples, one document or one per line:</label>
+		<textarea name="sampleText" id="sampleText" rows="5" placeholder='{"name": "John", "age": 30}'></textarea>
+	</div>
+	<div>
+		<label for="enumLimit">Maximum distinct values of an enum (0 disables enums):</label>
+		<input type="number" name="enumLimit" id="enumLimit" value="5" min="0">
+	</div>
+	<div>
+		<label for="detectFormats">Detect formats (date-time, date, uuid, email):</label>
+		<input type="checkbox" name="detectFormats" id="detectFormats" value="true" checked>
+	</div>
+	<button form="form_infer_schema" type="submit" hx-post="/infer-schema" hx-swap="innerHTML" hx-target="#main">Infer</button>
+</form>
+`
+
+// Define template for the schema inference result page
+const inferSchemaResultTemplate = `
+<h1>Inferred JSON Schema</h1>
+<p>Inferred from {{.Samples}} sample(s)</p>
+<div class="flatten-result">
+	<pre>{{.Schema}}</pre>
+</div>
+<form action="/validate" method="post" enctype="multipart/form-data" id="form_infer_validate" hx-encoding="multipart/form-data">
+	<h2>Validate documents with this schema</h2>
+	<textarea name="schemaText" hidden>{{.Schema}}</textarea>
+	<div>
+		<label for="jsonFileValidate">JSON Documents:</label>
+		<input type="file" name="jsonFileValidate" accept=".json,.zip,.ndjson,.jsonl" multiple required>
+	</div>
+	<button form="form_infer_validate" type="submit" hx-post="/validate" hx-swap="innerHTML" hx-target="#main">Validate</button>
+</form>
+<form action="/from-schema" method="post" enctype="multipart/form-data" id="form_infer_from_schema">
+	<textarea name="schemaText" hidden>{{.Schema}}</textarea>
+</form>
+<form id="form_infer_edit" method="post" action="/edit">
+	<textarea name="jsonContent" hidden>{{.Sample}}</textarea>
+	<textarea name="schema" hidden>{{.Schema}}</textarea>
+</form>
+<form id="form_infer_download" action="/infer-schema/download" method="post">
+	<textarea name="schema" hidden>{{.Schema}}</textarea>
+</form>
+<div class="button-container">
+	<button form="form_infer_download" type="submit">Download schema</button>
+	<button form="form_infer_from_schema" type="submit">Generate document from schema</button>
+	<button form="form_infer_edit" type="submit" hx-post="/edit" hx-swap="innerHTML" hx-target="#main">Edit first sample with schema</button>
+</div>
+<div style="margin-top: 20px;">
+	<button onclick="window.location.href='/'">Return to Home</button>
+</div>
+`
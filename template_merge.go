@@ -0,0 +1,50 @@
+package jsonedit
+
+// Define template for the merge form
+const mergeFormTemplate = `
+<form method="post" enctype="multipart/form-data" id="form_merge" hx-encoding="multipart/form-data">
+	<h2>Three-way Merge of JSON Files</h2>
+	<div>
+		<label for="jsonFileBase">Common base:</label>
+		<input type="file" name="jsonFileBase" accept=".json" required>
+	</div>
+	<div>
+		<label for="jsonFileOurs">Ours:</label>
+		<input type="file" name="jsonFileOurs" accept=".json" required>
+	</div>
+	<div>
+		<label for="jsonFileTheirs">Theirs:</label>
+		<input type="file" name="jsonFileTheirs" accept=".json" required>
+	</div>
+	<button form="form_merge" type="submit" hx-post="/merge" hx-swap="innerHTML" hx-target="#main">Merge</button>
+</form>
+`
+
+// Define template for the conflict resolution page
+const mergeConflictsTemplate = `
+<h1>Merge Conflicts</h1>
+<p>{{len .Conflicts}} values were changed differently in both documents, pick the side to keep for each of them.</p>
+<form id="form_merge_resolve">
+	<textarea name="base" hidden>{{.Base}}</textarea>
+	<textarea name="ours" hidden>{{.Ours}}</textarea>
+	<textarea name="theirs" hidden>{{.Theirs}}</textarea>
+	{{range $i, $conflict := .Conflicts}}
+	<div class="merge-conflict">
+		<h3>{{if $conflict.Path}}{{$conflict.Path}}{{else}}(document){{end}}</h3>
+		<input type="hidden" name="conflictPath{{$i}}" value="{{$conflict.Path}}">
+		<p>Base: <code>{{$conflict.Base}}</code></p>
+		<div>
+			<input type="radio" name="resolution{{$i}}" id="ours{{$i}}" value="ours" checked>
+			<label for="ours{{$i}}">Ours: <code class="diff-modified">{{$conflict.Ours}}</code></label>
+		</div>
+		<div>
+			<input type="radio" name="resolution{{$i}}" id="theirs{{$i}}" value="theirs">
+			<label for="theirs{{$i}}">Theirs: <code class="diff-modified">{{$conflict.Theirs}}</code></label>
+		</div>
+	</div>
+	{{end}}
+	<div class="button-container">
+		<button form="form_merge_resolve" type="submit" hx-post="/merge/resolve" hx-swap="innerHTML" hx-target="#main">Open merged document</button>
+	</div>
+</form>
+`
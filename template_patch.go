@@ -0,0 +1,27 @@
+package jsonedit
+
+// Define template for the patch form
+const patchFormTemplate = `
+<form method="post" enctype="multipart/form-data" id="form_patch" hx-encoding="multipart/form-data">
+	<h2>Apply Patch to JSON File</h2>
+	{{if .Error}}
+	<div class="error">{{.Error}}</div>
+	{{end}}
+	<div>
+		<label for="jsonFilePatch">JSON File:</label>
+		<input type="file" name="jsonFilePatch" accept=".json" required>
+	</div>
+	<div>
+		<label for="patchFile">Patch File:</label>
+		<input type="file" name="patchFile" accept=".json" required>
+	</div>
+	<div>
+		<label for="patchType">Patch format:</label>
+		<select name="patchType" id="patchType">
+			<option value="json-patch">JSON Patch (RFC 6902)</option>
+			<option value="merge-patch">Merge Patch (RFC 7386)</option>
+		</select>
+	</div>
+	<button form="form_patch" type="submit" hx-post="/patch" hx-swap="innerHTML" hx-target="#main">Apply</button>
+</form>
+`
@@ -4,12 +4,45 @@ const validateFormTemplate = `
 <form action="/validate" method="post" enctype="multipart/form-data" id="form_validate" hx-encoding="multipart/form-data">
 	<h2>Validate JSON against Schema</h2>
 	<div>
-		<label for="schemaFileValidate">JSON Schema File:</label>
-		<input type="file" name="schemaFileValidate" accept=".json" required>
+		<label for="schemaFileValidate">JSON Schema Files:</label>
+		<input type="file" name="schemaFileValidate" accept=".json,.zip" multiple>
 	</div>
 	<div>
-		<label for="jsonFileValidate">JSON Document:</label>
-		<input type="file" name="jsonFileValidate" accept=".json" required>
+		<label for="schemaText">or paste the schema:</label>
+		<textarea name="schemaText" id="schemaText" rows="5" placeholder='{"type": "object"}'></textarea>
+	</div>
+	<div>
+		<label for="schemaMain">Main Schema:</label>
+		<input type="text" name="schemaMain" placeholder="first file if empty, e.g. schemas/main.json">
+	</div>
+	{{if .Workspace}}
+	<div>
+		<label for="schemaPath">Or Schema in Workspace:</label>
+		<input type="text" name="schemaPath" placeholder="e.g. schemas/main.json, its directory is loaded">
+	</div>
+	{{end}}
+	<div>
+		<label for="jsonFileValidate">JSON Documents:</label>
+		<input type="file" name="jsonFileValidate" accept=".json,.zip,.ndjson,.jsonl" multiple required>
+	</div>
+	<div>
+		<label for="draft">Draft for schemas without $schema:</label>
+		<select name="draft" id="draft">
+			<option value="">Default (2020-12)</option>
+			<option value="draft-04">Draft 4</option>
+			<option value="draft-06">Draft 6</option>
+			<option value="draft-07">Draft 7</option>
+			<option value="2019-09">2019-09</option>
+			<option value="2020-12">2020-12</option>
+		</select>
+	</div>
+	<div>
+		<label for="assertFormat">Validate formats:</label>
+		<input type="checkbox" name="assertFormat" id="assertFormat" value="true">
+	</div>
+	<div>
+		<label for="assertContent">Validate content encoding and media type:</label>
+		<input type="checkbox" name="assertContent" id="assertContent" value="true">
 	</div>
 	<button form="form_validate" type="submit" hx-post="/validate" hx-swap="innerHTML" hx-target="#main">Validate</button>
 </form>
@@ -24,6 +57,33 @@ const validateResultTemplate = `
 			<h2>Validation Error</h2>
 			<pre>{{.Error}}</pre>
 		</div>
+	{{else if .Violations}}
+		<div class="error-message">
+			<h2>{{len .Violations}} Violation(s)</h2>
+			<form id="form_validate_edit" method="post" action="/edit">
+				<textarea name="jsonContent" hidden>{{.Document}}</textarea>
+				<table class="violations">
+					<thead>
+						<tr>
+							<th>Instance location</th>
+							<th>Keyword</th>
+							<th>Schema location</th>
+							<th>Message</th>
+						</tr>
+					</thead>
+					<tbody>
+					{{range .Violations}}
+						<tr>
+							<td><button type="submit" class="link-button" name="highlight" value="{{.InstanceLocation}}" hx-post="/edit" hx-swap="innerHTML" hx-target="#main">{{if .InstanceLocation}}{{.InstanceLocation}}{{else}}/{{end}}</button></td>
+							<td>{{.Keyword}}</td>
+							<td title="{{.AbsoluteKeywordLocation}}">{{.KeywordLocation}}</td>
+							<td>{{.Message}}</td>
+						</tr>
+					{{end}}
+					</tbody>
+				</table>
+			</form>
+		</div>
 	{{else}}
 		<div class="success-message">
 			<h2>Valid JSON</h2>
@@ -32,3 +92,58 @@ const validateResultTemplate = `
 	{{end}}
 </div>
 `
+
+// Define template for the batch validation result page
+const validateBatchResultTemplate = `
+<h1>JSON Validation Result</h1>
+<div class="validation-result">
+	<div class="{{if .Result.Failed}}error-message{{else}}success-message{{end}}">
+		<h2>{{.Result.Passed}} of {{.Result.Total}} document(s) valid, {{.Result.Failed}} failed</h2>
+		<p>Validated against {{.Schema}}</p>
+	</div>
+	<table class="violations">
+		<thead>
+			<tr>
+				<th>Document</th>
+				<th>Result</th>
+				<th>Violations</th>
+			</tr>
+		</thead>
+		<tbody>
+		{{range .Result.Documents}}
+			<tr>
+				<td>{{.Name}}</td>
+				<td>{{if .Valid}}valid{{else}}invalid{{end}}</td>
+				<td>
+					{{if .Error}}<pre>{{.Error}}</pre>{{end}}
+					{{if .Violations}}
+					<ul>
+						{{range .Violations}}
+						<li>at '{{if .InstanceLocation}}{{.InstanceLocation}}{{else}}/{{end}}' [{{.Keyword}}]: {{.Message}}</li>
+						{{end}}
+					</ul>
+					{{end}}
+				</td>
+			</tr>
+		{{end}}
+		</tbody>
+	</table>
+</div>
+<form id="form_validate_export" action="/validate/export" method="post">
+	<textarea name="result" hidden>{{.Export}}</textarea>
+	<input type="hidden" name="schema" value="{{.Schema}}">
+	<div>
+		<label for="format">Export format:</label>
+		<select name="format" id="format">
+			<option value="json">JSON</option>
+			<option value="junit">JUnit XML</option>
+		</select>
+	</div>
+	<div class="button-container">
+		<button form="form_validate_export" type="submit">Export</button>
+	</div>
+</form>
+<div style="margin-top: 20px;">
+	<button onclick="window.location.href='/'">Return to Home</button>
+</div>
+`
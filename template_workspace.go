@@ -0,0 +1,33 @@
+package jsonedit
+
+// Define template for the workspace file tree
+const filesTemplate = `
+{{define "tree"}}
+<ul class="file-tree">
+	{{range .}}
+	<li>
+		{{if .IsDir}}
+		<span class="file-tree-dir">{{.Name}}/</span>
+		{{template "tree" .Children}}
+		{{else}}
+		<span class="file-tree-file" hx-get="/open?path={{.Path | urlquery}}" hx-swap="innerHTML" hx-target="#main">{{.Name}}</span>
+		{{end}}
+	</li>
+	{{end}}
+</ul>
+{{end}}
+<h1>Workspace</h1>
+<p>{{.Root}}</p>
+<form id="form_open" hx-get="/open" hx-swap="innerHTML" hx-target="#main">
+	<div>
+		<label for="path">Open file by path:</label>
+		<input type="text" name="path" required>
+	</div>
+	<button form="form_open" type="submit">Open</button>
+</form>
+{{if .Entries}}
+{{template "tree" .Entries}}
+{{else}}
+<p>No files found</p>
+{{end}}
+`
@@ -10,6 +10,9 @@ import (
 	"time"
 
 	"github.com/doganarif/govisual"
+
+	"github.com/sascha-andres/jsonedit/internal/workspace"
+	"github.com/sascha-andres/jsonedit/json/validate"
 )
 
 type (
@@ -27,6 +30,180 @@ type (
 
 		// ReadOnly indicates whether the content or form should be displayed in a non-editable mode.
 		ReadOnly bool
+
+		// Path is the workspace relative path of the file being edited, empty if the content is not backed by a file.
+		Path string
+
+		// Message represents an optional informational message to be displayed on the page.
+		Message string
+
+		// Highlight is the path of the form field to highlight, empty if no field is highlighted.
+		Highlight string
+
+		// Schema is the JSON schema attached to the edit session, empty if the document is not validated.
+		Schema string
+
+		// BlockInvalidSave indicates whether saving is refused while the document does not satisfy the schema.
+		BlockInvalidSave bool
+	}
+
+	// EditValidationData holds the data rendered as validation status of the edit page
+	EditValidationData struct {
+
+		// Error is the message of a failure other than schema violations, e.g. a schema that does not compile.
+		Error string
+
+		// Violations lists the failed schema keywords, empty if the document is valid.
+		Violations []editViolation
+	}
+
+	// ValidateBatchResultData holds the data rendered on the batch validation result page
+	ValidateBatchResultData struct {
+
+		// Schema is the name of the main schema the documents were validated against.
+		Schema string
+
+		// Result holds the summary and the result of every document.
+		Result *validate.BatchResult
+
+		// Export is the result encoded as JSON, posted back to export it.
+		Export string
+	}
+
+	// SchemaFormData holds the data rendered on forms reading a JSON schema
+	SchemaFormData struct {
+
+		// Workspace indicates whether workspace mode is enabled, allowing to read schemas from the workspace.
+		Workspace bool
+
+		// MaxDocuments is the maximum number of random documents generated at once.
+		MaxDocuments int
+	}
+
+	// ValidateResultData holds the data rendered on the validation result page
+	ValidateResultData struct {
+
+		// Error is the message of a failure other than schema violations, e.g. an invalid document.
+		Error string
+
+		// Violations lists the failed schema keywords, empty if the document is valid.
+		Violations []validate.Violation
+
+		// Document is the validated JSON document, kept to open it in the editor.
+		Document string
+	}
+
+	// CompareResultData holds the data rendered on the comparison result page
+	CompareResultData struct {
+
+		// Equal indicates whether no differences were found.
+		Equal bool
+
+		// Added is the number of values only present in the second document.
+		Added int
+
+		// Removed is the number of values only present in the first document.
+		Removed int
+
+		// Modified is the number of scalar values that differ.
+		Modified int
+
+		// TypeChanged is the number of values whose JSON type differs.
+		TypeChanged int
+
+		// Tree is the list of changes arranged by their path.
+		Tree []*changeTreeNode
+
+		// Left holds the lines of the first document for the side-by-side view.
+		Left []diffBlock
+
+		// Right holds the lines of the second document for the side-by-side view.
+		Right []diffBlock
+
+		// First is the first document, kept to export the comparison.
+		First string
+
+		// Second is the second document, kept to export the comparison.
+		Second string
+
+		// ArrayKey is the member used to match array elements, kept to export the comparison.
+		ArrayKey string
+
+		// UnorderedArrays indicates whether arrays were compared as unordered sets, kept to export the comparison.
+		UnorderedArrays bool
+
+		// IgnorePaths holds the ignore patterns, one per line, kept to export the comparison.
+		IgnorePaths string
+
+		// AbsoluteTolerance is the absolute numeric tolerance, kept to export the comparison.
+		AbsoluteTolerance string
+
+		// RelativeTolerance is the relative numeric tolerance, kept to export the comparison.
+		RelativeTolerance string
+
+		// Suppressed is the number of differences hidden by ignore patterns or the numeric tolerance.
+		Suppressed int
+	}
+
+	// MergeConflictsData holds the data rendered on the merge conflict resolution page
+	MergeConflictsData struct {
+
+		// Conflicts lists the values changed differently in both edited documents.
+		Conflicts []mergeConflictView
+
+		// Base is the common base document, kept to merge again with the resolutions.
+		Base string
+
+		// Ours is our edited document, kept to merge again with the resolutions.
+		Ours string
+
+		// Theirs is their edited document, kept to merge again with the resolutions.
+		Theirs string
+	}
+
+	// InferSchemaResultData holds the data rendered on the schema inference result page
+	InferSchemaResultData struct {
+
+		// Schema is the inferred JSON schema.
+		Schema string
+
+		// Sample is the first sample document, kept to open it in the editor with the schema.
+		Sample string
+
+		// Samples is the number of sample documents the schema was inferred from.
+		Samples int
+	}
+
+	// CodegenResultData holds the data rendered on the code generation result page
+	CodegenResultData struct {
+
+		// Code is the generated source code.
+		Code string
+
+		// Language is the language of the code, kept to name the downloaded file.
+		Language string
+	}
+
+	// FlattenResultData holds the data rendered on the flatten result page
+	FlattenResultData struct {
+
+		// FlattenResult is the flattened document in the selected format.
+		FlattenResult string
+
+		// Document is the flattened JSON document, kept to download the result.
+		Document string
+
+		// KeyStyle is the selected style of the paths.
+		KeyStyle string
+
+		// EscapeKeys indicates whether separators within object keys are escaped.
+		EscapeKeys bool
+
+		// QuoteStrings indicates whether strings are written as quoted JSON strings.
+		QuoteStrings bool
+
+		// Format is the selected output format.
+		Format string
 	}
 
 	// App represents the core application with configuration options such as host, port, JSON indentation, and logging.
@@ -52,6 +229,15 @@ type (
 
 		// noBrowser indicates whether the application should open the default browser after startup.
 		noBrowser bool
+
+		// workspace provides access to files on the local disk, nil if workspace mode is disabled.
+		workspace *workspace.Workspace
+
+		// backup indicates whether a .bak copy is kept when a workspace file is overwritten.
+		backup bool
+
+		// blockInvalidSave indicates whether saving a document not satisfying its attached schema is refused instead of only warned about.
+		blockInvalidSave bool
 	}
 
 	// AppOption represents a function that configures an App instance and may return an error during the setup process.
@@ -134,6 +320,39 @@ func WithIndent(indent string) AppOption {
 	}
 }
 
+// WithRoot enables workspace mode, allowing files below root to be listed, opened and saved.
+// An empty root leaves workspace mode disabled.
+func WithRoot(root string) AppOption {
+	return func(app *App) error {
+		if root == "" {
+			return nil
+		}
+		ws, err := workspace.New(root)
+		if err != nil {
+			return err
+		}
+		app.workspace = ws
+		return nil
+	}
+}
+
+// WithBackup sets whether a .bak copy of a workspace file is kept when it is overwritten.
+func WithBackup(backup bool) AppOption {
+	return func(app *App) error {
+		app.backup = backup
+		return nil
+	}
+}
+
+// WithBlockInvalidSave sets whether saving a document that does not satisfy the schema attached in the editor is refused.
+// Without it the editor only warns before saving.
+func WithBlockInvalidSave(block bool) AppOption {
+	return func(app *App) error {
+		app.blockInvalidSave = block
+		return nil
+	}
+}
+
 // openBrowser opens the default browser with the specified URL.
 func openBrowser(url string) {
 	var err error
@@ -166,14 +385,39 @@ func (app *App) Run() error {
 	mux.HandleFunc("/upload", app.handleUpload)
 	mux.HandleFunc("/save", app.handleSave)
 	mux.HandleFunc("/edit", app.handleEdit)
+	mux.HandleFunc("/edit/validate", app.handleEditValidate)
 	mux.HandleFunc("/new", app.handleNewObject)
 	mux.HandleFunc("/new-array", app.handleNewArray)
 	mux.HandleFunc("/compare", app.handleCompare)
+	mux.HandleFunc("/compare/export", app.handleCompareExport)
+	mux.HandleFunc("/patch", app.handlePatch)
+	mux.HandleFunc("/merge", app.handleMerge)
+	mux.HandleFunc("/merge/resolve", app.handleMergeResolve)
 	mux.HandleFunc("/flatten", app.handleFlatten)
+	mux.HandleFunc("/flatten/download", app.handleFlattenDownload)
 	mux.HandleFunc("/from-schema", app.handleFromSchema)
+	mux.HandleFunc("/from-schema/choices", app.handleFromSchemaChoices)
 	mux.HandleFunc("/validate", app.handleValidate)
+	mux.HandleFunc("/validate/export", app.handleValidateExport)
+	mux.HandleFunc("/infer-schema", app.handleInferSchema)
+	mux.HandleFunc("/infer-schema/download", app.handleInferSchemaDownload)
+	mux.HandleFunc("/codegen", app.handleCodegen)
+	mux.HandleFunc("/codegen/download", app.handleCodegenDownload)
 	mux.HandleFunc("/csv2json", app.handleCSV2JSON)
-
+	mux.HandleFunc("/files", app.handleFiles)
+	mux.HandleFunc("/open", app.handleOpen)
+	mux.HandleFunc("/save-file", app.handleSaveFile)
+
+	mux.HandleFunc("/api/v1/openapi.json", app.handleAPIOpenAPI)
+	mux.HandleFunc("/api/v1/compare", app.handleAPICompare)
+	mux.HandleFunc("/api/v1/flatten", app.handleAPIFlatten)
+	mux.HandleFunc("/api/v1/validate", app.handleAPIValidate)
+	mux.HandleFunc("/api/v1/from-schema", app.handleAPIFromSchema)
+	mux.HandleFunc("/api/v1/csv2json", app.handleAPICSV2JSON)
+
+	if app.workspace != nil {
+		app.logger.Info("workspace mode enabled", "root", app.workspace.Root(), "backup", app.backup)
+	}
 	app.logger.Info("server starting", "host", app.host, "port", app.port)
 
 	if !app.noBrowser {